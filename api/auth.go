@@ -0,0 +1,366 @@
+package main
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	// 🔒 Security package
+	"bruno-api/security"
+)
+
+// =============================================================================
+// 🔐 ADMIN AUTHENTICATION
+// =============================================================================
+
+const (
+	adminUserContextKey    = "admin_user"
+	adminMinPasswordLength = 12
+	// adminAPIKeyUsername identifies requests authenticated with an admin API key
+	adminAPIKeyUsername = "api-key"
+)
+
+// publicWriteRoutes are the mutating routes visitors use without an account.
+// Every other POST, PUT, PATCH and DELETE route requires authentication.
+var publicWriteRoutes = map[string]bool{
+	"/api/v1/auth/login":      true,
+	"/api/v1/chat":            true,
+	"/api/v1/chat/leads":      true,
+	"/api/v1/match":           true,
+	"/api/v1/resume/tailor":   true,
+	"/api/v1/analytics/track": true,
+	"/api/chat":               true,
+	"/api/match":              true,
+	"/api/resume/tailor":      true,
+	"/api/analytics/track":    true,
+}
+
+// unknownUserHash is checked against for unknown usernames so failed logins
+// take as long whether or not the account exists
+var (
+	unknownUserHash     string
+	unknownUserHashOnce sync.Once
+)
+
+// initAdminAuth creates the first admin account from ADMIN_BOOTSTRAP_USERNAME
+// and ADMIN_BOOTSTRAP_PASSWORD when there is none yet
+func initAdminAuth() {
+	username := strings.TrimSpace(getEnv("ADMIN_BOOTSTRAP_USERNAME", ""))
+	password := getEnv("ADMIN_BOOTSTRAP_PASSWORD", "")
+	if db == nil || username == "" || password == "" {
+		return
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM admin_users`).Scan(&count); err != nil {
+		log.Printf("⚠️ Failed to check admin accounts: %v", err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+
+	if _, err := insertAdminUser(username, password, roleOwner); err != nil {
+		log.Printf("❌ Failed to create bootstrap admin %q: %v", username, err)
+		return
+	}
+	log.Printf("🔐 Bootstrap admin %q created", username)
+}
+
+// handleLogin exchanges admin credentials for a session token
+func handleLogin(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	var request LoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	var user AdminUser
+	var passwordHash string
+	err := db.QueryRow(`
+		SELECT id, username, role, active, last_login_at, created_at, password_hash
+		FROM admin_users
+		WHERE username = $1
+	`, strings.TrimSpace(request.Username)).Scan(&user.ID, &user.Username, &user.Role, &user.Active, &user.LastLoginAt, &user.CreatedAt, &passwordHash)
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify credentials"})
+		return
+	}
+	if err == sql.ErrNoRows {
+		unknownUserHashOnce.Do(func() { unknownUserHash, _ = security.HashPassword("unknown-user") })
+		passwordHash = unknownUserHash
+	}
+
+	if !security.CheckPassword(request.Password, passwordHash) || err == sql.ErrNoRows || !user.Active {
+		log.Printf("🔐 Failed admin login for %q from %s", redactLog(security.SanitizeString(request.Username)), redactLog(c.ClientIP()))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+
+	token, err := security.GenerateSecureToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+	expiresAt := time.Now().Add(security.TokenExpiry).UTC()
+
+	// Expired sessions are cleaned up whenever someone logs in
+	if _, err := db.Exec(`DELETE FROM admin_sessions WHERE expires_at < NOW()`); err != nil {
+		log.Printf("⚠️ Failed to delete expired admin sessions: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO admin_sessions (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`,
+		hashAdminToken(token), user.ID, expiresAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+	if _, err := db.Exec(`UPDATE admin_users SET last_login_at = NOW() WHERE id = $1`, user.ID); err != nil {
+		log.Printf("⚠️ Failed to record login of admin %q: %v", user.Username, err)
+	}
+
+	log.Printf("🔐 Admin %q logged in", user.Username)
+	c.JSON(http.StatusOK, gin.H{"token": token, "expires_at": expiresAt, "user": user})
+}
+
+// handleLogout ends the session of the request's token
+func handleLogout(c *gin.Context) {
+	token := bearerToken(c)
+	if token == "" || currentAdmin(c).Username == adminAPIKeyUsername {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only session tokens can be logged out"})
+		return
+	}
+
+	if _, err := db.Exec(`DELETE FROM admin_sessions WHERE token_hash = $1`, hashAdminToken(token)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "logged_out"})
+}
+
+// getCurrentAdmin returns the account of the request with its permissions
+func getCurrentAdmin(c *gin.Context) {
+	user := currentAdmin(c)
+	c.JSON(http.StatusOK, struct {
+		*AdminUser
+		Permissions []string `json:"permissions"`
+	}{user, rolePermissionList(user.Role)})
+}
+
+// getAdminUsers lists the admin accounts
+func getAdminUsers(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	rows, err := db.Query(`SELECT id, username, role, active, last_login_at, created_at FROM admin_users ORDER BY username`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch admin users"})
+		return
+	}
+	defer rows.Close()
+
+	users := []AdminUser{}
+	for rows.Next() {
+		var user AdminUser
+		if err := rows.Scan(&user.ID, &user.Username, &user.Role, &user.Active, &user.LastLoginAt, &user.CreatedAt); err != nil {
+			continue
+		}
+		users = append(users, user)
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// createAdminUser adds an admin account
+func createAdminUser(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	var request AdminUserRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if validationErr := validateAdminUser(request); validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+		return
+	}
+
+	role := request.Role
+	if role == "" {
+		role = roleAnalyst
+	}
+
+	user, err := insertAdminUser(strings.TrimSpace(request.Username), request.Password, role)
+	if isUniqueViolation(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create admin user"})
+		return
+	}
+
+	log.Printf("🔐 Admin %q (%s) created by %q", user.Username, user.Role, currentAdmin(c).Username)
+	c.JSON(http.StatusCreated, user)
+}
+
+// validateAdminUser checks the username, password and role of a new account
+func validateAdminUser(request AdminUserRequest) *security.ValidationError {
+	username := strings.TrimSpace(request.Username)
+	if username == "" || len(username) > 100 || username != security.SanitizeString(username) || username == adminAPIKeyUsername {
+		return &security.ValidationError{Field: "username", Message: "Invalid username"}
+	}
+	if len(request.Password) < adminMinPasswordLength {
+		return &security.ValidationError{Field: "password", Message: "Password must be at least " + strconv.Itoa(adminMinPasswordLength) + " characters"}
+	}
+	if request.Role != "" && !validRole(request.Role) {
+		return &security.ValidationError{Field: "role", Message: "Role must be one of " + strings.Join(adminRoles, ", ")}
+	}
+	return nil
+}
+
+// insertAdminUser stores an account with a role and a bcrypt hash of its password
+func insertAdminUser(username, password, role string) (AdminUser, error) {
+	user := AdminUser{Username: username, Role: role, Active: true}
+	passwordHash, err := security.HashPassword(password)
+	if err != nil {
+		return user, err
+	}
+	err = db.QueryRow(`INSERT INTO admin_users (username, password_hash, role) VALUES ($1, $2, $3) RETURNING id, created_at`,
+		username, passwordHash, role).Scan(&user.ID, &user.CreatedAt)
+	return user, err
+}
+
+// =============================================================================
+// 🛡️ AUTHENTICATION MIDDLEWARE
+// =============================================================================
+
+// requireAdmin requires an admin session token or an admin API key, sent as a
+// Bearer token or X-API-Key header
+func requireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(adminUserContextKey); ok {
+			c.Next()
+			return
+		}
+
+		user, status, message := authenticateAdmin(c)
+		if user == nil {
+			if status == http.StatusUnauthorized {
+				c.Header("WWW-Authenticate", `Bearer realm="API"`)
+			}
+			c.JSON(status, gin.H{"error": message})
+			c.Abort()
+			return
+		}
+
+		c.Set(adminUserContextKey, user)
+		c.Next()
+	}
+}
+
+// requireAuthForWrites applies requireAdmin to mutating requests, except on
+// the public write routes
+func requireAuthForWrites() gin.HandlerFunc {
+	auth := requireAdmin()
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			if !publicWriteRoutes[c.FullPath()] {
+				auth(c)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// authenticateAdmin resolves the account of a request. It returns the HTTP
+// status and message to answer with when there is none.
+func authenticateAdmin(c *gin.Context) (*AdminUser, int, string) {
+	apiKey := c.GetHeader("X-API-Key")
+	token := bearerToken(c)
+	if apiKey == "" && token == "" {
+		return nil, http.StatusUnauthorized, "Authentication required"
+	}
+
+	// Compare against every key so timing does not reveal which one matched
+	candidate := apiKey
+	if candidate == "" {
+		candidate = token
+	}
+	validKey := false
+	for _, key := range secConfig.AdminAPIKeys {
+		if security.SecureCompare(candidate, key) {
+			validKey = true
+		}
+	}
+	if validKey {
+		// Admin API keys predate roles and keep full access
+		return &AdminUser{Username: adminAPIKeyUsername, Role: roleOwner, Active: true}, 0, ""
+	}
+	if apiKey != "" {
+		return nil, http.StatusUnauthorized, "Invalid API key"
+	}
+
+	if db == nil {
+		return nil, http.StatusServiceUnavailable, "Database not available"
+	}
+
+	var user AdminUser
+	err := db.QueryRowContext(c.Request.Context(), `
+		SELECT u.id, u.username, u.role, u.active, u.last_login_at, u.created_at
+		FROM admin_sessions s
+		JOIN admin_users u ON u.id = s.user_id
+		WHERE s.token_hash = $1 AND s.expires_at > NOW() AND u.active = true
+	`, hashAdminToken(token)).Scan(&user.ID, &user.Username, &user.Role, &user.Active, &user.LastLoginAt, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, http.StatusUnauthorized, "Invalid or expired token"
+	}
+	if err != nil {
+		log.Printf("❌ Failed to verify admin session: %v", err)
+		return nil, http.StatusInternalServerError, "Failed to verify token"
+	}
+	return &user, 0, ""
+}
+
+// currentAdmin returns the account set by requireAdmin
+func currentAdmin(c *gin.Context) *AdminUser {
+	if value, ok := c.Get(adminUserContextKey); ok {
+		if user, ok := value.(*AdminUser); ok {
+			return user
+		}
+	}
+	return &AdminUser{}
+}
+
+// bearerToken returns the token of an Authorization: Bearer header
+func bearerToken(c *gin.Context) string {
+	if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+	}
+	return ""
+}
+
+// hashAdminToken returns the hash admin sessions are stored under
+func hashAdminToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
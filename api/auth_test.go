@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bruno-api/security"
+)
+
+func newAuthTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"user": currentAdmin(c).Username}) }
+
+	api := router.Group("/api/v1", requireAuthForWrites())
+	api.GET("/projects", ok)
+	api.POST("/projects", ok)
+	api.DELETE("/projects/:id", ok)
+	api.POST("/chat", ok)
+	api.GET("/auth/me", requireAdmin(), ok)
+	return router
+}
+
+func TestRequireAuthForWrites(t *testing.T) {
+	previous, previousDB := secConfig, db
+	defer func() { secConfig, db = previous, previousDB }()
+	secConfig = security.SecurityConfig{AdminAPIKeys: []string{"admin-key"}}
+	db = nil
+	router := newAuthTestRouter()
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		header   string
+		value    string
+		expected int
+	}{
+		{"reads are public", http.MethodGet, "/api/v1/projects", "", "", http.StatusOK},
+		{"public write route", http.MethodPost, "/api/v1/chat", "", "", http.StatusOK},
+		{"write without credentials", http.MethodPost, "/api/v1/projects", "", "", http.StatusUnauthorized},
+		{"delete without credentials", http.MethodDelete, "/api/v1/projects/1", "", "", http.StatusUnauthorized},
+		{"write with admin API key", http.MethodPost, "/api/v1/projects", "X-API-Key", "admin-key", http.StatusOK},
+		{"write with API key as bearer", http.MethodDelete, "/api/v1/projects/1", "Authorization", "Bearer admin-key", http.StatusOK},
+		{"write with wrong API key", http.MethodPost, "/api/v1/projects", "X-API-Key", "wrong", http.StatusUnauthorized},
+		{"session token without database", http.MethodPost, "/api/v1/projects", "Authorization", "Bearer session-token", http.StatusServiceUnavailable},
+		{"admin read without credentials", http.MethodGet, "/api/v1/auth/me", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code, w.Body.String())
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	req.Header.Set("X-API-Key", "admin-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), adminAPIKeyUsername)
+}
+
+func TestValidateAdminUser(t *testing.T) {
+	assert.Nil(t, validateAdminUser(AdminUserRequest{Username: "bruno", Password: "correct horse battery"}))
+
+	validationErr := validateAdminUser(AdminUserRequest{Username: "bruno", Password: "short"})
+	require.NotNil(t, validationErr)
+	assert.Equal(t, "password", validationErr.Field)
+
+	validationErr = validateAdminUser(AdminUserRequest{Username: "bruno", Password: "correct horse battery", Role: "superuser"})
+	require.NotNil(t, validationErr)
+	assert.Equal(t, "role", validationErr.Field)
+
+	for _, username := range []string{"", "<script>", adminAPIKeyUsername} {
+		validationErr = validateAdminUser(AdminUserRequest{Username: username, Password: "correct horse battery"})
+		require.NotNil(t, validationErr, username)
+		assert.Equal(t, "username", validationErr.Field)
+	}
+}
+
+func TestHashAdminToken(t *testing.T) {
+	token, err := security.GenerateSecureToken()
+	require.NoError(t, err)
+	assert.Equal(t, hashAdminToken(token), hashAdminToken(token))
+	assert.NotEqual(t, token, hashAdminToken(token))
+	assert.Len(t, hashAdminToken(token), 64)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🐤 CANARY MONITORING
+// =============================================================================
+
+func initCanaryProber() {
+	prober, err := services.NewCanaryProber(llmService)
+	if err != nil {
+		log.Printf("⚠️ Canary prober disabled: %v", err)
+		return
+	}
+	canaryProber = prober
+	go canaryProber.Run(context.Background())
+}
+
+// getCanaryStatus returns the results of the last canary run
+func getCanaryStatus(c *gin.Context) {
+	if canaryProber == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Canary prober not available"})
+		return
+	}
+	c.JSON(http.StatusOK, canaryProber.Status())
+}
+
+// runCanaries asks the canary questions now and returns the results
+func runCanaries(c *gin.Context) {
+	if canaryProber == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Canary prober not available"})
+		return
+	}
+	canaryProber.RunOnce(c.Request.Context())
+	c.JSON(http.StatusOK, canaryProber.Status())
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 📥 CHAT TRANSCRIPT EXPORT
+// =============================================================================
+
+// exportChatSession returns the transcript of a session to the visitor who
+// holds its token (X-Session-Token header). The token is never accepted as a
+// query parameter, which would write it to the access log. Unknown sessions
+// and wrong tokens get the same 404 so session IDs cannot be probed.
+func exportChatSession(c *gin.Context) {
+	if llmService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Chat not available"})
+		return
+	}
+
+	sessionID := c.Param("id")
+	if !services.ValidSessionID(sessionID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", services.TranscriptMarkdown))
+	contentType := services.TranscriptContentType(format)
+	if contentType == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of " + strings.Join(services.TranscriptFormats, ", ")})
+		return
+	}
+
+	token := c.GetHeader("X-Session-Token")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	valid, err := llmService.Sessions().VerifyToken(ctx, sessionID, token)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("❌ Failed to verify transcript token of session %s: %v", sessionID, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session store not available"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	session, err := llmService.Sessions().Get(ctx, sessionID)
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session store not available"})
+		return
+	}
+
+	content, err := services.RenderTranscript(session, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export transcript"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="chat-`+sessionID+`.`+format+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// getChatTranscript returns the stored transcript of a session to an admin
+func getChatTranscript(c *gin.Context) {
+	if llmService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Chat not available"})
+		return
+	}
+
+	sessionID := c.Param("id")
+	if !services.ValidSessionID(sessionID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	session, err := llmService.Sessions().Get(c.Request.Context(), sessionID)
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session store not available"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, session)
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🔌 CHAT WEBSOCKET CONFIGURATION
+// =============================================================================
+
+const (
+	chatWSWriteWait      = 10 * time.Second
+	chatWSPongWait       = 60 * time.Second
+	chatWSPingPeriod     = (chatWSPongWait * 9) / 10
+	chatWSMaxMessageSize = 8 * 1024
+	chatWSSendBuffer     = 256
+
+	// Client -> server message types
+	chatWSTypeMessage = "message"
+	chatWSTypeCancel  = "cancel"
+
+	// Bidirectional message types
+	chatWSTypeTyping = "typing"
+
+	// Server -> client message types
+	chatWSTypeToken  = "token"
+	chatWSTypeDone   = "done"
+	chatWSTypeNotice = "notice"
+	chatWSTypeError  = "error"
+	chatWSTypeOwner  = "owner" // reply of the owner in a claimed session
+)
+
+var (
+	chatWSUpgrader websocket.Upgrader
+	chatWSLimiter  *connectionLimiter
+
+	errChatWSClosed = errors.New("websocket connection closed")
+)
+
+func initChatWebSocket() {
+	maxPerIP, err := strconv.Atoi(getEnv("CHAT_WS_MAX_CONNECTIONS_PER_IP", "3"))
+	if err != nil || maxPerIP <= 0 {
+		maxPerIP = 3
+	}
+
+	chatWSLimiter = newConnectionLimiter(maxPerIP)
+	chatWSUpgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     checkChatWSOrigin,
+	}
+
+	log.Printf("🔌 Chat WebSocket initialized (max %d connections per IP)", maxPerIP)
+}
+
+// checkChatWSOrigin applies the CORS allowed origins to WebSocket upgrades
+func checkChatWSOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range secConfig.AllowedOrigins {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// 🚦 CONNECTION LIMITER
+// =============================================================================
+
+// connectionLimiter caps concurrent connections per client IP
+type connectionLimiter struct {
+	mu     sync.Mutex
+	max    int
+	active map[string]int
+}
+
+func newConnectionLimiter(max int) *connectionLimiter {
+	return &connectionLimiter{
+		max:    max,
+		active: make(map[string]int),
+	}
+}
+
+// acquire reserves a connection slot for ip, returning false when the limit is reached
+func (l *connectionLimiter) acquire(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active[ip] >= l.max {
+		return false
+	}
+	l.active[ip]++
+	return true
+}
+
+// release frees a connection slot for ip
+func (l *connectionLimiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active[ip] <= 1 {
+		delete(l.active, ip)
+		return
+	}
+	l.active[ip]--
+}
+
+// =============================================================================
+// 🤖 CHAT WEBSOCKET HANDLERS
+// =============================================================================
+
+// chatWSClient holds the state of a single chat WebSocket connection
+type chatWSClient struct {
+	conn      *websocket.Conn
+	send      chan ChatWSMessage
+	done      chan struct{}
+	clientIP  string
+	mu        sync.Mutex
+	sessionID string
+	cancel    context.CancelFunc
+}
+
+func handleChatWebSocket(c *gin.Context) {
+	clientIP := c.ClientIP()
+
+	if !chatWSLimiter.acquire(clientIP) {
+		log.Printf("🚫 Chat WebSocket rejected for %s: connection limit reached", redactLog(clientIP))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": "Too many chat connections from this IP",
+		})
+		return
+	}
+	defer chatWSLimiter.release(clientIP)
+
+	// Browsers cannot set headers on WebSocket upgrades, so the token may also
+	// come as a query parameter
+	sessionID := c.Query("session_id")
+	if sessionID != "" {
+		token := c.GetHeader("X-Session-Token")
+		if token == "" {
+			token = c.Query("token")
+		}
+		if sessionErr := verifyChatSession(c.Request.Context(), sessionID, token); sessionErr != nil {
+			c.JSON(sessionErr.Status, gin.H{"error": sessionErr.Message})
+			return
+		}
+	}
+
+	conn, err := chatWSUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written an HTTP error response
+		log.Printf("❌ Chat WebSocket upgrade failed for %s: %v", redactLog(clientIP), err)
+		return
+	}
+
+	client := &chatWSClient{
+		conn:      conn,
+		send:      make(chan ChatWSMessage, chatWSSendBuffer),
+		done:      make(chan struct{}),
+		clientIP:  clientIP,
+		sessionID: sessionID,
+	}
+
+	log.Printf("🔌 Chat WebSocket connected from %s", redactLog(clientIP))
+	chatTakeover.attach(c.Request.Context(), client.sessionID, client)
+
+	go client.writePump()
+	client.readPump()
+	chatTakeover.detach(context.Background(), client.currentSessionID(), client)
+
+	log.Printf("🔌 Chat WebSocket disconnected from %s", redactLog(clientIP))
+}
+
+// readPump reads client messages until the connection closes
+func (client *chatWSClient) readPump() {
+	defer func() {
+		client.cancelGeneration()
+		close(client.done)
+	}()
+
+	client.conn.SetReadLimit(chatWSMaxMessageSize)
+	client.conn.SetReadDeadline(time.Now().Add(chatWSPongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(chatWSPongWait))
+	})
+
+	client.emit(ChatWSMessage{
+		Type:      chatWSTypeNotice,
+		Content:   "connected",
+		SessionID: client.currentSessionID(),
+	})
+
+	for {
+		_, data, err := client.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("⚠️ Chat WebSocket read error from %s: %v", redactLog(client.clientIP), err)
+			}
+			return
+		}
+
+		var message ChatWSMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			client.emit(ChatWSMessage{Type: chatWSTypeError, Error: "Invalid message format"})
+			continue
+		}
+
+		switch message.Type {
+		case chatWSTypeMessage:
+			client.startGeneration(message)
+		case chatWSTypeCancel:
+			if !client.cancelGeneration() {
+				client.emit(ChatWSMessage{Type: chatWSTypeNotice, Content: "Nothing to cancel"})
+			}
+		case chatWSTypeTyping:
+			// Visitor typing indicators are accepted but not forwarded anywhere yet
+		default:
+			client.emit(ChatWSMessage{Type: chatWSTypeError, Error: "Unknown message type"})
+		}
+	}
+}
+
+// writePump serializes writes to the connection and sends keepalive pings
+func (client *chatWSClient) writePump() {
+	ticker := time.NewTicker(chatWSPingPeriod)
+	defer func() {
+		ticker.Stop()
+		client.conn.Close()
+	}()
+
+	for {
+		select {
+		case message := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(chatWSWriteWait))
+			if err := client.conn.WriteJSON(message); err != nil {
+				log.Printf("⚠️ Chat WebSocket write error to %s: %v", redactLog(client.clientIP), err)
+				return
+			}
+		case <-ticker.C:
+			client.conn.SetWriteDeadline(time.Now().Add(chatWSWriteWait))
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-client.done:
+			client.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(chatWSWriteWait),
+			)
+			return
+		}
+	}
+}
+
+// emit queues a message for the client, failing once the connection is closed
+func (client *chatWSClient) emit(message ChatWSMessage) error {
+	if message.Timestamp == "" {
+		message.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	select {
+	case client.send <- message:
+		return nil
+	case <-client.done:
+		return errChatWSClosed
+	}
+}
+
+// startGeneration runs the chat pipeline for a user message in the background
+func (client *chatWSClient) startGeneration(message ChatWSMessage) {
+	if strings.TrimSpace(message.Content) == "" {
+		client.emit(ChatWSMessage{Type: chatWSTypeError, Error: "Message cannot be empty"})
+		return
+	}
+
+	// Switching sessions needs the token of the new session
+	if message.SessionID != "" && message.SessionID != client.currentSessionID() {
+		if sessionErr := verifyChatSession(context.Background(), message.SessionID, message.Token); sessionErr != nil {
+			client.emit(ChatWSMessage{Type: chatWSTypeError, Error: sessionErr.Message})
+			return
+		}
+		client.setSessionID(message.SessionID)
+	}
+
+	client.mu.Lock()
+	if client.cancel != nil {
+		client.mu.Unlock()
+		client.emit(ChatWSMessage{Type: chatWSTypeError, Error: "A response is already being generated"})
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	client.cancel = cancel
+	client.mu.Unlock()
+	ctx, meter := services.WithUsageMeter(ctx)
+
+	request := services.ChatRequest{
+		Message:   message.Content,
+		Context:   message.Context,
+		SessionID: client.currentSessionID(),
+	}
+
+	go func() {
+		defer func() {
+			client.mu.Lock()
+			client.cancel = nil
+			client.mu.Unlock()
+			cancel()
+		}()
+
+		log.Printf("🤖 Chat WebSocket message from %s: %s", redactLog(client.clientIP), redactLog(truncateString(request.Message, 100)))
+
+		subject := chatQuotaSubject(ctx, request.SessionID, client.clientIP)
+		quota, exceeded := admitChatMessage(ctx, subject)
+		if exceeded != nil {
+			client.emit(ChatWSMessage{Type: chatWSTypeError, Error: exceeded.Message, Quota: quota, RetryAfter: ceilSeconds(exceeded.RetryAfter)})
+			return
+		}
+
+		// Sessions claimed by the owner skip the model
+		if chatTakeover.claimed(ctx, request.SessionID) {
+			if violation := forwardToOwner(ctx, request.SessionID, client.clientIP, request.Message); violation != nil {
+				recordGuardrailTrip(ctx, subject)
+				client.emit(ChatWSMessage{Type: chatWSTypeError, Error: violation.Message})
+				return
+			}
+			client.emit(ChatWSMessage{Type: chatWSTypeNotice, Content: "Message sent to Bruno", SessionID: request.SessionID})
+			return
+		}
+
+		startTime := time.Now()
+		client.emit(ChatWSMessage{Type: chatWSTypeTyping, Active: true})
+		response, err := llmService.ProcessChatStream(ctx, request, func(token string) error {
+			return client.emit(ChatWSMessage{Type: chatWSTypeToken, Content: token})
+		})
+		client.emit(ChatWSMessage{Type: chatWSTypeTyping, Active: false})
+
+		if err != nil {
+			if ctx.Err() == context.Canceled {
+				client.emit(ChatWSMessage{Type: chatWSTypeNotice, Content: "Generation cancelled"})
+				return
+			}
+			var violation *services.GuardrailViolation
+			if errors.As(err, &violation) {
+				recordGuardrailTrip(ctx, subject)
+				client.emit(ChatWSMessage{Type: chatWSTypeError, Error: violation.Message})
+				return
+			}
+			var modeErr *services.ChatModeError
+			if errors.As(err, &modeErr) {
+				client.emit(ChatWSMessage{Type: chatWSTypeError, Error: modeErr.Error()})
+				return
+			}
+			log.Printf("❌ Chat WebSocket processing error for %s: %v", redactLog(client.clientIP), err)
+			client.emit(ChatWSMessage{Type: chatWSTypeError, Error: "Failed to process chat request"})
+			return
+		}
+
+		client.setSessionID(response.SessionID)
+
+		recordChatUsage(ctx, subject, response, meter)
+		observeChatExchange(ctx, client.clientIP, request, response, startTime)
+
+		client.emit(ChatWSMessage{
+			Type:      chatWSTypeDone,
+			SessionID: response.SessionID,
+			Response:  response,
+			Quota:     quota,
+		})
+	}()
+}
+
+// cancelGeneration aborts the in-flight generation, reporting whether there was one
+func (client *chatWSClient) cancelGeneration() bool {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.cancel == nil {
+		return false
+	}
+	client.cancel()
+	return true
+}
+
+// chatSessionError is why a WebSocket client may not join a session
+type chatSessionError struct {
+	Status  int
+	Message string
+}
+
+// verifyChatSession checks that a WebSocket client holds the token of a
+// session before the connection joins it. Without the check any client could
+// read the owner's replies to another visitor and continue their conversation.
+func verifyChatSession(ctx context.Context, sessionID, token string) *chatSessionError {
+	if token == "" {
+		return &chatSessionError{Status: http.StatusUnauthorized, Message: "Session token required"}
+	}
+
+	valid, err := llmService.Sessions().VerifyToken(ctx, sessionID, token)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("❌ Failed to verify WebSocket token of session %s: %v", sessionID, err)
+		return &chatSessionError{Status: http.StatusServiceUnavailable, Message: "Session store not available"}
+	}
+	if !valid {
+		return &chatSessionError{Status: http.StatusNotFound, Message: "Session not found"}
+	}
+	return nil
+}
+
+// setSessionID binds the connection to a session so owner replies reach it.
+// Callers verify the session token first, or pass a session the server created.
+func (client *chatWSClient) setSessionID(sessionID string) {
+	client.mu.Lock()
+	previous := client.sessionID
+	client.sessionID = sessionID
+	client.mu.Unlock()
+
+	if previous != sessionID {
+		ctx := context.Background()
+		chatTakeover.detach(ctx, previous, client)
+		chatTakeover.attach(ctx, sessionID, client)
+	}
+}
+
+func (client *chatWSClient) currentSessionID() string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.sessionID
+}
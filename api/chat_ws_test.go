@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bruno-api/security"
+	"bruno-api/services"
+)
+
+func TestConnectionLimiter(t *testing.T) {
+	limiter := newConnectionLimiter(2)
+
+	assert.True(t, limiter.acquire("10.0.0.1"))
+	assert.True(t, limiter.acquire("10.0.0.1"))
+	assert.False(t, limiter.acquire("10.0.0.1"), "third connection from same IP should be rejected")
+	assert.True(t, limiter.acquire("10.0.0.2"), "other IPs keep their own budget")
+
+	limiter.release("10.0.0.1")
+	assert.True(t, limiter.acquire("10.0.0.1"), "released slot should be reusable")
+
+	limiter.release("10.0.0.2")
+	_, tracked := limiter.active["10.0.0.2"]
+	assert.False(t, tracked, "idle IPs should not be tracked")
+}
+
+func TestCheckChatWSOrigin(t *testing.T) {
+	previous := secConfig
+	defer func() { secConfig = previous }()
+
+	secConfig = security.SecurityConfig{AllowedOrigins: []string{"https://lucena.cloud"}}
+
+	req, _ := http.NewRequest("GET", "/api/v1/chat/ws", nil)
+	assert.True(t, checkChatWSOrigin(req), "requests without Origin are allowed")
+
+	req.Header.Set("Origin", "https://lucena.cloud")
+	assert.True(t, checkChatWSOrigin(req))
+
+	req.Header.Set("Origin", "https://evil.example")
+	assert.False(t, checkChatWSOrigin(req))
+
+	secConfig.AllowedOrigins = []string{"*"}
+	assert.True(t, checkChatWSOrigin(req))
+}
+
+func TestChatWebSocketRequiresSessionToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hubs, rdb := newTestTakeoverHubs(t, 1)
+
+	previousHub, previousLLM := chatTakeover, llmService
+	chatTakeover, llmService = hubs[0], services.NewLLMService(nil, rdb)
+	t.Cleanup(func() { chatTakeover, llmService = previousHub, previousLLM })
+	initChatWebSocket()
+
+	ctx := context.Background()
+	session, err := llmService.Sessions().GetOrCreate(ctx, "")
+	require.NoError(t, err)
+	other, err := llmService.Sessions().GetOrCreate(ctx, "")
+	require.NoError(t, err)
+
+	// Handlers outlive the client side of the connection, wait for them
+	// before the globals are restored
+	var handlers sync.WaitGroup
+	router := gin.New()
+	router.GET("/api/v1/chat/ws", func(c *gin.Context) {
+		handlers.Add(1)
+		defer handlers.Done()
+		handleChatWebSocket(c)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/chat/ws?session_id=" + session.ID
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, resp, err = websocket.DefaultDialer.Dial(url+"&token=wrong-token", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"&token="+session.Token, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		handlers.Wait()
+	})
+
+	var message ChatWSMessage
+	require.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, chatWSTypeNotice, message.Type)
+	assert.Equal(t, session.ID, message.SessionID)
+
+	// Switching to another session without its token is refused
+	require.NoError(t, conn.WriteJSON(ChatWSMessage{Type: chatWSTypeMessage, Content: "Hi", SessionID: other.ID}))
+	require.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, chatWSTypeError, message.Type)
+	assert.Equal(t, "Session token required", message.Error)
+
+	hubs[0].mu.Lock()
+	assert.Len(t, hubs[0].visitors[session.ID], 1)
+	assert.Empty(t, hubs[0].visitors[other.ID], "the connection stays out of sessions it has no token for")
+	hubs[0].mu.Unlock()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	// 🔒 Security package
+	"bruno-api/security"
+)
+
+// =============================================================================
+// 📚 KNOWLEDGE DOCUMENTS
+// =============================================================================
+
+// maxDocumentBytes is the largest document accepted for upload
+func maxDocumentBytes() int {
+	limit, err := strconv.Atoi(getEnv("CONTEXT_DOCUMENT_MAX_BYTES", "1048576"))
+	if err != nil || limit <= 0 {
+		return 1048576
+	}
+	return limit
+}
+
+func getContextDocuments(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, title, COALESCE(filename, ''), size_bytes, active, created_at, updated_at
+		FROM context_documents
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch documents"})
+		return
+	}
+	defer rows.Close()
+
+	documents := []ContextDocument{}
+	for rows.Next() {
+		var doc ContextDocument
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.Filename, &doc.SizeBytes, &doc.Active, &doc.CreatedAt, &doc.UpdatedAt); err != nil {
+			continue
+		}
+		documents = append(documents, doc)
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// createContextDocument accepts a multipart upload (`file`, optional `title`)
+// or a JSON body with `title` and `content`
+func createContextDocument(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	maxBytes := maxDocumentBytes()
+	var doc ContextDocument
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form field 'file' is required"})
+			return
+		}
+
+		doc.Filename = filepath.Base(fileHeader.Filename)
+		switch strings.ToLower(filepath.Ext(doc.Filename)) {
+		case ".txt", ".md", ".markdown":
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only .txt and .md documents are supported"})
+			return
+		}
+		if fileHeader.Size > int64(maxBytes) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Document is too large"})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read document"})
+			return
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(io.LimitReader(file, int64(maxBytes)+1))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read document"})
+			return
+		}
+		doc.Content = string(content)
+		doc.Title = c.PostForm("title")
+		if doc.Title == "" {
+			doc.Title = strings.TrimSuffix(doc.Filename, filepath.Ext(doc.Filename))
+		}
+	} else if err := c.ShouldBindJSON(&doc); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	title, titleErr := security.ValidateAndSanitizeTitle(doc.Title)
+	if titleErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": titleErr.Message})
+		return
+	}
+	doc.Title = title
+
+	if strings.TrimSpace(doc.Content) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Document content is required"})
+		return
+	}
+	if len(doc.Content) > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Document is too large"})
+		return
+	}
+	if !utf8.ValidString(doc.Content) || strings.ContainsRune(doc.Content, 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Document must be UTF-8 text"})
+		return
+	}
+
+	doc.SizeBytes = len(doc.Content)
+	doc.Active = true
+	err := db.QueryRow(`
+		INSERT INTO context_documents (title, filename, content, size_bytes)
+		VALUES ($1, NULLIF($2, ''), $3, $4)
+		RETURNING id, created_at, updated_at
+	`, doc.Title, doc.Filename, doc.Content, doc.SizeBytes).Scan(&doc.ID, &doc.CreatedAt, &doc.UpdatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store document"})
+		return
+	}
+
+	doc.Content = ""
+	c.JSON(http.StatusCreated, doc)
+}
+
+func deleteContextDocument(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	documentID, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	result, err := db.Exec(`DELETE FROM context_documents WHERE id = $1`, documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete document"})
+		return
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Document deleted successfully"})
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+
+	// 🔒 Security package
+	"bruno-api/security"
+)
+
+// =============================================================================
+// ❓ FAQ ANSWERS
+// =============================================================================
+
+const (
+	maxFAQVariants      = 20
+	maxFAQQuestionChars = 300
+	maxFAQAnswerChars   = 4000
+)
+
+// refreshFAQs reloads the FAQ matcher in the background after an admin change
+func refreshFAQs() {
+	if llmService == nil {
+		return
+	}
+	go func() {
+		if err := llmService.FAQs().Refresh(); err != nil {
+			log.Printf("⚠️ Failed to refresh FAQ entries: %v", err)
+		}
+	}()
+}
+
+func getFAQEntries(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, question, COALESCE(variants, '{}'), answer, active, created_at, updated_at
+		FROM faq_entries
+		ORDER BY id
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch FAQ entries"})
+		return
+	}
+	defer rows.Close()
+
+	entries := []FAQEntry{}
+	for rows.Next() {
+		var entry FAQEntry
+		var variants pq.StringArray
+		var active bool
+		if err := rows.Scan(&entry.ID, &entry.Question, &variants, &entry.Answer, &active, &entry.CreatedAt, &entry.UpdatedAt); err != nil {
+			continue
+		}
+		entry.Variants = []string(variants)
+		entry.Active = &active
+		entries = append(entries, entry)
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func createFAQEntry(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	var entry FAQEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !validateFAQEntry(c, &entry) {
+		return
+	}
+
+	err := db.QueryRow(`
+		INSERT INTO faq_entries (question, variants, answer, active)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`, entry.Question, pq.Array(entry.Variants), entry.Answer, *entry.Active).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create FAQ entry"})
+		return
+	}
+
+	refreshFAQs()
+	c.JSON(http.StatusCreated, entry)
+}
+
+func updateFAQEntry(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	entryID, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	var entry FAQEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !validateFAQEntry(c, &entry) {
+		return
+	}
+
+	result, err := db.Exec(`UPDATE faq_entries SET question = $1, variants = $2, answer = $3, active = $4 WHERE id = $5`,
+		entry.Question, pq.Array(entry.Variants), entry.Answer, *entry.Active, entryID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update FAQ entry"})
+		return
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "FAQ entry not found"})
+		return
+	}
+
+	refreshFAQs()
+	c.JSON(http.StatusOK, gin.H{"message": "FAQ entry updated successfully"})
+}
+
+func deleteFAQEntry(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	entryID, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	result, err := db.Exec(`DELETE FROM faq_entries WHERE id = $1`, entryID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete FAQ entry"})
+		return
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "FAQ entry not found"})
+		return
+	}
+
+	refreshFAQs()
+	c.JSON(http.StatusOK, gin.H{"message": "FAQ entry deleted successfully"})
+}
+
+// validateFAQEntry trims the question, variants and answer and writes a 400
+// response when they are invalid. Answers are returned verbatim to visitors,
+// so they are stored as plain text rather than HTML-escaped.
+func validateFAQEntry(c *gin.Context, entry *FAQEntry) bool {
+	entry.Question = strings.Join(strings.Fields(entry.Question), " ")
+	if entry.Question == "" || utf8.RuneCountInString(entry.Question) > maxFAQQuestionChars {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Question must be between 1 and 300 characters"})
+		return false
+	}
+
+	entry.Answer = strings.TrimSpace(entry.Answer)
+	if entry.Answer == "" || utf8.RuneCountInString(entry.Answer) > maxFAQAnswerChars {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Answer must be between 1 and 4000 characters"})
+		return false
+	}
+	if !utf8.ValidString(entry.Answer) || strings.ContainsRune(entry.Answer, 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Answer must be UTF-8 text"})
+		return false
+	}
+
+	if len(entry.Variants) > maxFAQVariants {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many variants (max 20)"})
+		return false
+	}
+	variants := make([]string, 0, len(entry.Variants))
+	seen := map[string]bool{strings.ToLower(entry.Question): true}
+	for _, variant := range entry.Variants {
+		variant = strings.Join(strings.Fields(variant), " ")
+		if variant == "" || utf8.RuneCountInString(variant) > maxFAQQuestionChars {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Variants must be between 1 and 300 characters"})
+			return false
+		}
+		if key := strings.ToLower(variant); !seen[key] {
+			seen[key] = true
+			variants = append(variants, variant)
+		}
+	}
+	entry.Variants = variants
+
+	if entry.Active == nil {
+		active := true
+		entry.Active = &active
+	}
+	return true
+}
@@ -160,6 +160,7 @@ func createProject(c *gin.Context) {
 	}
 
 	project.ID = id
+	refreshIntents()
 	c.JSON(http.StatusCreated, project)
 }
 
@@ -238,6 +239,7 @@ func updateProject(c *gin.Context) {
 		return
 	}
 
+	refreshIntents()
 	c.JSON(http.StatusOK, gin.H{"message": "Project updated successfully"})
 }
 
@@ -269,6 +271,7 @@ func deleteProject(c *gin.Context) {
 		return
 	}
 
+	refreshIntents()
 	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
 }
 
@@ -376,6 +379,7 @@ func createSkill(c *gin.Context) {
 	}
 
 	skill.ID = id
+	refreshIntents()
 	c.JSON(http.StatusCreated, skill)
 }
 
@@ -439,6 +443,7 @@ func updateSkill(c *gin.Context) {
 		return
 	}
 
+	refreshIntents()
 	c.JSON(http.StatusOK, gin.H{"message": "Skill updated successfully"})
 }
 
@@ -470,6 +475,7 @@ func deleteSkill(c *gin.Context) {
 		return
 	}
 
+	refreshIntents()
 	c.JSON(http.StatusOK, gin.H{"message": "Skill deleted successfully"})
 }
 
@@ -553,6 +559,7 @@ func createExperience(c *gin.Context) {
 	}
 
 	exp.ID = id
+	refreshIntents()
 	c.JSON(http.StatusCreated, exp)
 }
 
@@ -583,6 +590,7 @@ func updateExperience(c *gin.Context) {
 		return
 	}
 
+	refreshIntents()
 	c.JSON(http.StatusOK, gin.H{"message": "Experience updated successfully"})
 }
 
@@ -602,6 +610,7 @@ func deleteExperience(c *gin.Context) {
 		return
 	}
 
+	refreshIntents()
 	c.JSON(http.StatusOK, gin.H{"message": "Experience deleted successfully"})
 }
 
@@ -0,0 +1,459 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+
+	// 🔒 Security package
+	"bruno-api/security"
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🧭 CHAT INTENT TAXONOMY
+// =============================================================================
+
+const (
+	maxIntentSynonyms  = 20
+	maxIntentTermChars = 100
+)
+
+var intentNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+
+// refreshIntents reloads the chat intent matcher after the taxonomy or the
+// portfolio data it is derived from changes
+func refreshIntents() {
+	if llmService == nil {
+		return
+	}
+	go func() {
+		if err := llmService.Intents().Refresh(); err != nil {
+			log.Printf("⚠️ Failed to refresh chat intents: %v", err)
+		}
+	}()
+}
+
+func getChatIntents(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	rows, err := db.Query(`SELECT id, name, COALESCE(description, ''), active FROM chat_intents ORDER BY name`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch intents"})
+		return
+	}
+	defer rows.Close()
+
+	var intents []ChatIntent
+	byID := make(map[int]int)
+	for rows.Next() {
+		var intent ChatIntent
+		var active bool
+		if err := rows.Scan(&intent.ID, &intent.Name, &intent.Description, &active); err != nil {
+			continue
+		}
+		intent.Active = &active
+		intent.Keywords = []ChatIntentKeyword{}
+		intent.Derived = []string{}
+		byID[intent.ID] = len(intents)
+		intents = append(intents, intent)
+	}
+
+	keywordRows, err := db.Query(`SELECT id, intent_id, keyword, synonyms FROM chat_intent_keywords ORDER BY keyword`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch intent keywords"})
+		return
+	}
+	defer keywordRows.Close()
+
+	for keywordRows.Next() {
+		var keyword ChatIntentKeyword
+		var synonyms pq.StringArray
+		if err := keywordRows.Scan(&keyword.ID, &keyword.IntentID, &keyword.Keyword, &synonyms); err != nil {
+			continue
+		}
+		keyword.Synonyms = []string(synonyms)
+		if i, ok := byID[keyword.IntentID]; ok {
+			intents[i].Keywords = append(intents[i].Keywords, keyword)
+		}
+	}
+
+	// Names derived from the portfolio are not stored, so take them from the matcher
+	if llmService != nil {
+		terms := llmService.Intents().Terms()
+		for i := range intents {
+			if derived, ok := terms[intents[i].Name]; ok {
+				intents[i].Derived = derived.Derived
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, intents)
+}
+
+func createChatIntent(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	var intent ChatIntent
+	if err := c.ShouldBindJSON(&intent); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !validateChatIntent(c, &intent) {
+		return
+	}
+
+	err := db.QueryRow(`INSERT INTO chat_intents (name, description, active) VALUES ($1, $2, $3) RETURNING id`,
+		intent.Name, intent.Description, *intent.Active).Scan(&intent.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Intent already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create intent"})
+		return
+	}
+
+	intent.Keywords = []ChatIntentKeyword{}
+	intent.Derived = []string{}
+	refreshIntents()
+	c.JSON(http.StatusCreated, intent)
+}
+
+func updateChatIntent(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	intentID, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	var intent ChatIntent
+	if err := c.ShouldBindJSON(&intent); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !validateChatIntent(c, &intent) {
+		return
+	}
+
+	result, err := db.Exec(`UPDATE chat_intents SET name = $1, description = $2, active = $3 WHERE id = $4`,
+		intent.Name, intent.Description, *intent.Active, intentID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Intent already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update intent"})
+		return
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Intent not found"})
+		return
+	}
+
+	refreshIntents()
+	c.JSON(http.StatusOK, gin.H{"message": "Intent updated successfully"})
+}
+
+func deleteChatIntent(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	intentID, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	result, err := db.Exec(`DELETE FROM chat_intents WHERE id = $1`, intentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete intent"})
+		return
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Intent not found"})
+		return
+	}
+
+	refreshIntents()
+	c.JSON(http.StatusOK, gin.H{"message": "Intent deleted successfully"})
+}
+
+func createChatIntentKeyword(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	intentID, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	var keyword ChatIntentKeyword
+	if err := c.ShouldBindJSON(&keyword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !validateChatIntentKeyword(c, &keyword) {
+		return
+	}
+
+	keyword.IntentID = intentID
+	err := db.QueryRow(`INSERT INTO chat_intent_keywords (intent_id, keyword, synonyms) VALUES ($1, $2, $3) RETURNING id`,
+		intentID, keyword.Keyword, pq.Array(keyword.Synonyms)).Scan(&keyword.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Keyword already exists for this intent"})
+			return
+		}
+		if isForeignKeyViolation(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Intent not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create keyword"})
+		return
+	}
+
+	refreshIntents()
+	c.JSON(http.StatusCreated, keyword)
+}
+
+func updateChatIntentKeyword(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	intentID, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+	keywordID, validationErr := security.ValidateInteger(c.Param("keywordId"), "keywordId", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	var keyword ChatIntentKeyword
+	if err := c.ShouldBindJSON(&keyword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !validateChatIntentKeyword(c, &keyword) {
+		return
+	}
+
+	result, err := db.Exec(`UPDATE chat_intent_keywords SET keyword = $1, synonyms = $2 WHERE id = $3 AND intent_id = $4`,
+		keyword.Keyword, pq.Array(keyword.Synonyms), keywordID, intentID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Keyword already exists for this intent"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update keyword"})
+		return
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Keyword not found"})
+		return
+	}
+
+	refreshIntents()
+	c.JSON(http.StatusOK, gin.H{"message": "Keyword updated successfully"})
+}
+
+func deleteChatIntentKeyword(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	intentID, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+	keywordID, validationErr := security.ValidateInteger(c.Param("keywordId"), "keywordId", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	result, err := db.Exec(`DELETE FROM chat_intent_keywords WHERE id = $1 AND intent_id = $2`, keywordID, intentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete keyword"})
+		return
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Keyword not found"})
+		return
+	}
+
+	refreshIntents()
+	c.JSON(http.StatusOK, gin.H{"message": "Keyword deleted successfully"})
+}
+
+// validateChatIntent normalizes an intent and writes a 400 response when it is invalid
+func validateChatIntent(c *gin.Context, intent *ChatIntent) bool {
+	intent.Name = strings.ToLower(strings.TrimSpace(intent.Name))
+	if !intentNamePattern.MatchString(intent.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Intent name must be 1-50 lowercase letters, digits, '-' or '_'"})
+		return false
+	}
+
+	if intent.Description != "" {
+		description, descErr := security.ValidateAndSanitizeDescription(intent.Description)
+		if descErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": descErr.Message})
+			return false
+		}
+		intent.Description = description
+	}
+
+	if intent.Active == nil {
+		active := true
+		intent.Active = &active
+	}
+	return true
+}
+
+// validateChatIntentKeyword normalizes a keyword and its synonyms and writes a
+// 400 response when they are invalid
+func validateChatIntentKeyword(c *gin.Context, keyword *ChatIntentKeyword) bool {
+	normalized, ok := normalizeIntentTerm(keyword.Keyword)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Keyword must be between 1 and 100 characters"})
+		return false
+	}
+	keyword.Keyword = normalized
+
+	if len(keyword.Synonyms) > maxIntentSynonyms {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many synonyms (max 20)"})
+		return false
+	}
+
+	synonyms := make([]string, 0, len(keyword.Synonyms))
+	seen := map[string]bool{keyword.Keyword: true}
+	for _, synonym := range keyword.Synonyms {
+		normalized, ok := normalizeIntentTerm(synonym)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Synonyms must be between 1 and 100 characters"})
+			return false
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			synonyms = append(synonyms, normalized)
+		}
+	}
+	keyword.Synonyms = synonyms
+	return true
+}
+
+// normalizeIntentTerm lowercases a keyword and collapses whitespace
+func normalizeIntentTerm(term string) (string, bool) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(term)), " ")
+	return normalized, normalized != "" && len(normalized) <= maxIntentTermChars
+}
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23503"
+}
+
+// =============================================================================
+// 🧪 INTENT CLASSIFIER TRAINING
+// =============================================================================
+
+// runTrainIntents retrains the intent classifier from a labelled query file,
+// prints its cross-validated accuracy and optionally writes the model
+func runTrainIntents(args []string) int {
+	flags := flag.NewFlagSet("train-intents", flag.ContinueOnError)
+	dataPath := flags.String("data", "services/data/intent_queries.tsv", "labelled query file")
+	outPath := flags.String("out", "", "write the trained model to this file (load it with INTENT_MODEL_PATH)")
+	folds := flags.Int("folds", 5, "cross-validation folds")
+	threshold := flags.Float64("threshold", 0.6, "confidence threshold")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	file, err := os.Open(*dataPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	defer file.Close()
+
+	examples, err := services.ParseLabelledQueries(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s: %v\n", *dataPath, err)
+		return 1
+	}
+
+	classifier := services.TrainIntentClassifier(examples)
+	fmt.Printf("📚 Trained on %d labelled queries (%d features)\n", len(examples), classifier.Vocabulary)
+
+	matcher := services.NewIntentMatcher(nil)
+	keywordOnly := services.EvaluateIntentPredictor(examples, classifier.Intents, func(query string) []string {
+		var intents []string
+		for _, intent := range classifier.Intents {
+			if matcher.Matches(intent, query) {
+				intents = append(intents, intent)
+			}
+		}
+		return intents
+	})
+	classifierOnly := services.CrossValidateIntentClassifier(examples, *folds, *threshold, nil)
+	combined := services.CrossValidateIntentClassifier(examples, *folds, *threshold, matcher)
+
+	fmt.Printf("🧪 %d-fold cross-validation, threshold %.2f (exact match accuracy)\n", *folds, *threshold)
+	fmt.Printf("   keywords only:         %5.1f%%\n", keywordOnly.ExactMatch*100)
+	fmt.Printf("   classifier only:       %5.1f%%\n", classifierOnly.ExactMatch*100)
+	fmt.Printf("   classifier + keywords: %5.1f%%\n", combined.ExactMatch*100)
+	fmt.Printf("\n   %-12s %9s %7s %6s %8s\n", "intent", "precision", "recall", "f1", "support")
+	for _, intent := range classifier.Intents {
+		row := combined.PerIntent[intent]
+		fmt.Printf("   %-12s %9.2f %7.2f %6.2f %8d\n", intent, row.Precision, row.Recall, row.F1, row.Support)
+	}
+
+	if *outPath != "" {
+		if err := classifier.Save(*outPath); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to write model: %v\n", err)
+			return 1
+		}
+		fmt.Printf("\n💾 Model written to %s\n", *outPath)
+	}
+	return 0
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	// 🔒 Security package
+	"bruno-api/security"
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🤝 LEAD CAPTURE
+// =============================================================================
+
+const (
+	leadsDefaultLimit = 50
+	leadsMaxLimit     = 200
+)
+
+// submitLead stores the contact details a visitor leaves after the lead
+// capture offer, together with the conversation. Only the holder of the
+// session token (X-Session-Token header) can submit, and only once the offer
+// was made in that session. Submitting again for the same session updates the
+// lead.
+func submitLead(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+	if llmService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Chat not available"})
+		return
+	}
+
+	var request LeadRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	lead, validationErr := validateLead(request)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "field": validationErr.Field})
+		return
+	}
+
+	token := c.GetHeader("X-Session-Token")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token required"})
+		return
+	}
+
+	// Unknown sessions and wrong tokens get the same 404, as for exports
+	ctx := c.Request.Context()
+	valid, err := llmService.Sessions().VerifyToken(ctx, lead.SessionID, token)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("❌ Failed to verify session token for lead of session %s: %v", lead.SessionID, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session store not available"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	offered, err := llmService.Sessions().LeadOffered(ctx, lead.SessionID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session store not available"})
+		return
+	}
+	if !offered {
+		c.JSON(http.StatusForbidden, gin.H{"error": "No lead capture offer was made in this session"})
+		return
+	}
+
+	// Leads belong to a conversation; the transcript is stored with them
+	session, err := llmService.Sessions().Get(ctx, lead.SessionID)
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ Failed to load transcript for lead of session %s: %v", lead.SessionID, err)
+		session = &services.ChatSession{ID: lead.SessionID}
+	}
+	lead.Transcript = session.Messages
+	if lead.Transcript == nil {
+		lead.Transcript = []services.SessionMessage{}
+	}
+
+	transcript, err := json.Marshal(lead.Transcript)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode transcript"})
+		return
+	}
+
+	err = db.QueryRow(`
+		INSERT INTO leads (session_id, name, email, company, message, transcript)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (session_id) DO UPDATE SET
+			name = EXCLUDED.name, email = EXCLUDED.email, company = EXCLUDED.company,
+			message = EXCLUDED.message, transcript = EXCLUDED.transcript
+		RETURNING id, status, created_at, updated_at
+	`, lead.SessionID, lead.Name, lead.Email, lead.Company, lead.Message, transcript).Scan(&lead.ID, &lead.Status, &lead.CreatedAt, &lead.UpdatedAt)
+	if err != nil {
+		log.Printf("❌ Failed to store lead for session %s: %v", lead.SessionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store lead"})
+		return
+	}
+
+	log.Printf("🤝 Lead #%d captured from session %s", lead.ID, lead.SessionID)
+	c.JSON(http.StatusCreated, gin.H{"id": lead.ID, "status": lead.Status})
+}
+
+// getLeads lists leads, newest first, optionally with one status
+func getLeads(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	status := c.Query("status")
+	if status != "" && !services.ValidLeadStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of " + strings.Join(services.LeadStatuses, ", ")})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(leadsDefaultLimit)))
+	if err != nil || limit < 1 || limit > leadsMaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(leadsMaxLimit)})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, session_id, name, email, COALESCE(company, ''), COALESCE(message, ''), status, created_at, updated_at
+		FROM leads
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, status, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leads"})
+		return
+	}
+	defer rows.Close()
+
+	leads := []Lead{}
+	for rows.Next() {
+		var lead Lead
+		if err := rows.Scan(&lead.ID, &lead.SessionID, &lead.Name, &lead.Email, &lead.Company, &lead.Message,
+			&lead.Status, &lead.CreatedAt, &lead.UpdatedAt); err != nil {
+			continue
+		}
+		leads = append(leads, lead)
+	}
+
+	c.JSON(http.StatusOK, leads)
+}
+
+// getLead returns a lead with its conversation transcript
+func getLead(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	id, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	var lead Lead
+	var transcript []byte
+	err := db.QueryRow(`
+		SELECT id, session_id, name, email, COALESCE(company, ''), COALESCE(message, ''), status, transcript, created_at, updated_at
+		FROM leads
+		WHERE id = $1
+	`, id).Scan(&lead.ID, &lead.SessionID, &lead.Name, &lead.Email, &lead.Company, &lead.Message,
+		&lead.Status, &transcript, &lead.CreatedAt, &lead.UpdatedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lead not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lead"})
+		return
+	}
+
+	lead.Transcript = []services.SessionMessage{}
+	if err := json.Unmarshal(transcript, &lead.Transcript); err != nil {
+		log.Printf("⚠️ Failed to decode transcript of lead #%d: %v", lead.ID, err)
+	}
+
+	c.JSON(http.StatusOK, lead)
+}
+
+// updateLeadStatus moves a lead to another status
+func updateLeadStatus(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	id, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	var request LeadStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !services.ValidLeadStatus(request.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of " + strings.Join(services.LeadStatuses, ", ")})
+		return
+	}
+
+	result, err := db.Exec(`UPDATE leads SET status = $1 WHERE id = $2`, request.Status, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update lead"})
+		return
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lead not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": id, "status": request.Status})
+}
+
+// validateLead checks and sanitizes the contact details of a lead
+func validateLead(request LeadRequest) (Lead, *security.ValidationError) {
+	lead := Lead{SessionID: strings.TrimSpace(request.SessionID)}
+	if !services.ValidSessionID(lead.SessionID) {
+		return lead, &security.ValidationError{Field: "session_id", Message: "Invalid session id"}
+	}
+
+	email, validationErr := security.ValidateAndSanitizeEmail(strings.TrimSpace(request.Email))
+	if validationErr != nil {
+		return lead, validationErr
+	}
+	lead.Email = strings.ToLower(email)
+
+	lead.Name = security.SanitizeString(request.Name)
+	if lead.Name == "" || len(lead.Name) > security.MaxTitleLength {
+		return lead, &security.ValidationError{Field: "name", Message: "Name must be between 1 and " + strconv.Itoa(security.MaxTitleLength) + " characters"}
+	}
+
+	lead.Company = security.SanitizeString(request.Company)
+	if len(lead.Company) > security.MaxTitleLength {
+		return lead, &security.ValidationError{Field: "company", Message: "Company must be " + strconv.Itoa(security.MaxTitleLength) + " characters or less"}
+	}
+
+	if strings.TrimSpace(request.Message) != "" {
+		message, validationErr := security.ValidateAndSanitizeDescription(request.Message)
+		if validationErr != nil {
+			return lead, validationErr
+		}
+		lead.Message = message
+	}
+
+	return lead, nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLead(t *testing.T) {
+	lead, validationErr := validateLead(LeadRequest{
+		SessionID: testSessionID,
+		Name:      " Ada <Lovelace> ",
+		Email:     "Ada@Example.com",
+		Company:   "Analytical Engines",
+	})
+	require.Nil(t, validationErr)
+	assert.Equal(t, "ada@example.com", lead.Email)
+	assert.Equal(t, "Ada &lt;Lovelace&gt;", lead.Name, "names are sanitized")
+
+	_, validationErr = validateLead(LeadRequest{SessionID: testSessionID, Name: "Ada", Email: "not-an-email"})
+	require.NotNil(t, validationErr)
+	assert.Equal(t, "email", validationErr.Field)
+
+	_, validationErr = validateLead(LeadRequest{SessionID: "sess_unknown", Name: "Ada", Email: "ada@example.com"})
+	require.NotNil(t, validationErr)
+	assert.Equal(t, "session_id", validationErr.Field)
+}
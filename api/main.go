@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -31,6 +32,9 @@ import (
 
 	// 🤖 LLM services
 	"bruno-api/services"
+
+	// 🧠 Model Context Protocol server
+	"bruno-api/mcp"
 )
 
 // =============================================================================
@@ -38,10 +42,14 @@ import (
 // =============================================================================
 
 var (
-	db          *sql.DB
-	redisClient *redis.Client
-	secConfig   security.SecurityConfig
-	llmService  *services.LLMService
+	db            *sql.DB
+	redisClient   *redis.Client
+	secConfig     security.SecurityConfig
+	llmService    *services.LLMService
+	fitAnalyzer   *services.FitAnalyzer
+	resumeTailor  *services.ResumeTailor
+	searchService *services.SearchService
+	canaryProber  *services.CanaryProber
 )
 
 // =============================================================================
@@ -54,6 +62,17 @@ func main() {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	// MCP stdio mode (`bruno-api mcp`) only needs the database
+	if len(os.Args) > 1 && os.Args[1] == "mcp" {
+		runMCPStdio()
+		return
+	}
+
+	// Intent classifier training (`bruno-api train-intents`) runs offline
+	if len(os.Args) > 1 && os.Args[1] == "train-intents" {
+		os.Exit(runTrainIntents(os.Args[2:]))
+	}
+
 	// Initialize database connection
 	if err := initDatabase(); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
@@ -69,9 +88,27 @@ func main() {
 	// Initialize security configuration
 	initSecurityConfig()
 
+	// Initialize admin accounts
+	initAdminAuth()
+
 	// Initialize LLM service
 	initLLMService()
 
+	// Initialize chat quotas
+	initChatQuotas()
+
+	// Initialize search
+	initSearch()
+
+	// Initialize canary monitoring of the chat pipeline
+	initCanaryProber()
+
+	// Initialize chat WebSocket transport
+	initChatWebSocket()
+
+	// Initialize live owner takeover of chat sessions
+	initChatTakeover()
+
 	// Initialize OpenTelemetry (if enabled)
 	initTracing()
 
@@ -171,13 +208,18 @@ func initSecurityConfig() {
 		AllowedOrigins:    strings.Split(getEnv("ALLOWED_ORIGINS", "*"), ","),
 		EnableCSP:         getEnv("ENABLE_CSP", "true") == "true",
 		CSPPolicy:         getEnv("CSP_POLICY", "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data: https:; font-src 'self' data:;"),
+		OpenAIAPIKeys:     security.ParseAPIKeys(getEnv("OPENAI_API_KEYS", "")),
+		AdminAPIKeys:      security.ParseAPIKeys(getEnv("ADMIN_API_KEYS", "")),
+		TrustedProxies:    security.ParseList(getEnv("TRUSTED_PROXIES", "")),
 	}
 
 	log.Println("🔒 Security configuration initialized")
 }
 
 func initLLMService() {
-	llmService = services.NewLLMService(db)
+	llmService = services.NewLLMService(db, redisClient)
+	fitAnalyzer = services.NewFitAnalyzer(db, llmService)
+	resumeTailor = services.NewResumeTailor(db, llmService)
 
 	// Test LLM service health
 	if err := llmService.HealthCheck(); err != nil {
@@ -188,6 +230,18 @@ func initLLMService() {
 	}
 }
 
+func runMCPStdio() {
+	if err := initDatabase(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	server := mcp.NewServer(services.NewContextBuilder(db))
+	if err := server.ServeStdio(context.Background(), os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("MCP server stopped: %v", err)
+	}
+}
+
 func initTracing() {
 	// OpenTelemetry initialization (currently disabled)
 	// This can be enabled when needed for distributed tracing
@@ -208,18 +262,25 @@ func setupRouter() *gin.Engine {
 
 	router := gin.New()
 
+	// 🌐 Only the ingress may set X-Forwarded-For; otherwise any client could
+	// pick the IP its quotas are counted against
+	if err := router.SetTrustedProxies(secConfig.TrustedProxies); err != nil {
+		log.Printf("⚠️ Invalid TRUSTED_PROXIES, trusting no proxy: %v", err)
+		_ = router.SetTrustedProxies(nil)
+	}
+
 	// Add middleware
 	router.Use(requestLogger())
 	router.Use(errorHandler())
-	router.Use(gzip.Gzip(gzip.DefaultCompression))
+	router.Use(gzip.Gzip(gzip.DefaultCompression, gzip.WithExcludedPaths([]string{"/v1/chat/completions"})))
 	router.Use(security.EnhancedSecurityHeaders(secConfig))
-	router.Use(security.SQLInjectionProtectionMiddleware())
+	router.Use(security.SQLInjectionProtectionMiddleware(freeTextQueryParams))
 	router.Use(security.RateLimitMiddleware())
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     secConfig.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Session-Token"},
+		ExposeHeaders:    append([]string{"Content-Length", "Content-Disposition"}, quotaResponseHeaders...),
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -230,96 +291,134 @@ func setupRouter() *gin.Engine {
 	// Prometheus metrics endpoint (secured)
 	router.GET("/metrics", security.MetricsAuthMiddleware(secConfig), gin.WrapH(promhttp.Handler()))
 
+	// 🧠 MCP streamable HTTP endpoint (read-only portfolio data)
+	if getEnvBool("ENABLE_MCP_HTTP", false) {
+		router.Any("/mcp", gin.WrapH(mcp.NewServer(services.NewContextBuilder(db)).AllowOrigins(secConfig.AllowedOrigins)))
+	}
+
 	// API routes (v1)
-	api := router.Group("/api/v1")
+	api := router.Group("/api/v1", requireAuthForWrites())
 	{
-		// Projects
-		api.GET("/projects", getProjects)
-		api.GET("/projects/:id", getProject)
-		api.POST("/projects", createProject)
-		api.PUT("/projects/:id", updateProject)
-		api.DELETE("/projects/:id", deleteProject)
-
-		// Skills
-		api.GET("/skills", getSkills)
-		api.GET("/skills/:id", getSkill)
-		api.POST("/skills", createSkill)
-		api.PUT("/skills/:id", updateSkill)
-		api.DELETE("/skills/:id", deleteSkill)
-
-		// Experiences
-		api.GET("/experiences", getExperiences)
-		api.GET("/experiences/:id", getExperience)
-		api.POST("/experiences", createExperience)
-		api.PUT("/experiences/:id", updateExperience)
-		api.DELETE("/experiences/:id", deleteExperience)
-
-		// Content
-		api.GET("/content", getContent)
-		api.GET("/content/:type", getContentByType)
-		api.POST("/content", createContent)
-		api.PUT("/content/:id", updateContent)
-		api.DELETE("/content/:id", deleteContent)
-
-		// About
-		api.GET("/about", getAbout)
-		api.PUT("/about", updateAbout)
-
-		// Contact
-		api.GET("/contact", getContact)
-		api.PUT("/contact", updateContact)
+		// 🔐 Admin login
+		api.POST("/auth/login", handleLogin)
+		api.POST("/auth/logout", requireAdmin(), handleLogout)
+		api.GET("/auth/me", requireAdmin(), getCurrentAdmin)
+
+		// Portfolio content
+		registerPortfolioRoutes(api)
 
 		// 🤖 AI Chat endpoint
 		api.POST("/chat", handleChat)
+		api.GET("/chat/ws", handleChatWebSocket)
 		api.GET("/chat/health", handleChatHealth)
+		api.POST("/chat/leads", submitLead)
+		api.GET("/chat/sessions/:id/export", exportChatSession)
+		api.GET("/chat/sessions/:id/replies", getOwnerReplies)
+
+		// 🎯 Job description fit analysis and tailored resumes
+		api.POST("/match", modelQuota(), handleMatch)
+		api.POST("/resume/tailor", modelQuota(), handleTailoredResume)
+
+		// 🔎 Search
+		api.GET("/search", handleSearch)
 
 		// 📊 Analytics endpoint
 		api.POST("/analytics/track", handleAnalyticsTrack)
+
+		// 🛡️ Admin endpoints (admin session or API key required, each group
+		// guarded by the permissions of the admin's role)
+		admin := api.Group("/admin", requireAdmin())
+		{
+			// 🔐 Admin accounts, roles and authorization audit log
+			users := admin.Group("/users", authorize(resourceAccess))
+			users.GET("", getAdminUsers)
+			users.POST("", createAdminUser)
+			users.PUT("/:id/role", updateAdminUserRole)
+			admin.GET("/roles", authorize(resourceAccess), getAdminRoles)
+			admin.GET("/audit", authorize(resourceAccess), getAuthorizationAuditLog)
+
+			// 🔬 Chat transcripts and context preview (no LLM call, so it only reads)
+			chat := admin.Group("/chat", authorizeAction(resourceChat, actionRead))
+			chat.GET("/sessions/:id", getChatTranscript)
+			chat.POST("/preview", handleChatPreview)
+
+			// 🙋 Live chat sessions and owner takeover
+			live := admin.Group("/live", authorize(resourceTakeover))
+			live.GET("/sessions", getLiveChatSessions)
+			live.GET("/stream", streamLiveChatSessions)
+			live.POST("/sessions/:id/claim", claimLiveChatSession)
+			live.POST("/sessions/:id/reply", replyLiveChatSession)
+			live.POST("/sessions/:id/release", releaseLiveChatSession)
+
+			// 🧭 Chat intent taxonomy
+			intents := admin.Group("/intents", authorize(resourceIntents))
+			intents.GET("", getChatIntents)
+			intents.POST("", createChatIntent)
+			intents.PUT("/:id", updateChatIntent)
+			intents.DELETE("/:id", deleteChatIntent)
+			intents.POST("/:id/keywords", createChatIntentKeyword)
+			intents.PUT("/:id/keywords/:keywordId", updateChatIntentKeyword)
+			intents.DELETE("/:id/keywords/:keywordId", deleteChatIntentKeyword)
+
+			// ❓ FAQ answers served without the LLM
+			faq := admin.Group("/faq", authorize(resourceFAQ))
+			faq.GET("", getFAQEntries)
+			faq.POST("", createFAQEntry)
+			faq.PUT("/:id", updateFAQEntry)
+			faq.DELETE("/:id", deleteFAQEntry)
+
+			// 🤝 Leads captured from hiring conversations
+			leads := admin.Group("/leads", authorize(resourceLeads))
+			leads.GET("", getLeads)
+			leads.GET("/:id", getLead)
+			leads.PUT("/:id/status", updateLeadStatus)
+
+			// 👥 Shadow evaluation of candidate models
+			shadow := admin.Group("/shadow", authorize(resourceAnalytics))
+			shadow.GET("/report", getShadowReport)
+			shadow.GET("/comparisons", getShadowComparisons)
+
+			// 💰 Token and GPU usage against the daily budget
+			admin.GET("/usage", authorize(resourceAnalytics), getUsageReport)
+
+			// 🐤 Canary questions through the full chat pipeline
+			canaries := admin.Group("/canaries", authorize(resourceAnalytics))
+			canaries.GET("", getCanaryStatus)
+			canaries.POST("/run", runCanaries)
+
+			// 📚 Knowledge documents for the chatbot
+			documents := admin.Group("/documents", authorize(resourceDocuments))
+			documents.GET("", getContextDocuments)
+			documents.POST("", createContextDocument)
+			documents.DELETE("/:id", deleteContextDocument)
+		}
+	}
+
+	// 🧩 OpenAI-compatible API (API key required)
+	openAI := router.Group("/v1", security.APIKeyAuthMiddleware(secConfig.OpenAIAPIKeys))
+	{
+		openAI.GET("/models", handleOpenAIModels)
+		openAI.POST("/chat/completions", handleOpenAIChatCompletions)
 	}
 
 	// Legacy API routes (for frontend compatibility)
-	legacyApi := router.Group("/api")
+	legacyApi := router.Group("/api", requireAuthForWrites())
 	{
-		// Projects
-		legacyApi.GET("/projects", getProjects)
-		legacyApi.GET("/projects/:id", getProject)
-		legacyApi.POST("/projects", createProject)
-		legacyApi.PUT("/projects/:id", updateProject)
-		legacyApi.DELETE("/projects/:id", deleteProject)
-
-		// Skills
-		legacyApi.GET("/skills", getSkills)
-		legacyApi.GET("/skills/:id", getSkill)
-		legacyApi.POST("/skills", createSkill)
-		legacyApi.PUT("/skills/:id", updateSkill)
-		legacyApi.DELETE("/skills/:id", deleteSkill)
-
-		// Experiences
-		legacyApi.GET("/experiences", getExperiences)
-		legacyApi.GET("/experiences/:id", getExperience)
-		legacyApi.POST("/experiences", createExperience)
-		legacyApi.PUT("/experiences/:id", updateExperience)
-		legacyApi.DELETE("/experiences/:id", deleteExperience)
-
-		// Content
-		legacyApi.GET("/content", getContent)
-		legacyApi.GET("/content/:type", getContentByType)
-		legacyApi.POST("/content", createContent)
-		legacyApi.PUT("/content/:id", updateContent)
-		legacyApi.DELETE("/content/:id", deleteContent)
-
-		// About
-		legacyApi.GET("/about", getAbout)
-		legacyApi.PUT("/about", updateAbout)
-
-		// Contact
-		legacyApi.GET("/contact", getContact)
-		legacyApi.PUT("/contact", updateContact)
+		// Portfolio content
+		registerPortfolioRoutes(legacyApi)
 
 		// 🤖 AI Chat endpoint
 		legacyApi.POST("/chat", handleChat)
+		legacyApi.GET("/chat/ws", handleChatWebSocket)
 		legacyApi.GET("/chat/health", handleChatHealth)
 
+		// 🎯 Job description fit analysis and tailored resumes
+		legacyApi.POST("/match", modelQuota(), handleMatch)
+		legacyApi.POST("/resume/tailor", modelQuota(), handleTailoredResume)
+
+		// 🔎 Search
+		legacyApi.GET("/search", handleSearch)
+
 		// 📊 Analytics endpoint
 		legacyApi.POST("/analytics/track", handleAnalyticsTrack)
 	}
@@ -327,6 +426,52 @@ func setupRouter() *gin.Engine {
 	return router
 }
 
+// registerPortfolioRoutes adds the portfolio content routes to an API group,
+// one group per resource so writes are authorized by the admin's role
+func registerPortfolioRoutes(api *gin.RouterGroup) {
+	// Projects
+	projects := api.Group("/projects", authorize(resourceProjects))
+	projects.GET("", getProjects)
+	projects.GET("/:id", getProject)
+	projects.POST("", createProject)
+	projects.PUT("/:id", updateProject)
+	projects.DELETE("/:id", deleteProject)
+
+	// Skills
+	skills := api.Group("/skills", authorize(resourceSkills))
+	skills.GET("", getSkills)
+	skills.GET("/:id", getSkill)
+	skills.POST("", createSkill)
+	skills.PUT("/:id", updateSkill)
+	skills.DELETE("/:id", deleteSkill)
+
+	// Experiences
+	experiences := api.Group("/experiences", authorize(resourceExperiences))
+	experiences.GET("", getExperiences)
+	experiences.GET("/:id", getExperience)
+	experiences.POST("", createExperience)
+	experiences.PUT("/:id", updateExperience)
+	experiences.DELETE("/:id", deleteExperience)
+
+	// Content
+	content := api.Group("/content", authorize(resourceContent))
+	content.GET("", getContent)
+	content.GET("/:type", getContentByType)
+	content.POST("", createContent)
+	content.PUT("/:id", updateContent)
+	content.DELETE("/:id", deleteContent)
+
+	// About
+	about := api.Group("/about", authorize(resourceContent))
+	about.GET("", getAbout)
+	about.PUT("", updateAbout)
+
+	// Contact
+	contact := api.Group("/contact", authorize(resourceContent))
+	contact.GET("", getContact)
+	contact.PUT("", updateContact)
+}
+
 // =============================================================================
 // 🛠️ UTILITY FUNCTIONS
 // =============================================================================
@@ -370,7 +515,7 @@ func handleChat(c *gin.Context) {
 	requestID := fmt.Sprintf("chat_handler_%d", startTime.UnixNano())
 
 	log.Printf("🤖 [%s] Chat request received", requestID)
-	log.Printf("   📍 Remote IP: %s", c.ClientIP())
+	log.Printf("   📍 Remote IP: %s", redactLog(c.ClientIP()))
 	log.Printf("   📍 User Agent: %s", c.GetHeader("User-Agent"))
 	log.Printf("   📍 Content-Type: %s", c.GetHeader("Content-Type"))
 	log.Printf("   📍 Content-Length: %s", c.GetHeader("Content-Length"))
@@ -388,8 +533,9 @@ func handleChat(c *gin.Context) {
 	}
 
 	log.Printf("✅ [%s] JSON binding successful", requestID)
-	log.Printf("   📝 Message: %s", truncateString(request.Message, 100))
-	log.Printf("   📝 Context: %s", truncateString(request.Context, 50))
+	log.Printf("   📝 Message: %s", redactLog(truncateString(request.Message, 100)))
+	log.Printf("   📝 Context: %s", redactLog(truncateString(request.Context, 50)))
+	log.Printf("   💬 Session: %s", request.SessionID)
 
 	// Validate message is not empty
 	if strings.TrimSpace(request.Message) == "" {
@@ -400,10 +546,59 @@ func handleChat(c *gin.Context) {
 		return
 	}
 
+	// Count the message against the session and IP quotas
+	subject := chatQuotaSubject(c.Request.Context(), request.SessionID, c.ClientIP())
+	quota, exceeded := admitChatMessage(c.Request.Context(), subject)
+	setQuotaHeaders(c, quota)
+	if exceeded != nil {
+		rejectOverQuota(c, exceeded)
+		return
+	}
+
+	// Sessions claimed by the owner skip the model
+	if chatTakeover.claimed(c.Request.Context(), request.SessionID) {
+		if violation := forwardToOwner(c.Request.Context(), request.SessionID, c.ClientIP(), request.Message); violation != nil {
+			recordGuardrailTrip(c.Request.Context(), subject)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": violation.Message,
+				"rule":  violation.Rule,
+			})
+			return
+		}
+		log.Printf("🙋 [%s] Message forwarded to the owner", requestID)
+		c.JSON(http.StatusAccepted, gin.H{
+			"session_id":    request.SessionID,
+			"source":        services.SessionAuthorOwner,
+			"status":        "forwarded",
+			"owner_replies": takeOwnerReplies(c, request.SessionID),
+		})
+		return
+	}
+
 	log.Printf("🔄 [%s] Processing chat request...", requestID)
 
-	// Process chat request
-	response, err := llmService.ProcessChat(request)
+	// Process chat request, metering the tokens it costs
+	ctx, meter := services.WithUsageMeter(c.Request.Context())
+	response, err := llmService.ProcessChatStream(ctx, request, nil)
+	var violation *services.GuardrailViolation
+	if errors.As(err, &violation) {
+		log.Printf("🛡️ [%s] Chat request rejected by guardrail: %s", requestID, violation.Rule)
+		recordGuardrailTrip(c.Request.Context(), subject)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": violation.Message,
+			"rule":  violation.Rule,
+		})
+		return
+	}
+	var modeErr *services.ChatModeError
+	if errors.As(err, &modeErr) {
+		log.Printf("❌ [%s] Invalid chat context: %s", requestID, modeErr.Mode)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       modeErr.Error(),
+			"valid_modes": services.ChatModeNames(),
+		})
+		return
+	}
 	if err != nil {
 		log.Printf("❌ [%s] Chat processing error: %v", requestID, err)
 		log.Printf("   🔍 Error type: %T", err)
@@ -420,9 +615,54 @@ func handleChat(c *gin.Context) {
 	log.Printf("   📤 Response length: %d chars", len(response.Response))
 	log.Printf("   🎯 Model used: %s", response.Model)
 
+	recordChatUsage(c.Request.Context(), subject, response, meter)
+	observeChatExchange(c.Request.Context(), c.ClientIP(), request, response, startTime)
+	response.OwnerReplies = takeOwnerReplies(c, response.SessionID)
+
 	c.JSON(http.StatusOK, response)
 }
 
+// handleChatPreview shows what the chat pipeline would send to the model for a
+// message, without calling it
+func handleChatPreview(c *gin.Context) {
+	var request services.ChatRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if strings.TrimSpace(request.Message) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message cannot be empty"})
+		return
+	}
+
+	preview, err := llmService.PreviewChat(c.Request.Context(), request)
+	if errors.Is(err, services.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	var modeErr *services.ChatModeError
+	if errors.As(err, &modeErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       modeErr.Error(),
+			"valid_modes": services.ChatModeNames(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to build chat preview",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
 func handleChatHealth(c *gin.Context) {
 	if err := llmService.HealthCheck(); err != nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
@@ -436,7 +676,8 @@ func handleChatHealth(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status":    "healthy",
 		"provider":  "ollama",
-		"model":     getEnv("GEMMA_MODEL", "gemma3n:e4b"),
+		"model":     llmService.Model(),
+		"roles":     llmService.ModelRoutes(),
 		"timestamp": time.Now().UTC(),
 	})
 }
@@ -340,6 +340,23 @@ func TestNotFoundEndpoint(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestRequestLoggerOmitsQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(gin.LoggerWithConfig(gin.LoggerConfig{Formatter: formatRequestLog, Output: &logs}))
+	router.GET("/api/v1/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/search?q=contact+jane.doe%40example.com&session_id=sess_abc", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Contains(t, logs.String(), "GET /api/v1/search 200")
+	assert.NotContains(t, logs.String(), "jane.doe")
+	assert.NotContains(t, logs.String(), "sess_abc")
+}
+
 // Benchmark tests
 func BenchmarkHealthEndpoint(b *testing.B) {
 	router := setupTestRouter(nil)
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =============================================================================
+// 🎯 JOB DESCRIPTION FIT ANALYSIS
+// =============================================================================
+
+// maxJobDescriptionLength returns the maximum accepted job description length in characters
+func maxJobDescriptionLength() int {
+	maxLength, err := strconv.Atoi(getEnv("MATCH_MAX_JOB_DESCRIPTION_LENGTH", "20000"))
+	if err != nil || maxLength <= 0 {
+		return 20000
+	}
+	return maxLength
+}
+
+func handleMatch(c *gin.Context) {
+	var request MatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	jobDescription, ok := validateJobDescription(c, request.JobDescription)
+	if !ok {
+		return
+	}
+
+	withSummary := request.Summary == nil || *request.Summary
+
+	report, err := fitAnalyzer.Analyze(c.Request.Context(), jobDescription, withSummary)
+	if err != nil {
+		log.Printf("❌ Job fit analysis failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze job description"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// validateJobDescription trims a job description and writes a 400 response when it is empty or too long
+func validateJobDescription(c *gin.Context, raw string) (string, bool) {
+	jobDescription := strings.TrimSpace(raw)
+	if jobDescription == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Job description cannot be empty"})
+		return "", false
+	}
+	if maxLength := maxJobDescriptionLength(); utf8.RuneCountInString(jobDescription) > maxLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Job description must be " + strconv.Itoa(maxLength) + " characters or less"})
+		return "", false
+	}
+	return jobDescription, true
+}
@@ -0,0 +1,582 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🧠 MODEL CONTEXT PROTOCOL SERVER
+// =============================================================================
+
+const (
+	serverName    = "bruno-portfolio"
+	serverVersion = "1.0.0"
+
+	// latestProtocolVersion is used when the client asks for a version we do not know
+	latestProtocolVersion = "2025-06-18"
+
+	resourceMimeType = "application/json"
+)
+
+var supportedProtocolVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+
+// JSON-RPC error codes
+const (
+	codeParseError       = -32700
+	codeInvalidRequest   = -32600
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeInternalError    = -32603
+	codeResourceNotFound = -32002
+)
+
+// PortfolioData is the read-only portfolio data exposed over MCP. It is
+// implemented by services.ContextBuilder.
+type PortfolioData interface {
+	GetAbout() (services.AboutInfo, error)
+	GetContact() (services.ContactInfo, error)
+	ListSkills() ([]services.SkillInfo, error)
+	ListExperience() ([]services.ExpInfo, error)
+	ListProjects() ([]services.ProjectInfo, error)
+}
+
+// Server answers MCP requests about the portfolio
+type Server struct {
+	data           PortfolioData
+	allowedOrigins []string
+}
+
+// NewServer creates a new MCP server backed by the given portfolio data
+func NewServer(data PortfolioData) *Server {
+	return &Server{data: data}
+}
+
+// AllowOrigins sets the browser origins the HTTP transport accepts. The "*"
+// wildcard is ignored: a page on any site must not reach the server through
+// DNS rebinding.
+func (s *Server) AllowOrigins(origins []string) *Server {
+	s.allowedOrigins = nil
+	for _, origin := range origins {
+		if origin = strings.TrimSpace(origin); origin != "" && origin != "*" {
+			s.allowedOrigins = append(s.allowedOrigins, origin)
+		}
+	}
+	return s
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// HandleMessage processes a single JSON-RPC message or batch and returns the
+// encoded response, or nil when the message only contained notifications
+func (s *Server) HandleMessage(ctx context.Context, data []byte) []byte {
+	trimmed := strings.TrimSpace(string(data))
+
+	if strings.HasPrefix(trimmed, "[") {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return encode(errorResponse(nil, codeParseError, "Parse error"))
+		}
+
+		var responses []*rpcResponse
+		for _, raw := range batch {
+			if response := s.handleRequest(ctx, raw); response != nil {
+				responses = append(responses, response)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return encode(responses)
+	}
+
+	if response := s.handleRequest(ctx, data); response != nil {
+		return encode(response)
+	}
+	return nil
+}
+
+func (s *Server) handleRequest(ctx context.Context, data []byte) *rpcResponse {
+	var request rpcRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return errorResponse(nil, codeParseError, "Parse error")
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return errorResponse(request.ID, codeInvalidRequest, "Invalid request")
+	}
+
+	// Notifications never get a response
+	isNotification := len(request.ID) == 0
+
+	result, rpcErr := s.dispatch(ctx, request)
+	if isNotification {
+		if rpcErr != nil {
+			log.Printf("⚠️ MCP notification %s failed: %s", request.Method, rpcErr.Message)
+		}
+		return nil
+	}
+	if rpcErr != nil {
+		return &rpcResponse{JSONRPC: "2.0", ID: request.ID, Error: rpcErr}
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: request.ID, Result: result}
+}
+
+func (s *Server) dispatch(ctx context.Context, request rpcRequest) (interface{}, *rpcError) {
+	switch request.Method {
+	case "initialize":
+		return s.initialize(request.Params)
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "resources/list":
+		return map[string]interface{}{"resources": resourceList()}, nil
+	case "resources/read":
+		return s.readResource(request.Params)
+	case "tools/list":
+		return map[string]interface{}{"tools": toolList()}, nil
+	case "tools/call":
+		return s.callTool(request.Params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("Method not found: %s", request.Method)}
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) (interface{}, *rpcError) {
+	var request struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &request); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "Invalid initialize params"}
+		}
+	}
+
+	version := latestProtocolVersion
+	for _, supported := range supportedProtocolVersions {
+		if request.ProtocolVersion == supported {
+			version = supported
+		}
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"resources": map[string]interface{}{},
+			"tools":     map[string]interface{}{},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    serverName,
+			"version": serverVersion,
+		},
+		"instructions": "Read-only access to Bruno Lucena's portfolio: projects, skills, work experience and contact details. Use the resources for full listings and the tools to search or look up specific records.",
+	}, nil
+}
+
+// =============================================================================
+// 📚 RESOURCES
+// =============================================================================
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
+func resourceList() []resource {
+	return []resource{
+		{URI: "portfolio://about", Name: "about", Description: "Professional summary", MimeType: resourceMimeType},
+		{URI: "portfolio://projects", Name: "projects", Description: "Active projects with technologies and links", MimeType: resourceMimeType},
+		{URI: "portfolio://skills", Name: "skills", Description: "Skills by category with proficiency (1-5)", MimeType: resourceMimeType},
+		{URI: "portfolio://experience", Name: "experience", Description: "Work experience, most recent first", MimeType: resourceMimeType},
+		{URI: "portfolio://contact", Name: "contact", Description: "Public contact information and availability", MimeType: resourceMimeType},
+	}
+}
+
+func (s *Server) readResource(params json.RawMessage) (interface{}, *rpcError) {
+	var request struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &request); err != nil || request.URI == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "Missing resource uri"}
+	}
+
+	var payload interface{}
+	var err error
+	switch request.URI {
+	case "portfolio://about":
+		payload, err = s.data.GetAbout()
+	case "portfolio://projects":
+		payload, err = s.data.ListProjects()
+	case "portfolio://skills":
+		payload, err = s.data.ListSkills()
+	case "portfolio://experience":
+		payload, err = s.data.ListExperience()
+	case "portfolio://contact":
+		payload, err = s.data.GetContact()
+	default:
+		return nil, &rpcError{Code: codeResourceNotFound, Message: fmt.Sprintf("Resource not found: %s", request.URI)}
+	}
+	if err != nil {
+		log.Printf("❌ MCP failed to read resource %s: %v", request.URI, err)
+		return nil, &rpcError{Code: codeInternalError, Message: "Failed to read resource"}
+	}
+
+	text, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: "Failed to encode resource"}
+	}
+
+	return map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{"uri": request.URI, "mimeType": resourceMimeType, "text": string(text)},
+		},
+	}, nil
+}
+
+// =============================================================================
+// 🛠️ TOOLS
+// =============================================================================
+
+type tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+func toolList() []tool {
+	return []tool{
+		{
+			Name:        "search_portfolio",
+			Description: "Search projects, work experience and skills by keywords",
+			InputSchema: objectSchema(map[string]interface{}{
+				"query": map[string]interface{}{"type": "string", "description": "Keywords to search for"},
+				"types": map[string]interface{}{
+					"type":        "array",
+					"description": "Restrict results to these record types",
+					"items":       map[string]interface{}{"type": "string", "enum": []string{"project", "experience", "skill"}},
+				},
+			}, "query"),
+		},
+		{
+			Name:        "get_project",
+			Description: "Get a project by id or title",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id":    map[string]interface{}{"type": "integer"},
+				"title": map[string]interface{}{"type": "string"},
+			}),
+		},
+		{
+			Name:        "get_experience",
+			Description: "Get the roles held at a company",
+			InputSchema: objectSchema(map[string]interface{}{
+				"company": map[string]interface{}{"type": "string"},
+			}, "company"),
+		},
+		{
+			Name:        "lookup_skill",
+			Description: "Look up a skill or technology with the experience and projects where it was used",
+			InputSchema: objectSchema(map[string]interface{}{
+				"name": map[string]interface{}{"type": "string"},
+			}, "name"),
+		},
+	}
+}
+
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// toolArguments holds the union of all tool arguments
+type toolArguments struct {
+	Query   string   `json:"query"`
+	Types   []string `json:"types"`
+	ID      int      `json:"id"`
+	Title   string   `json:"title"`
+	Company string   `json:"company"`
+	Name    string   `json:"name"`
+}
+
+// SearchResult is a single record matched by search_portfolio
+type SearchResult struct {
+	Type  string      `json:"type"`
+	ID    int         `json:"id"`
+	Title string      `json:"title"`
+	Score int         `json:"score"`
+	Data  interface{} `json:"data"`
+}
+
+func (s *Server) callTool(params json.RawMessage) (interface{}, *rpcError) {
+	var request struct {
+		Name      string        `json:"name"`
+		Arguments toolArguments `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "Invalid tool call params"}
+	}
+
+	var result interface{}
+	var err error
+	switch request.Name {
+	case "search_portfolio":
+		result, err = s.searchPortfolio(request.Arguments)
+	case "get_project":
+		result, err = s.getProject(request.Arguments)
+	case "get_experience":
+		result, err = s.getExperience(request.Arguments)
+	case "lookup_skill":
+		result, err = s.lookupSkill(request.Arguments)
+	default:
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("Unknown tool: %s", request.Name)}
+	}
+
+	// Tool failures are reported in the result so the model can see them
+	if err != nil {
+		return toolResult(err.Error(), true), nil
+	}
+
+	text, encodeErr := json.MarshalIndent(result, "", "  ")
+	if encodeErr != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: "Failed to encode tool result"}
+	}
+	return toolResult(string(text), false), nil
+}
+
+func toolResult(text string, isError bool) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]interface{}{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+func (s *Server) searchPortfolio(args toolArguments) ([]SearchResult, error) {
+	terms := strings.Fields(strings.ToLower(args.Query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	wanted := func(recordType string) bool {
+		if len(args.Types) == 0 {
+			return true
+		}
+		for _, t := range args.Types {
+			if t == recordType {
+				return true
+			}
+		}
+		return false
+	}
+
+	results := []SearchResult{}
+
+	if wanted("project") {
+		projects, err := s.data.ListProjects()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load projects: %v", err)
+		}
+		for _, p := range projects {
+			text := strings.Join(append([]string{p.Title, p.Type, p.Description}, p.Technologies...), " ")
+			if score := matchScore(text, terms); score > 0 {
+				results = append(results, SearchResult{Type: "project", ID: p.ID, Title: p.Title, Score: score, Data: p})
+			}
+		}
+	}
+
+	if wanted("experience") {
+		experiences, err := s.data.ListExperience()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load experience: %v", err)
+		}
+		for _, e := range experiences {
+			text := strings.Join(append([]string{e.Title, e.Company, e.Description}, e.Technologies...), " ")
+			if score := matchScore(text, terms); score > 0 {
+				results = append(results, SearchResult{Type: "experience", ID: e.ID, Title: e.Title + " at " + e.Company, Score: score, Data: e})
+			}
+		}
+	}
+
+	if wanted("skill") {
+		skills, err := s.data.ListSkills()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load skills: %v", err)
+		}
+		for _, sk := range skills {
+			if score := matchScore(sk.Name+" "+sk.Category, terms); score > 0 {
+				results = append(results, SearchResult{Type: "skill", ID: sk.ID, Title: sk.Name, Score: score, Data: sk})
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > 10 {
+		results = results[:10]
+	}
+	return results, nil
+}
+
+func (s *Server) getProject(args toolArguments) (*services.ProjectInfo, error) {
+	if args.ID == 0 && strings.TrimSpace(args.Title) == "" {
+		return nil, fmt.Errorf("id or title is required")
+	}
+
+	projects, err := s.data.ListProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %v", err)
+	}
+
+	for _, p := range projects {
+		if (args.ID != 0 && p.ID == args.ID) || (args.Title != "" && strings.EqualFold(p.Title, strings.TrimSpace(args.Title))) {
+			project := p
+			return &project, nil
+		}
+	}
+	return nil, fmt.Errorf("project not found")
+}
+
+func (s *Server) getExperience(args toolArguments) ([]services.ExpInfo, error) {
+	company := strings.ToLower(strings.TrimSpace(args.Company))
+	if company == "" {
+		return nil, fmt.Errorf("company is required")
+	}
+
+	experiences, err := s.data.ListExperience()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load experience: %v", err)
+	}
+
+	var matches []services.ExpInfo
+	for _, e := range experiences {
+		if strings.Contains(strings.ToLower(e.Company), company) {
+			matches = append(matches, e)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no experience found at %s", args.Company)
+	}
+	return matches, nil
+}
+
+// skillLookup describes a skill and where it was used
+type skillLookup struct {
+	Name       string               `json:"name"`
+	Skills     []services.SkillInfo `json:"skills"`
+	Experience []string             `json:"experience"`
+	Projects   []string             `json:"projects"`
+}
+
+func (s *Server) lookupSkill(args toolArguments) (*skillLookup, error) {
+	name := strings.TrimSpace(args.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	skills, err := s.data.ListSkills()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load skills: %v", err)
+	}
+	experiences, err := s.data.ListExperience()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load experience: %v", err)
+	}
+	projects, err := s.data.ListProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %v", err)
+	}
+
+	lookup := &skillLookup{Name: name, Skills: []services.SkillInfo{}, Experience: []string{}, Projects: []string{}}
+	for _, sk := range skills {
+		if strings.EqualFold(sk.Name, name) {
+			lookup.Skills = append(lookup.Skills, sk)
+		}
+	}
+	for _, e := range experiences {
+		if containsFold(e.Technologies, name) {
+			lookup.Experience = append(lookup.Experience, fmt.Sprintf("%s at %s (%s)", e.Title, e.Company, e.Period))
+		}
+	}
+	for _, p := range projects {
+		if containsFold(p.Technologies, name) {
+			lookup.Projects = append(lookup.Projects, p.Title)
+		}
+	}
+
+	if len(lookup.Skills) == 0 && len(lookup.Experience) == 0 && len(lookup.Projects) == 0 {
+		return nil, fmt.Errorf("no records mention %s", name)
+	}
+	return lookup, nil
+}
+
+// =============================================================================
+// 🛠️ UTILITY FUNCTIONS
+// =============================================================================
+
+// matchScore counts how many query terms appear in text
+func matchScore(text string, terms []string) int {
+	text = strings.ToLower(text)
+	score := 0
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			score++
+		}
+	}
+	return score
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *rpcResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+func encode(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("❌ MCP failed to encode response: %v", err)
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bruno-api/services"
+)
+
+// fakePortfolio is an in-memory PortfolioData implementation
+type fakePortfolio struct{}
+
+func (fakePortfolio) GetAbout() (services.AboutInfo, error) {
+	return services.AboutInfo{Description: "Cloud Native Infrastructure Engineer"}, nil
+}
+
+func (fakePortfolio) GetContact() (services.ContactInfo, error) {
+	return services.ContactInfo{Email: "bruno@lucena.cloud"}, nil
+}
+
+func (fakePortfolio) ListSkills() ([]services.SkillInfo, error) {
+	return []services.SkillInfo{
+		{ID: 1, Name: "Kubernetes", Category: "Cloud", Proficiency: 5},
+		{ID: 2, Name: "Go", Category: "Programming", Proficiency: 5},
+	}, nil
+}
+
+func (fakePortfolio) ListExperience() ([]services.ExpInfo, error) {
+	return []services.ExpInfo{
+		{ID: 7, Title: "SRE/DevOps", Company: "Notifi", Period: "2023-06-01 - Present", Technologies: []string{"Kubernetes", "Knative"}},
+	}, nil
+}
+
+func (fakePortfolio) ListProjects() ([]services.ProjectInfo, error) {
+	return []services.ProjectInfo{
+		{ID: 2, Title: "Knative Lambda", Type: "Serverless", Technologies: []string{"Knative", "Go"}},
+	}, nil
+}
+
+func call(t *testing.T, server *Server, request string) map[string]interface{} {
+	t.Helper()
+	raw := server.HandleMessage(context.Background(), []byte(request))
+	if raw == nil {
+		t.Fatalf("Expected a response for %s", request)
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	return response
+}
+
+func TestInitialize(t *testing.T) {
+	server := NewServer(fakePortfolio{})
+
+	response := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	result := response["result"].(map[string]interface{})
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("Expected negotiated version 2025-03-26, got %v", result["protocolVersion"])
+	}
+
+	response = call(t, server, `{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+	result = response["result"].(map[string]interface{})
+	if result["protocolVersion"] != latestProtocolVersion {
+		t.Errorf("Expected fallback to %s, got %v", latestProtocolVersion, result["protocolVersion"])
+	}
+}
+
+func TestNotificationsHaveNoResponse(t *testing.T) {
+	server := NewServer(fakePortfolio{})
+	if raw := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)); raw != nil {
+		t.Errorf("Expected no response to notification, got %s", raw)
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	server := NewServer(fakePortfolio{})
+	response := call(t, server, `{"jsonrpc":"2.0","id":"a","method":"unknown/method"}`)
+	rpcErr := response["error"].(map[string]interface{})
+	if int(rpcErr["code"].(float64)) != codeMethodNotFound {
+		t.Errorf("Expected method not found, got %v", rpcErr)
+	}
+}
+
+func TestReadResource(t *testing.T) {
+	server := NewServer(fakePortfolio{})
+
+	response := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"portfolio://contact"}}`)
+	contents := response["result"].(map[string]interface{})["contents"].([]interface{})
+	text := contents[0].(map[string]interface{})["text"].(string)
+	if !strings.Contains(text, "bruno@lucena.cloud") {
+		t.Errorf("Expected contact email in resource, got %s", text)
+	}
+
+	response = call(t, server, `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"portfolio://salary"}}`)
+	if response["error"] == nil {
+		t.Error("Expected error for unknown resource")
+	}
+}
+
+func TestToolCalls(t *testing.T) {
+	server := NewServer(fakePortfolio{})
+
+	tests := []struct {
+		name     string
+		params   string
+		contains string
+		isError  bool
+	}{
+		{"Search", `{"name":"search_portfolio","arguments":{"query":"knative"}}`, "Knative Lambda", false},
+		{"Search filtered", `{"name":"search_portfolio","arguments":{"query":"knative","types":["skill"]}}`, "[]", false},
+		{"Project by title", `{"name":"get_project","arguments":{"title":"knative lambda"}}`, "Serverless", false},
+		{"Missing project", `{"name":"get_project","arguments":{"id":99}}`, "project not found", true},
+		{"Experience", `{"name":"get_experience","arguments":{"company":"notifi"}}`, "SRE/DevOps", false},
+		{"Skill lookup", `{"name":"lookup_skill","arguments":{"name":"go"}}`, "Knative Lambda", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := call(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+tt.params+`}`)
+			result := response["result"].(map[string]interface{})
+			text := result["content"].([]interface{})[0].(map[string]interface{})["text"].(string)
+
+			if result["isError"].(bool) != tt.isError {
+				t.Errorf("isError = %v, want %v (%s)", result["isError"], tt.isError, text)
+			}
+			if !strings.Contains(text, tt.contains) {
+				t.Errorf("Expected %q in tool result, got %s", tt.contains, text)
+			}
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	server := NewServer(fakePortfolio{})
+	raw := server.HandleMessage(context.Background(), []byte(`[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`))
+
+	var responses []map[string]interface{}
+	if err := json.Unmarshal(raw, &responses); err != nil {
+		t.Fatalf("Invalid batch response: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Errorf("Expected 2 responses, got %d", len(responses))
+	}
+}
+
+func TestServeStdio(t *testing.T) {
+	server := NewServer(fakePortfolio{})
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n" + `{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n")
+	var out bytes.Buffer
+
+	if err := server.ServeStdio(context.Background(), in, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 1 {
+		t.Errorf("Expected exactly one response line, got %d: %s", lines, out.String())
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	server := NewServer(fakePortfolio{})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "portfolio://projects") {
+		t.Errorf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected 202 for notification, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", w.Code)
+	}
+}
+
+func TestServeHTTPOrigin(t *testing.T) {
+	server := NewServer(fakePortfolio{}).AllowOrigins([]string{"*", " https://lucena.cloud"})
+
+	tests := []struct {
+		origin   string
+		expected int
+	}{
+		{"", http.StatusOK},
+		{"https://lucena.cloud", http.StatusOK},
+		{"http://attacker.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+		if tt.origin != "" {
+			request.Header.Set("Origin", tt.origin)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, request)
+		if w.Code != tt.expected {
+			t.Errorf("Origin %q: expected %d, got %d", tt.origin, tt.expected, w.Code)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// maxMessageSize bounds a single JSON-RPC message read from a transport
+const maxMessageSize = 1024 * 1024
+
+// ServeStdio serves newline-delimited JSON-RPC messages until in is closed.
+// Logs go to stderr so stdout only carries protocol messages.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	log.Printf("🧠 MCP server listening on stdio")
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	writer := bufio.NewWriter(out)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		response := s.HandleMessage(ctx, line)
+		if response == nil {
+			continue
+		}
+		if _, err := writer.Write(append(response, '\n')); err != nil {
+			return err
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// ServeHTTP implements the streamable HTTP transport. Every request is
+// answered with a single JSON response; the server never opens SSE streams.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !s.originAllowed(r.Header.Get("Origin")) {
+		log.Printf("🧠 MCP request from origin %q rejected", r.Header.Get("Origin"))
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	response := s.HandleMessage(r.Context(), body)
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
+
+// originAllowed reports whether a request may use the HTTP transport. Requests
+// without an Origin header come from agents rather than browsers.
+func (s *Server) originAllowed(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range s.allowedOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	// 🤖 LLM services
+	"bruno-api/services"
 )
 
 // =============================================================================
@@ -15,16 +19,34 @@ import (
 
 // requestLogger logs all incoming requests
 func requestLogger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("[%s] %s %s %d %s %s\n",
-			param.TimeStamp.Format(time.RFC3339),
-			param.Method,
-			param.Path,
-			param.StatusCode,
-			param.Latency,
-			param.ClientIP,
-		)
-	})
+	return gin.LoggerWithFormatter(formatRequestLog)
+}
+
+// formatRequestLog formats a request log line. The query string is left out:
+// it carries search queries, session IDs and tokens.
+func formatRequestLog(param gin.LogFormatterParams) string {
+	path, _, _ := strings.Cut(param.Path, "?")
+	return fmt.Sprintf("[%s] %s %s %d %s %s\n",
+		param.TimeStamp.Format(time.RFC3339),
+		param.Method,
+		path,
+		param.StatusCode,
+		param.Latency,
+		redactLog(param.ClientIP),
+	)
+}
+
+// logRedactor returns the PII redactor of the LLM service, nil without one
+func logRedactor() *services.Redactor {
+	if llmService == nil {
+		return nil
+	}
+	return llmService.Redactor()
+}
+
+// redactLog masks PII before it is written to the logs
+func redactLog(text string) string {
+	return logRedactor().Log(text)
 }
 
 // errorHandler handles panics and errors
@@ -0,0 +1,51 @@
+-- Full-text and embedding search across portfolio content
+-- Migration: 002_search.sql
+
+-- array_to_string is only STABLE, so wrap it for use in generated columns
+CREATE OR REPLACE FUNCTION search_text_array(arr TEXT[]) RETURNS TEXT AS $$
+    SELECT coalesce(array_to_string(arr, ' '), '')
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Weighted search vectors: names and titles (A), technologies and categories (B), descriptions (C)
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', search_text_array(technologies)), 'B') ||
+    setweight(to_tsvector('english', coalesce(type, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C')
+) STORED;
+
+ALTER TABLE experience ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '') || ' ' || coalesce(company, '')), 'A') ||
+    setweight(to_tsvector('english', search_text_array(technologies)), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C')
+) STORED;
+
+ALTER TABLE skills ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(category, '')), 'B')
+) STORED;
+
+ALTER TABLE content ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(key, '')), 'A') ||
+    setweight(jsonb_to_tsvector('english', value, '["string"]'), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_search ON projects USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_experience_search ON experience USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_skills_search ON skills USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_content_search ON content USING GIN (search_vector);
+
+-- Document embeddings for optional semantic search, refreshed by the API when content changes
+CREATE TABLE IF NOT EXISTS search_embeddings (
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    snippet TEXT,
+    content_hash VARCHAR(64) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    embedding REAL[] NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (entity_type, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_embeddings_model ON search_embeddings(model);
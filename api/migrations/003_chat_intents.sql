@@ -0,0 +1,55 @@
+-- Intent taxonomy used to route chat questions to portfolio sections
+-- Migration: 003_chat_intents.sql
+
+CREATE TABLE IF NOT EXISTS chat_intents (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    description TEXT,
+    active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Keywords match whole words (and their plural); synonyms behave like extra keywords.
+-- Company names, technologies and project titles are added by the API from the portfolio tables.
+CREATE TABLE IF NOT EXISTS chat_intent_keywords (
+    id SERIAL PRIMARY KEY,
+    intent_id INTEGER NOT NULL REFERENCES chat_intents(id) ON DELETE CASCADE,
+    keyword VARCHAR(100) NOT NULL,
+    synonyms TEXT[] DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (intent_id, keyword)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_intent_keywords_intent ON chat_intent_keywords(intent_id);
+
+DROP TRIGGER IF EXISTS update_chat_intents_updated_at ON chat_intents;
+CREATE TRIGGER update_chat_intents_updated_at BEFORE UPDATE ON chat_intents FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_chat_intent_keywords_updated_at ON chat_intent_keywords;
+CREATE TRIGGER update_chat_intent_keywords_updated_at BEFORE UPDATE ON chat_intent_keywords FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Seed the built-in intents
+INSERT INTO chat_intents (name, description) VALUES
+('contact', 'Contact details and availability'),
+('skills', 'Skills, technologies and tools'),
+('experience', 'Work history and companies'),
+('projects', 'Projects and things built')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO chat_intent_keywords (intent_id, keyword)
+SELECT i.id, k.keyword
+FROM chat_intents i
+JOIN (VALUES
+    ('contact', 'contact'), ('contact', 'email'), ('contact', 'reach'), ('contact', 'hire'),
+    ('contact', 'available'), ('contact', 'linkedin'), ('contact', 'github'),
+    ('skills', 'skill'), ('skills', 'technology'), ('skills', 'tech'), ('skills', 'stack'),
+    ('skills', 'tools'), ('skills', 'languages'), ('skills', 'kubernetes'), ('skills', 'aws'),
+    ('skills', 'go'), ('skills', 'python'), ('skills', 'devops'), ('skills', 'sre'),
+    ('experience', 'experience'), ('experience', 'work'), ('experience', 'job'), ('experience', 'career'),
+    ('experience', 'company'), ('experience', 'role'), ('experience', 'position'), ('experience', 'background'),
+    ('projects', 'project'), ('projects', 'site'), ('projects', 'github'), ('projects', 'build'),
+    ('projects', 'created'), ('projects', 'developed'), ('projects', 'bruno site'), ('projects', 'knative')
+) AS k(intent, keyword) ON k.intent = i.name
+ON CONFLICT (intent_id, keyword) DO NOTHING;
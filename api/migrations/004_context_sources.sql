@@ -0,0 +1,33 @@
+-- Knowledge sources for the chatbot beyond the portfolio tables
+-- Migration: 004_context_sources.sql
+
+-- Frequently asked questions (enable with CONTEXT_SOURCES=...,faq)
+CREATE TABLE IF NOT EXISTS faq_entries (
+    id SERIAL PRIMARY KEY,
+    question TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Uploaded text and Markdown documents (enable with CONTEXT_SOURCES=...,documents)
+CREATE TABLE IF NOT EXISTS context_documents (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    filename VARCHAR(255),
+    content TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_faq_entries_active ON faq_entries(active);
+CREATE INDEX IF NOT EXISTS idx_context_documents_active ON context_documents(active);
+
+DROP TRIGGER IF EXISTS update_faq_entries_updated_at ON faq_entries;
+CREATE TRIGGER update_faq_entries_updated_at BEFORE UPDATE ON faq_entries FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_context_documents_updated_at ON context_documents;
+CREATE TRIGGER update_context_documents_updated_at BEFORE UPDATE ON context_documents FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🧩 OPENAI-COMPATIBLE API
+// =============================================================================
+
+// openAIModelCreated is reported as the creation time of the assistant model
+var openAIModelCreated = time.Now().Unix()
+
+// openAIModelID returns the model name the portfolio assistant is exposed as
+func openAIModelID() string {
+	return getEnv("OPENAI_MODEL_ID", "bruno-portfolio-assistant")
+}
+
+// UnmarshalJSON accepts both plain string content and arrays of text parts
+func (content *OpenAIContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*content = OpenAIContent(text)
+		return nil
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return fmt.Errorf("content must be a string or an array of text parts")
+	}
+
+	var texts []string
+	for _, part := range parts {
+		if part.Type == "text" {
+			texts = append(texts, part.Text)
+		}
+	}
+	*content = OpenAIContent(strings.Join(texts, "\n"))
+	return nil
+}
+
+func handleOpenAIModels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data": []OpenAIModel{
+			{
+				ID:      openAIModelID(),
+				Object:  "model",
+				Created: openAIModelCreated,
+				OwnedBy: "bruno-api",
+			},
+		},
+	})
+}
+
+func handleOpenAIChatCompletions(c *gin.Context) {
+	var request OpenAIChatCompletionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		openAIError(c, http.StatusBadRequest, "invalid_request_error", "Invalid request format: "+err.Error())
+		return
+	}
+
+	if request.Model != "" && request.Model != openAIModelID() {
+		openAIError(c, http.StatusNotFound, "model_not_found", fmt.Sprintf("The model '%s' does not exist", request.Model))
+		return
+	}
+
+	chatRequest, err := toChatRequest(request.Messages)
+	if err != nil {
+		openAIError(c, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	// Check guardrails up front so streamed requests can still get a proper
+	// status code. Earlier user turns are checked too, since they reach the
+	// model as history.
+	for _, message := range append(chatRequest.History, services.ChatMessage{Role: "user", Content: chatRequest.Message}) {
+		if violation := services.CheckGuardrails(message.Content); violation != nil {
+			openAIError(c, http.StatusBadRequest, "invalid_request_error", violation.Message)
+			return
+		}
+	}
+
+	completionID := fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
+	log.Printf("🧩 [%s] OpenAI-compatible completion request (stream=%v, messages=%d)", completionID, request.Stream, len(request.Messages))
+
+	if request.Stream {
+		streamOpenAICompletion(c, completionID, chatRequest)
+		return
+	}
+
+	response, err := llmService.ProcessChatStream(c.Request.Context(), chatRequest, nil)
+	if err != nil {
+		log.Printf("❌ [%s] OpenAI-compatible completion failed: %v", completionID, err)
+		openAIChatError(c, err)
+		return
+	}
+
+	finishReason := "stop"
+	c.JSON(http.StatusOK, OpenAIChatCompletionResponse{
+		ID:      completionID,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   openAIModelID(),
+		Choices: []OpenAIChoice{
+			{
+				Index:        0,
+				Message:      &OpenAIChatMessage{Role: "assistant", Content: OpenAIContent(response.Response)},
+				FinishReason: &finishReason,
+			},
+		},
+	})
+}
+
+// streamOpenAICompletion writes the completion as server-sent chunks
+func streamOpenAICompletion(c *gin.Context, completionID string, chatRequest services.ChatRequest) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	created := time.Now().Unix()
+	writeChunk := func(delta *OpenAIDelta, finishReason *string) error {
+		data, err := json.Marshal(OpenAIChatCompletionResponse{
+			ID:      completionID,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   openAIModelID(),
+			Choices: []OpenAIChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	if err := writeChunk(&OpenAIDelta{Role: "assistant"}, nil); err != nil {
+		return
+	}
+
+	_, err := llmService.ProcessChatStream(c.Request.Context(), chatRequest, func(token string) error {
+		return writeChunk(&OpenAIDelta{Content: token}, nil)
+	})
+	if err != nil {
+		log.Printf("❌ [%s] OpenAI-compatible stream failed: %v", completionID, err)
+		data, _ := json.Marshal(gin.H{"error": gin.H{"message": "Failed to process chat request", "type": "server_error"}})
+		fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+		c.Writer.Flush()
+		return
+	}
+
+	finishReason := "stop"
+	if err := writeChunk(&OpenAIDelta{}, &finishReason); err != nil {
+		return
+	}
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	c.Writer.Flush()
+}
+
+// toChatRequest maps OpenAI messages onto the chat pipeline: the last user
+// message is the question and earlier user turns become the conversation
+// history. Client system prompts and assistant turns are dropped so our own
+// prompt and guardrails apply; a made-up assistant turn could otherwise put
+// words in the model's mouth.
+func toChatRequest(messages []OpenAIChatMessage) (services.ChatRequest, error) {
+	if len(messages) == 0 {
+		return services.ChatRequest{}, errors.New("messages must not be empty")
+	}
+
+	last := messages[len(messages)-1]
+	if last.Role != "user" {
+		return services.ChatRequest{}, errors.New("the last message must have role 'user'")
+	}
+
+	history := []services.ChatMessage{}
+	for _, message := range messages[:len(messages)-1] {
+		if message.Role != "user" {
+			continue
+		}
+		history = append(history, services.ChatMessage{Role: message.Role, Content: string(message.Content)})
+	}
+
+	return services.ChatRequest{
+		Message: string(last.Content),
+		History: history,
+	}, nil
+}
+
+// openAIChatError maps pipeline errors onto OpenAI-style error responses
+func openAIChatError(c *gin.Context, err error) {
+	var violation *services.GuardrailViolation
+	if errors.As(err, &violation) {
+		openAIError(c, http.StatusBadRequest, "invalid_request_error", violation.Message)
+		return
+	}
+	openAIError(c, http.StatusInternalServerError, "server_error", "Failed to process chat request")
+}
+
+// openAIError writes an error in the format OpenAI clients expect
+func openAIError(c *gin.Context, status int, errorType, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"message": message,
+			"type":    errorType,
+		},
+	})
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🚦 CHAT QUOTAS
+// =============================================================================
+
+var chatQuotas *services.QuotaTracker
+
+// quotaResponseHeaders are exposed to browsers through CORS
+var quotaResponseHeaders = []string{
+	"Retry-After",
+	"X-Quota-Messages-Limit", "X-Quota-Messages-Remaining", "X-Quota-Messages-Reset",
+	"X-Quota-Tokens-Limit", "X-Quota-Tokens-Remaining", "X-Quota-Tokens-Reset",
+}
+
+func initChatQuotas() {
+	chatQuotas = services.NewQuotaTracker(redisClient, logRedactor())
+}
+
+// chatQuotaSubject identifies who a chat message is counted against. The
+// session scope only applies to sessions that exist, so made-up session IDs
+// cannot open fresh quota windows.
+func chatQuotaSubject(ctx context.Context, sessionID, ip string) services.QuotaSubject {
+	subject := services.QuotaSubject{IP: ip}
+	if llmService == nil || !services.ValidSessionID(sessionID) {
+		return subject
+	}
+	if exists, err := llmService.Sessions().Exists(ctx, sessionID); err == nil && exists {
+		subject.SessionID = sessionID
+	}
+	return subject
+}
+
+// admitChatMessage counts a chat message against the quotas. Redis errors
+// let the message through so an outage never takes the chat down.
+func admitChatMessage(ctx context.Context, subject services.QuotaSubject) (*services.QuotaStatus, *services.QuotaExceededError) {
+	if chatQuotas == nil {
+		return nil, nil
+	}
+
+	status, err := chatQuotas.Admit(ctx, subject)
+	var exceeded *services.QuotaExceededError
+	if errors.As(err, &exceeded) {
+		log.Printf("🚦 Chat quota %s exceeded for %s (session %q, IP %s)", exceeded.Quota, exceeded.Scope, subject.SessionID, redactLog(subject.IP))
+		return status, exceeded
+	}
+	if err != nil {
+		log.Printf("⚠️ Chat quota check failed, allowing message: %v", err)
+		return nil, nil
+	}
+	return status, nil
+}
+
+// recordChatUsage charges the tokens Ollama reported for an answer; FAQ and
+// budget answers make no model request and are free
+func recordChatUsage(ctx context.Context, subject services.QuotaSubject, response *services.ChatResponse, meter *services.UsageMeter) {
+	// Sessions created by this message count from now on
+	subject.SessionID = response.SessionID
+	recordModelTokens(ctx, subject, meter.Tokens())
+}
+
+// recordModelTokens charges prompt and completion tokens to the token quotas
+func recordModelTokens(ctx context.Context, subject services.QuotaSubject, tokens int) {
+	if chatQuotas == nil || tokens <= 0 {
+		return
+	}
+	if err := chatQuotas.RecordTokens(ctx, subject, tokens); err != nil {
+		log.Printf("⚠️ Failed to record model token usage: %v", err)
+	}
+}
+
+// modelQuota applies the per-IP chat quotas to public endpoints that call the
+// model outside the chat, such as job matching and resume tailoring: every
+// request counts as a message and its model tokens are charged
+func modelQuota() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject := services.QuotaSubject{IP: c.ClientIP()}
+		quota, exceeded := admitChatMessage(c.Request.Context(), subject)
+		setQuotaHeaders(c, quota)
+		if exceeded != nil {
+			rejectOverQuota(c, exceeded)
+			c.Abort()
+			return
+		}
+
+		ctx, meter := services.WithUsageMeter(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		recordModelTokens(ctx, subject, meter.Tokens())
+	}
+}
+
+// recordGuardrailTrip counts a message rejected by a guardrail towards a block
+func recordGuardrailTrip(ctx context.Context, subject services.QuotaSubject) {
+	if chatQuotas == nil {
+		return
+	}
+	if _, err := chatQuotas.RecordGuardrailTrip(ctx, subject); err != nil {
+		log.Printf("⚠️ Failed to record guardrail trip: %v", err)
+	}
+}
+
+// setQuotaHeaders reports the quota state of the most constrained scope
+func setQuotaHeaders(c *gin.Context, status *services.QuotaStatus) {
+	if status == nil {
+		return
+	}
+	for name, usage := range map[string]*services.QuotaUsage{"Messages": status.Messages, "Tokens": status.Tokens} {
+		if usage == nil {
+			continue
+		}
+		c.Header("X-Quota-"+name+"-Limit", strconv.Itoa(usage.Limit))
+		c.Header("X-Quota-"+name+"-Remaining", strconv.Itoa(usage.Remaining))
+		c.Header("X-Quota-"+name+"-Reset", strconv.Itoa(usage.ResetSeconds))
+	}
+}
+
+// rejectOverQuota answers 429 with the time until the quota frees up
+func rejectOverQuota(c *gin.Context, exceeded *services.QuotaExceededError) {
+	retryAfter := ceilSeconds(exceeded.RetryAfter)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       exceeded.Message,
+		"quota":       exceeded.Quota,
+		"scope":       exceeded.Scope,
+		"retry_after": retryAfter,
+	})
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"bruno-api/services"
+)
+
+func TestModelQuotaLimitsMatchRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("CHAT_QUOTA_MESSAGES_PER_HOUR_IP", "2")
+
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer rdb.Close()
+
+	previous := chatQuotas
+	chatQuotas = services.NewQuotaTracker(rdb, nil)
+	t.Cleanup(func() { chatQuotas = previous })
+
+	router := gin.New()
+	router.POST("/api/v1/match", modelQuota(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	codes := []int{}
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v1/match", nil)
+		req.RemoteAddr = "203.0.113.7:41000"
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+		if w.Code == http.StatusOK {
+			assert.NotEmpty(t, w.Header().Get("X-Quota-Messages-Remaining"))
+		} else {
+			assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		}
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
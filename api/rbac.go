@@ -0,0 +1,311 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	// 🔒 Security package
+	"bruno-api/security"
+)
+
+// =============================================================================
+// 🎭 ROLE-BASED ACCESS CONTROL
+// =============================================================================
+
+// Admin roles
+const (
+	roleOwner   = "owner"
+	roleEditor  = "editor"
+	roleAnalyst = "analyst"
+)
+
+// Resources guarded by authorize, one per route group
+const (
+	resourceProjects    = "projects"
+	resourceSkills      = "skills"
+	resourceExperiences = "experiences"
+	resourceContent     = "content" // content blocks, about and contact
+	resourceIntents     = "intents"
+	resourceFAQ         = "faq"
+	resourceDocuments   = "documents"
+	resourceChat        = "chat"     // transcripts and context previews
+	resourceLeads       = "leads"    // visitor contact details
+	resourceTakeover    = "takeover" // live sessions and owner replies
+	resourceAnalytics   = "analytics"
+	resourceAccess      = "access" // admin accounts, roles and the audit log
+)
+
+// Actions, derived from the request method by authorize
+const (
+	actionRead   = "read"
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+const (
+	auditDefaultLimit = 50
+	auditMaxLimit     = 500
+
+	// auditMaxPathLength matches authorization_audit_log.path
+	auditMaxPathLength = 500
+)
+
+// adminRoles lists the roles from most to least privileged
+var adminRoles = []string{roleOwner, roleEditor, roleAnalyst}
+
+// rolePermissions grants actions per resource; owners may do everything
+var rolePermissions = map[string]map[string][]string{
+	roleOwner: {"*": {actionRead, actionCreate, actionUpdate, actionDelete}},
+	roleEditor: {
+		resourceProjects:    {actionRead, actionCreate, actionUpdate},
+		resourceSkills:      {actionRead, actionCreate, actionUpdate},
+		resourceExperiences: {actionRead, actionCreate, actionUpdate},
+		resourceContent:     {actionRead, actionCreate, actionUpdate},
+		resourceIntents:     {actionRead, actionCreate, actionUpdate},
+		resourceFAQ:         {actionRead, actionCreate, actionUpdate},
+		resourceDocuments:   {actionRead, actionCreate, actionUpdate},
+	},
+	roleAnalyst: {
+		resourceAnalytics: {actionRead},
+		resourceChat:      {actionRead},
+	},
+}
+
+// validRole reports whether role is an admin role
+func validRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// roleAllows reports whether a role may perform an action on a resource
+func roleAllows(role, resource, action string) bool {
+	permissions := rolePermissions[role]
+	for _, key := range []string{resource, "*"} {
+		for _, allowed := range permissions[key] {
+			if allowed == action {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// methodAction maps a request method to an action
+func methodAction(method string) string {
+	switch method {
+	case http.MethodPost:
+		return actionCreate
+	case http.MethodPut, http.MethodPatch:
+		return actionUpdate
+	case http.MethodDelete:
+		return actionDelete
+	default:
+		return actionRead
+	}
+}
+
+// authorize requires the admin of a request to have the permission for the
+// resource and the action of the request method. Requests without an admin
+// pass: the authentication middleware has already decided they need none.
+func authorize(resource string) gin.HandlerFunc {
+	return authorizeAction(resource, "")
+}
+
+// authorizeAction is authorize with a fixed action, for routes whose method
+// does not say what they do (e.g. a POST that only reads)
+func authorizeAction(resource, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(adminUserContextKey); !ok {
+			c.Next()
+			return
+		}
+
+		required := action
+		if required == "" {
+			required = methodAction(c.Request.Method)
+		}
+
+		user := currentAdmin(c)
+		if !roleAllows(user.Role, resource, required) {
+			auditAuthorizationFailure(c, user, resource, required)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":    "Forbidden",
+				"resource": resource,
+				"action":   required,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// auditAuthorizationFailure records a denied request in the logs and in
+// authorization_audit_log. The table keeps the client IP unmasked: only owners
+// can read it, and tracing a denied request back is its purpose.
+func auditAuthorizationFailure(c *gin.Context, user *AdminUser, resource, action string) {
+	log.Printf("🚫 Admin %q (%s) denied %s on %s: %s %s", user.Username, user.Role, action, resource, c.Request.Method, c.FullPath())
+	if db == nil {
+		return
+	}
+
+	path := c.Request.URL.Path
+	if len(path) > auditMaxPathLength {
+		path = path[:auditMaxPathLength]
+	}
+
+	var userID sql.NullInt64
+	if user.ID > 0 {
+		userID = sql.NullInt64{Int64: int64(user.ID), Valid: true}
+	}
+	if _, err := db.ExecContext(c.Request.Context(), `
+		INSERT INTO authorization_audit_log (user_id, username, role, resource, action, method, path, client_ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, userID, user.Username, user.Role, resource, action, c.Request.Method, path, c.ClientIP()); err != nil {
+		log.Printf("⚠️ Failed to audit-log authorization failure: %v", err)
+	}
+}
+
+// =============================================================================
+// 🎭 ROLE ADMINISTRATION
+// =============================================================================
+
+// getAdminRoles lists the roles with their permissions
+func getAdminRoles(c *gin.Context) {
+	roles := make([]gin.H, 0, len(adminRoles))
+	for _, role := range adminRoles {
+		roles = append(roles, gin.H{"role": role, "permissions": rolePermissions[role]})
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// updateAdminUserRole assigns a role to an admin account. The last active
+// owner cannot be demoted, so there is always someone to manage roles.
+func updateAdminUserRole(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	id, validationErr := security.ValidateInteger(c.Param("id"), "id", 1, 999999)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message})
+		return
+	}
+
+	var request AdminRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !validRole(request.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be one of " + strings.Join(adminRoles, ", ")})
+		return
+	}
+
+	tx, err := db.BeginTx(c.Request.Context(), nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+	defer tx.Rollback()
+
+	// Lock the owners so two demotions cannot both pass the last-owner check
+	rows, err := tx.Query(`SELECT id FROM admin_users WHERE role = $1 AND active = true FOR UPDATE`, roleOwner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+	owners := map[int]bool{}
+	for rows.Next() {
+		var ownerID int
+		if err := rows.Scan(&ownerID); err == nil {
+			owners[ownerID] = true
+		}
+	}
+	rows.Close()
+
+	if request.Role != roleOwner && owners[id] && len(owners) == 1 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot demote the last owner"})
+		return
+	}
+
+	var user AdminUser
+	err = tx.QueryRow(`
+		UPDATE admin_users SET role = $1 WHERE id = $2
+		RETURNING id, username, role, active, last_login_at, created_at
+	`, request.Role, id).Scan(&user.ID, &user.Username, &user.Role, &user.Active, &user.LastLoginAt, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	log.Printf("🎭 Admin %q is now %s (assigned by %q)", user.Username, user.Role, currentAdmin(c).Username)
+	c.JSON(http.StatusOK, user)
+}
+
+// getAuthorizationAuditLog lists the latest authorization failures
+func getAuthorizationAuditLog(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(auditDefaultLimit)))
+	if err != nil || limit < 1 || limit > auditMaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(auditMaxLimit)})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, user_id, username, role, resource, action, method, path, COALESCE(client_ip, ''), created_at
+		FROM authorization_audit_log
+		ORDER BY created_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit log"})
+		return
+	}
+	defer rows.Close()
+
+	entries := []AuthorizationAuditEntry{}
+	for rows.Next() {
+		var entry AuthorizationAuditEntry
+		if err := rows.Scan(&entry.ID, &entry.UserID, &entry.Username, &entry.Role, &entry.Resource, &entry.Action,
+			&entry.Method, &entry.Path, &entry.ClientIP, &entry.CreatedAt); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// rolePermissionList flattens the permissions of a role into "resource:action" entries
+func rolePermissionList(role string) []string {
+	permissions := []string{}
+	for resource, actions := range rolePermissions[role] {
+		for _, action := range actions {
+			permissions = append(permissions, resource+":"+action)
+		}
+	}
+	sort.Strings(permissions)
+	return permissions
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		resource string
+		action   string
+		expected bool
+	}{
+		{roleOwner, resourceProjects, actionDelete, true},
+		{roleOwner, resourceAccess, actionUpdate, true},
+		{roleEditor, resourceProjects, actionUpdate, true},
+		{roleEditor, resourceProjects, actionDelete, false},
+		{roleEditor, resourceFAQ, actionCreate, true},
+		{roleEditor, resourceAnalytics, actionRead, false},
+		{roleEditor, resourceAccess, actionRead, false},
+		{roleAnalyst, resourceAnalytics, actionRead, true},
+		{roleAnalyst, resourceChat, actionRead, true},
+		{roleAnalyst, resourceChat, actionUpdate, false},
+		{roleAnalyst, resourceLeads, actionRead, false},
+		{roleAnalyst, resourceTakeover, actionRead, false},
+		{roleOwner, resourceLeads, actionUpdate, true},
+		{roleOwner, resourceTakeover, actionCreate, true},
+		{roleAnalyst, resourceProjects, actionRead, false},
+		{"", resourceProjects, actionRead, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, roleAllows(tt.role, tt.resource, tt.action), "%s %s %s", tt.role, tt.action, tt.resource)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	previousDB := db
+	defer func() { db = previousDB }()
+	db = nil
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	withRole := func(c *gin.Context) {
+		if role := c.GetHeader("X-Test-Role"); role != "" {
+			c.Set(adminUserContextKey, &AdminUser{Username: "tester", Role: role, Active: true})
+		}
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	projects := router.Group("/projects", withRole, authorize(resourceProjects))
+	projects.GET("", ok)
+	projects.PUT("/:id", ok)
+	projects.DELETE("/:id", ok)
+	preview := router.Group("/chat", withRole, authorizeAction(resourceChat, actionRead))
+	preview.POST("/preview", ok)
+	router.GET("/leads", withRole, authorize(resourceLeads), ok)
+	router.POST("/live/sessions/:id/claim", withRole, authorize(resourceTakeover), ok)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		role     string
+		expected int
+	}{
+		{"public read without admin", http.MethodGet, "/projects", "", http.StatusOK},
+		{"editor updates project", http.MethodPut, "/projects/1", roleEditor, http.StatusOK},
+		{"editor cannot delete project", http.MethodDelete, "/projects/1", roleEditor, http.StatusForbidden},
+		{"owner deletes project", http.MethodDelete, "/projects/1", roleOwner, http.StatusOK},
+		{"analyst cannot read projects", http.MethodGet, "/projects", roleAnalyst, http.StatusForbidden},
+		{"analyst previews chat", http.MethodPost, "/chat/preview", roleAnalyst, http.StatusOK},
+		{"editor cannot preview chat", http.MethodPost, "/chat/preview", roleEditor, http.StatusForbidden},
+		{"analyst cannot read leads", http.MethodGet, "/leads", roleAnalyst, http.StatusForbidden},
+		{"analyst cannot claim sessions", http.MethodPost, "/live/sessions/1/claim", roleAnalyst, http.StatusForbidden},
+		{"owner reads leads", http.MethodGet, "/leads", roleOwner, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.role != "" {
+				req.Header.Set("X-Test-Role", tt.role)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code, w.Body.String())
+		})
+	}
+}
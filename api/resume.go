@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =============================================================================
+// 📄 TAILORED RESUME
+// =============================================================================
+
+// handleTailoredResume returns a resume variant for a job description as JSON
+// (including the Markdown rendering) or, with ?format=markdown, as Markdown only
+func handleTailoredResume(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "markdown" && format != "md" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be 'json' or 'markdown'"})
+		return
+	}
+
+	var request ResumeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	jobDescription, ok := validateJobDescription(c, request.JobDescription)
+	if !ok {
+		return
+	}
+
+	resume, err := resumeTailor.Tailor(c.Request.Context(), jobDescription, request.Rewrite)
+	if err != nil {
+		log.Printf("❌ Tailored resume generation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate resume"})
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, resume)
+		return
+	}
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(resume.Markdown))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🔎 SEARCH
+// =============================================================================
+
+const (
+	searchDefaultLimit   = 10
+	searchMaxLimit       = 50
+	searchMaxQueryLength = 200
+)
+
+// freeTextQueryParams are exempt from the SQL injection patterns: visitors
+// search for "projects from 2023" or "where did he work", and the query is
+// only passed to Postgres as a bound argument
+var freeTextQueryParams = map[string][]string{
+	"/api/v1/search": {"q"},
+	"/api/search":    {"q"},
+}
+
+func initSearch() {
+	searchService = services.NewSearchService(db, llmService.Usage())
+
+	// Keep document embeddings in sync with the portfolio in the background
+	if searchService.SemanticEnabled() {
+		go searchService.RunEmbeddingIndexer(context.Background())
+	}
+}
+
+func handleSearch(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		return
+	}
+	if utf8.RuneCountInString(query) > searchMaxQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query must be " + strconv.Itoa(searchMaxQueryLength) + " characters or less"})
+		return
+	}
+
+	types, err := services.ParseSearchTypes(c.Query("types"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(searchDefaultLimit)))
+	if err != nil || limit < 1 || limit > searchMaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(searchMaxLimit)})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	response, err := searchService.Search(c.Request.Context(), services.SearchOptions{
+		Query:  query,
+		Types:  types,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Printf("❌ Search failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
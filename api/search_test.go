@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"bruno-api/security"
+	"bruno-api/services"
+)
+
+func TestSearchAcceptsSQLKeywords(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	previous := searchService
+	searchService = services.NewSearchService(nil, nil)
+	t.Cleanup(func() { searchService = previous })
+
+	router := gin.New()
+	router.Use(security.SQLInjectionProtectionMiddleware(freeTextQueryParams))
+	router.GET("/api/v1/search", handleSearch)
+
+	for _, query := range []string{"projects from 2023", "where did he work", "select skills"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/search?q="+url.QueryEscape(query), nil)
+		router.ServeHTTP(w, req)
+
+		// Without a database the search itself fails, after the query was accepted
+		assert.Equal(t, http.StatusInternalServerError, w.Code, query)
+		assert.Contains(t, w.Body.String(), "Failed to search", query)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/search?q=go&types="+url.QueryEscape("select skills"), nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "other parameters are still checked")
+}
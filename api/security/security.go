@@ -8,6 +8,7 @@ import (
 	"html"
 	"net/http"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -44,6 +45,9 @@ type SecurityConfig struct {
 	AllowedOrigins    []string
 	EnableCSP         bool
 	CSPPolicy         string
+	OpenAIAPIKeys     []string
+	AdminAPIKeys      []string
+	TrustedProxies    []string
 }
 
 // =============================================================================
@@ -280,6 +284,22 @@ func SecureCompare(a, b string) bool {
 	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
 
+// ParseAPIKeys splits a comma-separated list of API keys, dropping empty entries
+func ParseAPIKeys(raw string) []string {
+	return ParseList(raw)
+}
+
+// ParseList splits a comma-separated setting, trimming and dropping empty entries
+func ParseList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // =============================================================================
 // 🛡️ SECURITY MIDDLEWARE
 // =============================================================================
@@ -312,6 +332,45 @@ func MetricsAuthMiddleware(config SecurityConfig) gin.HandlerFunc {
 	}
 }
 
+// APIKeyAuthMiddleware requires a valid API key sent as a Bearer token or X-API-Key header
+func APIKeyAuthMiddleware(keys []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(keys) == 0 {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API key authentication is not configured"})
+			c.Abort()
+			return
+		}
+
+		apiKey := c.GetHeader("X-API-Key")
+		if authHeader := c.GetHeader("Authorization"); apiKey == "" && strings.HasPrefix(authHeader, "Bearer ") {
+			apiKey = strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+		}
+
+		if apiKey == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="API"`)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			c.Abort()
+			return
+		}
+
+		// Compare against every key so timing does not reveal which one matched
+		valid := false
+		for _, key := range keys {
+			if SecureCompare(apiKey, key) {
+				valid = true
+			}
+		}
+
+		if !valid {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // EnhancedSecurityHeaders adds comprehensive security headers
 func EnhancedSecurityHeaders(config SecurityConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -346,11 +405,18 @@ func RateLimitMiddleware() gin.HandlerFunc {
 	}
 }
 
-// SQLInjectionProtectionMiddleware adds additional SQL injection protection
-func SQLInjectionProtectionMiddleware() gin.HandlerFunc {
+// SQLInjectionProtectionMiddleware adds additional SQL injection protection.
+// freeText maps request paths to query parameters that carry free text, such
+// as search queries, which are skipped: "where did he work" is not an attack,
+// and their handlers only use them as bound query arguments.
+func SQLInjectionProtectionMiddleware(freeText map[string][]string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check query parameters for SQL injection patterns
-		for _, values := range c.Request.URL.Query() {
+		exempt := freeText[c.Request.URL.Path]
+		for name, values := range c.Request.URL.Query() {
+			if slices.Contains(exempt, name) {
+				continue
+			}
 			for _, value := range values {
 				if containsSQLInjectionPattern(value) {
 					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input detected"})
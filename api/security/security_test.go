@@ -1,8 +1,12 @@
 package security
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/gin-gonic/gin"
 )
 
 // =============================================================================
@@ -402,6 +406,114 @@ func TestSecureCompare(t *testing.T) {
 	}
 }
 
+func TestParseAPIKeys(t *testing.T) {
+	keys := ParseAPIKeys(" key-one, ,key-two,")
+	if len(keys) != 2 || keys[0] != "key-one" || keys[1] != "key-two" {
+		t.Errorf("ParseAPIKeys returned %v", keys)
+	}
+
+	if keys := ParseAPIKeys(""); len(keys) != 0 {
+		t.Errorf("ParseAPIKeys(\"\") = %v, want empty", keys)
+	}
+}
+
+func TestAPIKeyAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		keys     []string
+		header   string
+		value    string
+		expected int
+	}{
+		{
+			name:     "Valid bearer token",
+			keys:     []string{"secret-key"},
+			header:   "Authorization",
+			value:    "Bearer secret-key",
+			expected: http.StatusOK,
+		},
+		{
+			name:     "Valid X-API-Key header",
+			keys:     []string{"other-key", "secret-key"},
+			header:   "X-API-Key",
+			value:    "secret-key",
+			expected: http.StatusOK,
+		},
+		{
+			name:     "Invalid key",
+			keys:     []string{"secret-key"},
+			header:   "Authorization",
+			value:    "Bearer wrong-key",
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:     "Missing key",
+			keys:     []string{"secret-key"},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:     "No keys configured",
+			keys:     nil,
+			header:   "Authorization",
+			value:    "Bearer secret-key",
+			expected: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/protected", APIKeyAuthMiddleware(tt.keys), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("status = %d, want %d", w.Code, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSQLInjectionProtectionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(SQLInjectionProtectionMiddleware(map[string][]string{"/search": {"q"}}))
+	router.GET("/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/projects", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name     string
+		url      string
+		expected int
+	}{
+		{"Free text parameter", "/search?q=projects+from+2023", http.StatusOK},
+		{"Other parameter of a free text route", "/search?q=go&types=select+skills", http.StatusBadRequest},
+		{"Same parameter on another route", "/projects?q=where+did+he+work", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("status = %d, want %d", w.Code, tt.expected)
+			}
+		})
+	}
+}
+
 // =============================================================================
 // 🏃‍♂️ BENCHMARK TESTS
 // =============================================================================
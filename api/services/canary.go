@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// defaultCanaries are the canary questions used when CANARY_FILE is not set
+//
+//go:embed data/canaries.json
+var defaultCanaries []byte
+
+var (
+	canaryUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "chat_canary_up",
+		Help: "Whether the last run of a canary question found all expected facts (1) or not (0)",
+	}, []string{"canary"})
+	canaryRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "chat_canary_runs_total",
+		Help: "Canary question runs by result (pass, fail, error)",
+	}, []string{"canary", "result"})
+	canaryLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "chat_canary_latency_seconds",
+		Help:    "Time to answer a canary question through the full chat pipeline",
+		Buckets: []float64{0.5, 1, 2, 5, 10, 20, 30, 60},
+	}, []string{"canary"})
+)
+
+// canaryStartupDelay is how long the prober waits before its first run
+const canaryStartupDelay = time.Minute
+
+// Canary is a question with facts its answer must contain
+type Canary struct {
+	Name      string   `json:"name"`
+	Question  string   `json:"question"`
+	Context   string   `json:"context,omitempty"`
+	Expect    []string `json:"expect,omitempty"`     // all must appear
+	ExpectAny []string `json:"expect_any,omitempty"` // at least one must appear
+}
+
+// CanaryResult is the outcome of the last run of a canary
+type CanaryResult struct {
+	Name      string    `json:"name"`
+	Question  string    `json:"question"`
+	Passed    bool      `json:"passed"`
+	Missing   []string  `json:"missing,omitempty"`
+	Answer    string    `json:"answer,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	LatencyMs int       `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// CanaryStatus is what the prober knows about the last run
+type CanaryStatus struct {
+	Interval string         `json:"interval"`
+	LastRun  *time.Time     `json:"last_run"`
+	Passed   int            `json:"passed"`
+	Failed   int            `json:"failed"`
+	Results  []CanaryResult `json:"results"`
+}
+
+// CanaryProber periodically asks the canary questions through the chat pipeline
+type CanaryProber struct {
+	llm      *LLMService
+	canaries []Canary
+	interval time.Duration
+	timeout  time.Duration
+
+	runMu   sync.Mutex
+	mu      sync.RWMutex
+	results []CanaryResult
+	lastRun time.Time
+}
+
+// NewCanaryProber loads the canaries from CANARY_FILE or the built-in set.
+// CANARY_INTERVAL_MINUTES sets how often they run; 0 disables periodic runs.
+func NewCanaryProber(llm *LLMService) (*CanaryProber, error) {
+	data := defaultCanaries
+	if path := getEnv("CANARY_FILE", ""); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read canaries: %v", err)
+		}
+		data = content
+	}
+
+	canaries, err := ParseCanaries(data)
+	if err != nil {
+		return nil, err
+	}
+
+	minutes, err := strconv.Atoi(getEnv("CANARY_INTERVAL_MINUTES", "15"))
+	if err != nil || minutes < 0 {
+		minutes = 15
+	}
+
+	return &CanaryProber{
+		llm:      llm,
+		canaries: canaries,
+		interval: time.Duration(minutes) * time.Minute,
+		timeout:  90 * time.Second,
+	}, nil
+}
+
+// ParseCanaries reads a JSON list of canaries and validates them
+func ParseCanaries(data []byte) ([]Canary, error) {
+	var canaries []Canary
+	if err := json.Unmarshal(data, &canaries); err != nil {
+		return nil, fmt.Errorf("invalid canaries: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for i, canary := range canaries {
+		switch {
+		case canary.Name == "" || canary.Question == "":
+			return nil, fmt.Errorf("canary %d needs a name and a question", i+1)
+		case names[canary.Name]:
+			return nil, fmt.Errorf("duplicate canary %q", canary.Name)
+		case len(canary.Expect) == 0 && len(canary.ExpectAny) == 0:
+			return nil, fmt.Errorf("canary %q expects no facts", canary.Name)
+		}
+		if _, err := ParseChatMode(canary.Context); err != nil {
+			return nil, fmt.Errorf("canary %q: %v", canary.Name, err)
+		}
+		names[canary.Name] = true
+	}
+	return canaries, nil
+}
+
+// Run probes every interval until ctx is cancelled
+func (p *CanaryProber) Run(ctx context.Context) {
+	if p.interval <= 0 {
+		log.Printf("🐤 Canary prober disabled (CANARY_INTERVAL_MINUTES=0)")
+		return
+	}
+	log.Printf("🐤 Canary prober started: %d questions every %v", len(p.canaries), p.interval)
+
+	// The first run waits for the model and the database to come up
+	next := time.After(canaryStartupDelay)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-next:
+		}
+		p.RunOnce(ctx)
+		next = time.After(p.interval)
+	}
+}
+
+// RunOnce asks every canary question and records the results. Concurrent
+// calls wait for the running probe instead of doubling the load.
+func (p *CanaryProber) RunOnce(ctx context.Context) []CanaryResult {
+	p.runMu.Lock()
+	defer p.runMu.Unlock()
+
+	results := make([]CanaryResult, 0, len(p.canaries))
+	failed := 0
+	for _, canary := range p.canaries {
+		result := p.probe(ctx, canary)
+		if !result.Passed {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	p.mu.Lock()
+	p.results = results
+	p.lastRun = time.Now()
+	p.mu.Unlock()
+
+	log.Printf("🐤 Canary run finished: %d/%d passed", len(results)-failed, len(results))
+	return results
+}
+
+// probe asks a single canary question and checks the expected facts
+func (p *CanaryProber) probe(ctx context.Context, canary Canary) CanaryResult {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	result := CanaryResult{Name: canary.Name, Question: canary.Question}
+	startTime := time.Now()
+	response, err := p.llm.ProcessChatStream(ctx, ChatRequest{
+		Message: canary.Question,
+		Context: canary.Context,
+		History: []ChatMessage{}, // stateless, nothing is stored
+		Probe:   true,
+	}, nil)
+	latency := time.Since(startTime)
+	result.LatencyMs = int(latency.Milliseconds())
+	result.CheckedAt = time.Now().UTC()
+	canaryLatency.WithLabelValues(canary.Name).Observe(latency.Seconds())
+
+	if err != nil {
+		result.Error = err.Error()
+		canaryUp.WithLabelValues(canary.Name).Set(0)
+		canaryRuns.WithLabelValues(canary.Name, "error").Inc()
+		log.Printf("🐤 Canary %s errored: %v", canary.Name, err)
+		return result
+	}
+
+	result.Answer = response.Response
+	result.Source = response.Source
+	result.Missing = canary.missingFacts(response.Response)
+	result.Passed = len(result.Missing) == 0
+
+	if result.Passed {
+		canaryUp.WithLabelValues(canary.Name).Set(1)
+		canaryRuns.WithLabelValues(canary.Name, "pass").Inc()
+	} else {
+		canaryUp.WithLabelValues(canary.Name).Set(0)
+		canaryRuns.WithLabelValues(canary.Name, "fail").Inc()
+		log.Printf("🐤 Canary %s failed, missing %s: %s", canary.Name, strings.Join(result.Missing, ", "), truncateString(response.Response, 200))
+	}
+	return result
+}
+
+// missingFacts returns the expected facts the answer lacks, ignoring case
+func (c Canary) missingFacts(answer string) []string {
+	answer = strings.ToLower(answer)
+
+	var missing []string
+	for _, fact := range c.Expect {
+		if !strings.Contains(answer, strings.ToLower(fact)) {
+			missing = append(missing, fact)
+		}
+	}
+	if len(c.ExpectAny) > 0 {
+		found := false
+		for _, fact := range c.ExpectAny {
+			if strings.Contains(answer, strings.ToLower(fact)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, "one of: "+strings.Join(c.ExpectAny, ", "))
+		}
+	}
+	return missing
+}
+
+// Status returns the results of the last run
+func (p *CanaryProber) Status() CanaryStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	status := CanaryStatus{Interval: p.interval.String(), Results: []CanaryResult{}}
+	if !p.lastRun.IsZero() {
+		lastRun := p.lastRun.UTC()
+		status.LastRun = &lastRun
+	}
+	for _, result := range p.results {
+		if result.Passed {
+			status.Passed++
+		} else {
+			status.Failed++
+		}
+		status.Results = append(status.Results, result)
+	}
+	return status
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCanaries(t *testing.T) {
+	canaries, err := ParseCanaries(defaultCanaries)
+	if err != nil || len(canaries) == 0 {
+		t.Fatalf("Expected the built-in canaries to be valid, got %d canaries, error %v", len(canaries), err)
+	}
+
+	invalid := map[string]string{
+		`[{"name": "a", "question": "Where?"}]`: "expects no facts",
+		`[{"name": "a", "question": "Where?", "expect": ["x"]}, {"name": "a", "question": "Who?", "expect": ["y"]}]`: "duplicate",
+		`[{"question": "Where?", "expect": ["x"]}]`:                                   "needs a name",
+		`[{"name": "a", "question": "Where?", "context": "pirate", "expect": ["x"]}]`: "invalid chat context",
+	}
+	for data, want := range invalid {
+		if _, err := ParseCanaries([]byte(data)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseCanaries(%s) error = %v, want %q", data, err, want)
+		}
+	}
+}
+
+func TestCanaryMissingFacts(t *testing.T) {
+	canary := Canary{Expect: []string{"bruno@lucena.cloud"}, ExpectAny: []string{"React", "Go"}}
+
+	if missing := canary.missingFacts("Email BRUNO@lucena.cloud, built with react."); len(missing) != 0 {
+		t.Errorf("Expected all facts to be found, missing %v", missing)
+	}
+	if missing := canary.missingFacts("Built with Vue."); len(missing) != 2 {
+		t.Errorf("Expected the email and one of the technologies to be missing, got %v", missing)
+	}
+}
+
+func TestCanaryProberRecordsResults(t *testing.T) {
+	llm := &LLMService{
+		contextBuilder: &ContextBuilder{},
+		sessions:       NewSessionStore(nil, nil),
+		faqs:           newTestFAQMatcher(FAQEntry{ID: 1, Question: "Where is Bruno based?", Answer: "Bruno is based in Brazil."}),
+	}
+	prober := &CanaryProber{llm: llm, canaries: []Canary{
+		{Name: "location", Question: "Where is Bruno based?", Expect: []string{"Brazil"}},
+		{Name: "wrong-location", Question: "Where is Bruno based?", Expect: []string{"Berlin"}},
+	}, timeout: time.Second}
+
+	results := prober.RunOnce(context.Background())
+	if len(results) != 2 || !results[0].Passed || results[1].Passed {
+		t.Fatalf("Expected the first canary to pass and the second to fail, got %+v", results)
+	}
+	if results[0].Source != ResponseSourceFAQ {
+		t.Errorf("Expected the answer source to be reported, got %q", results[0].Source)
+	}
+
+	status := prober.Status()
+	if status.LastRun == nil || status.Passed != 1 || status.Failed != 1 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Chat modes selected with ChatRequest.Context
+const (
+	ChatModeDefault   = "default"
+	ChatModeRecruiter = "recruiter"
+	ChatModeTechnical = "technical"
+	ChatModeCasual    = "casual"
+	ChatModeProject   = "project"
+)
+
+// sectionProjectFocus heads the details of the project a project mode is about
+const sectionProjectFocus = "PROJECT DETAILS"
+
+// ChatMode is a persona that shapes the prompt, the context and the answer length
+type ChatMode struct {
+	Name      string `json:"name"`
+	ProjectID int    `json:"project_id,omitempty"`
+
+	// SystemPrompt replaces the default system message
+	SystemPrompt string `json:"system_prompt"`
+	// Instruction opens the context prompt and Reminder closes it
+	Instruction string `json:"instruction"`
+	Reminder    string `json:"reminder"`
+	// Focus intents are always selected, in addition to the classified ones
+	Focus []string `json:"focus,omitempty"`
+	// Sections orders the snippets by source; sources not listed follow
+	// in their configured order
+	Sections []string `json:"sections,omitempty"`
+	// MaxSentences is stated in the prompt; MaxTokens caps the generation
+	// (0 leaves it to the model)
+	MaxSentences int `json:"max_sentences"`
+	MaxTokens    int `json:"max_tokens,omitempty"`
+}
+
+// ChatModeError reports a ChatRequest.Context value that selects no mode
+type ChatModeError struct {
+	Mode   string `json:"mode"`
+	Reason string `json:"reason"`
+}
+
+func (e *ChatModeError) Error() string {
+	return fmt.Sprintf("invalid chat context %q: %s", e.Mode, e.Reason)
+}
+
+// chatModes are the named personas; project modes are derived from chatModes[ChatModeProject]
+var chatModes = map[string]ChatMode{
+	ChatModeDefault: {
+		SystemPrompt: chatSystemPrompt,
+		Instruction:  "SYSTEM: Answer questions directly with facts. NO greetings, NO introductions. Maximum 2 sentences.",
+		Reminder:     "CRITICAL: Keep responses SHORT and DIRECT. Maximum 2-3 sentences only.",
+		MaxSentences: 2,
+	},
+	ChatModeRecruiter: {
+		SystemPrompt: "You are Bruno's assistant speaking to a recruiter. NEVER use greetings or pleasantries. Lead with roles, companies, impact and availability. Use only the facts provided. Maximum 3 sentences.",
+		Instruction:  "SYSTEM: Answer for a recruiter: current role, relevant experience, key skills and availability first. NO greetings. Maximum 3 sentences.",
+		Reminder:     "CRITICAL: Be concise and factual. Maximum 3 sentences.",
+		Focus:        []string{IntentExperience, IntentSkills},
+		Sections:     []string{ContextSourceExperience, ContextSourceSkills, ContextSourceContact, ContextSourceProjects, ContextSourceAbout},
+		MaxSentences: 3,
+		MaxTokens:    160,
+	},
+	ChatModeTechnical: {
+		SystemPrompt: "You are a technical assistant answering engineers' questions about Bruno's work. Be precise: name tools, architectures and trade-offs. Use only the facts provided. NEVER use greetings. Maximum 5 sentences.",
+		Instruction:  "SYSTEM: Answer with technical depth: technologies, architecture and how they were used. NO greetings. Maximum 5 sentences.",
+		Reminder:     "CRITICAL: Stay precise and grounded in the context. Maximum 5 sentences.",
+		Focus:        []string{IntentSkills, IntentProjects},
+		Sections:     []string{ContextSourceSkills, ContextSourceProjects, ContextSourceExperience, ContextSourceAbout},
+		MaxSentences: 5,
+		MaxTokens:    320,
+	},
+	ChatModeCasual: {
+		SystemPrompt: "You are a friendly assistant chatting about Bruno's work. Keep a relaxed, conversational tone, but use only the facts provided. Maximum 3 sentences.",
+		Instruction:  "SYSTEM: Answer in a friendly, conversational tone using the facts below. Maximum 3 sentences.",
+		Reminder:     "CRITICAL: Keep it light and short. Maximum 3 sentences.",
+		Sections:     []string{ContextSourceAbout, ContextSourceProjects, ContextSourceSkills, ContextSourceExperience},
+		MaxSentences: 3,
+		MaxTokens:    160,
+	},
+	ChatModeProject: {
+		SystemPrompt: "You are a technical assistant answering questions about one of Bruno's projects. Focus on that project: its purpose, technologies and links. Use only the facts provided. NEVER use greetings. Maximum 4 sentences.",
+		Instruction:  "SYSTEM: Answer about the project in PROJECT DETAILS. Mention other work only when asked. NO greetings. Maximum 4 sentences.",
+		Reminder:     "CRITICAL: Stay focused on this project. Maximum 4 sentences.",
+		Focus:        []string{IntentSkills},
+		Sections:     []string{ContextSourceProjects, ContextSourceSkills, ContextSourceAbout},
+		MaxSentences: 4,
+		MaxTokens:    240,
+	},
+}
+
+// ChatModeNames lists the values accepted in ChatRequest.Context
+func ChatModeNames() []string {
+	return []string{ChatModeRecruiter, ChatModeTechnical, ChatModeCasual, ChatModeProject + ":<id>"}
+}
+
+// DefaultChatMode returns the mode used when no context is given
+func DefaultChatMode() ChatMode {
+	mode := chatModes[ChatModeDefault]
+	mode.Name = ChatModeDefault
+	return mode
+}
+
+// ParseChatMode resolves a ChatRequest.Context value. An empty value selects
+// the default mode; "project:<id>" selects the project mode for that project.
+func ParseChatMode(value string) (ChatMode, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return DefaultChatMode(), nil
+	}
+
+	name, argument, hasArgument := strings.Cut(normalized, ":")
+	if name == ChatModeProject {
+		id, err := strconv.Atoi(strings.TrimSpace(argument))
+		if !hasArgument || err != nil || id <= 0 {
+			return ChatMode{}, &ChatModeError{Mode: value, Reason: "project mode needs a project id, e.g. project:3"}
+		}
+		mode := chatModes[ChatModeProject]
+		mode.Name = ChatModeProject
+		mode.ProjectID = id
+		return mode, nil
+	}
+
+	mode, ok := chatModes[name]
+	if !ok || hasArgument || name == ChatModeDefault {
+		return ChatMode{}, &ChatModeError{
+			Mode:   value,
+			Reason: "unknown mode, expected one of " + strings.Join(ChatModeNames(), ", "),
+		}
+	}
+	mode.Name = name
+	return mode, nil
+}
+
+// String returns the value that selects the mode
+func (m ChatMode) String() string {
+	if m.ProjectID > 0 {
+		return fmt.Sprintf("%s:%d", m.Name, m.ProjectID)
+	}
+	return m.Name
+}
+
+// ollamaOptions returns the generation limits of the mode, or nil when it has none
+func (m ChatMode) ollamaOptions() *OllamaOptions {
+	if m.MaxTokens <= 0 {
+		return nil
+	}
+	return &OllamaOptions{NumPredict: m.MaxTokens}
+}
+
+// focusIntents marks the focus intents of the mode as selected
+func (m ChatMode) focusIntents(scores IntentScores) IntentScores {
+	for _, intent := range m.Focus {
+		for i := range scores {
+			if scores[i].Intent == intent {
+				scores[i].Selected = true
+			}
+		}
+	}
+	return scores
+}
+
+// prioritize orders snippets by the section priorities of the mode, keeping
+// the source order within a section
+func (m ChatMode) prioritize(snippets []ContextSnippet) []ContextSnippet {
+	if len(m.Sections) == 0 {
+		return snippets
+	}
+
+	rank := make(map[string]int, len(m.Sections))
+	for i, source := range m.Sections {
+		rank[source] = i
+	}
+	priority := func(snippet ContextSnippet) int {
+		if snippet.Section == sectionProjectFocus {
+			return -1
+		}
+		if r, ok := rank[snippet.Source]; ok {
+			return r
+		}
+		return len(m.Sections)
+	}
+
+	sort.SliceStable(snippets, func(i, j int) bool {
+		return priority(snippets[i]) < priority(snippets[j])
+	})
+	return snippets
+}
+
+// projectFocusSnippet renders everything known about the project of a project mode
+func projectFocusSnippet(project ProjectInfo) ContextSnippet {
+	lines := []string{fmt.Sprintf("- %s (%s)", project.Title, project.Type)}
+	if project.Description != "" {
+		lines = append(lines, "  Description: "+project.Description)
+	}
+	if len(project.Technologies) > 0 {
+		lines = append(lines, "  Tech: "+strings.Join(project.Technologies, ", "))
+	}
+	if project.GithubURL != "" {
+		lines = append(lines, "  GitHub: "+project.GithubURL)
+	}
+	if project.LiveURL != "" {
+		lines = append(lines, "  Live: "+project.LiveURL)
+	}
+
+	return ContextSnippet{
+		Source:    ContextSourceProjects,
+		Section:   sectionProjectFocus,
+		Reference: fmt.Sprintf("projects#%d", project.ID),
+		Title:     project.Title,
+		Text:      strings.Join(lines, "\n"),
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseChatMode(t *testing.T) {
+	tests := []struct {
+		value     string
+		name      string
+		projectID int
+		valid     bool
+	}{
+		{"", ChatModeDefault, 0, true},
+		{"Recruiter", ChatModeRecruiter, 0, true},
+		{" technical ", ChatModeTechnical, 0, true},
+		{"casual", ChatModeCasual, 0, true},
+		{"project:12", ChatModeProject, 12, true},
+		{"project", "", 0, false},
+		{"project:abc", "", 0, false},
+		{"project:0", "", 0, false},
+		{"default", "", 0, false},
+		{"recruiter:1", "", 0, false},
+		{"pirate", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		mode, err := ParseChatMode(tt.value)
+		if !tt.valid {
+			var modeErr *ChatModeError
+			if !errors.As(err, &modeErr) {
+				t.Errorf("ParseChatMode(%q) error = %v, want ChatModeError", tt.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseChatMode(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if mode.Name != tt.name || mode.ProjectID != tt.projectID {
+			t.Errorf("ParseChatMode(%q) = %s, want %s (project %d)", tt.value, mode, tt.name, tt.projectID)
+		}
+	}
+}
+
+func TestChatModeShapesPrompt(t *testing.T) {
+	mode, _ := ParseChatMode(ChatModeRecruiter)
+
+	intents := mode.focusIntents(IntentScores{{Intent: IntentExperience}, {Intent: IntentProjects}})
+	if !intents.Selected(IntentExperience) || intents.Selected(IntentProjects) {
+		t.Errorf("Expected only the focus intents to be forced, got %s", intents)
+	}
+
+	snippets := mode.prioritize([]ContextSnippet{
+		{Source: ContextSourceAbout, Section: sectionAbout, Text: "SRE based in Berlin."},
+		{Source: ContextSourceNotes, Section: sectionKnowledge, Text: "- [notes/oncall.md] Runbooks"},
+		{Source: ContextSourceExperience, Section: sectionExperience, Text: "- SRE at Notifi"},
+	})
+	prompt := formatSnippetsForLLM(snippets, "Why hire him?", mode)
+
+	if !strings.HasPrefix(prompt, mode.Instruction) || !strings.Contains(prompt, mode.Reminder) {
+		t.Errorf("Expected the recruiter template, got:\n%s", prompt)
+	}
+	experience := strings.Index(prompt, sectionExperience)
+	about := strings.Index(prompt, sectionAbout)
+	knowledge := strings.Index(prompt, sectionKnowledge)
+	if !(experience < about && about < knowledge) {
+		t.Errorf("Expected experience, then about, then unlisted sources, got:\n%s", prompt)
+	}
+	if options := mode.ollamaOptions(); options == nil || options.NumPredict != mode.MaxTokens {
+		t.Errorf("Expected the answer to be capped at %d tokens, got %+v", mode.MaxTokens, options)
+	}
+	if DefaultChatMode().ollamaOptions() != nil {
+		t.Error("Expected no generation limit in the default mode")
+	}
+}
+
+func TestProjectFocusSnippet(t *testing.T) {
+	snippet := projectFocusSnippet(ProjectInfo{
+		ID:           3,
+		Title:        "Knative Lambda",
+		Type:         "Platform",
+		Description:  "Serverless functions on Kubernetes",
+		Technologies: []string{"Go", "Knative"},
+		GithubURL:    "https://github.com/brunovlucena/knative-lambda",
+	})
+
+	if snippet.Reference != "projects#3" || snippet.Section != sectionProjectFocus {
+		t.Errorf("Unexpected snippet: %+v", snippet)
+	}
+	for _, want := range []string{"Serverless functions on Kubernetes", "Tech: Go, Knative", "GitHub: https://github.com/brunovlucena/knative-lambda"} {
+		if !strings.Contains(snippet.Text, want) {
+			t.Errorf("Expected %q in project details, got:\n%s", want, snippet.Text)
+		}
+	}
+}
+
+func TestProcessChatRejectsUnknownMode(t *testing.T) {
+	llm := &LLMService{contextBuilder: &ContextBuilder{}, sessions: NewSessionStore(nil, nil), faqs: NewFAQMatcher(nil)}
+
+	_, err := llm.ProcessChat(ChatRequest{Message: "Hi", Context: "pirate", History: []ChatMessage{}})
+	var modeErr *ChatModeError
+	if !errors.As(err, &modeErr) || modeErr.Mode != "pirate" {
+		t.Errorf("Expected a ChatModeError for an unknown mode, got %v", err)
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"unicode/utf8"
 )
 
 // ContextBuilder builds context from PostgreSQL data for LLM prompts
 type ContextBuilder struct {
-	db *sql.DB
+	db              *sql.DB
+	intents         *IntentMatcher
+	classifier      *IntentClassifier
+	intentThreshold float64
+	sources         []ContextSource
 }
 
 // PersonalContext represents structured data about Bruno
@@ -27,21 +32,26 @@ type AboutInfo struct {
 }
 
 type SkillInfo struct {
-	Name        string `json:"name"`
-	Category    string `json:"category"`
-	Proficiency int    `json:"proficiency"`
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Category    string  `json:"category"`
+	Proficiency int     `json:"proficiency"`
+	Score       float64 `json:"score,omitempty"`
 }
 
 type ExpInfo struct {
+	ID           int      `json:"id"`
 	Title        string   `json:"title"`
 	Company      string   `json:"company"`
 	Period       string   `json:"period"`
 	Current      bool     `json:"current"`
 	Description  string   `json:"description"`
 	Technologies []string `json:"technologies"`
+	Score        float64  `json:"score,omitempty"`
 }
 
 type ProjectInfo struct {
+	ID           int      `json:"id"`
 	Title        string   `json:"title"`
 	Description  string   `json:"description"`
 	Type         string   `json:"type"`
@@ -49,6 +59,7 @@ type ProjectInfo struct {
 	GithubURL    string   `json:"github_url"`
 	LiveURL      string   `json:"live_url"`
 	Featured     bool     `json:"featured"`
+	Score        float64  `json:"score,omitempty"`
 }
 
 type ContactInfo struct {
@@ -61,101 +72,140 @@ type ContactInfo struct {
 
 // NewContextBuilder creates a new context builder
 func NewContextBuilder(db *sql.DB) *ContextBuilder {
-	return &ContextBuilder{db: db}
+	cb := &ContextBuilder{
+		db:              db,
+		intents:         NewIntentMatcher(db),
+		classifier:      DefaultIntentClassifier(),
+		intentThreshold: parseEnvFloat("INTENT_CONFIDENCE_THRESHOLD", 0.6),
+	}
+	cb.sources = newContextSources(cb, getEnv("CONTEXT_SOURCES", defaultContextSources))
+	return cb
 }
 
 // BuildContext creates context based on user query
 func (cb *ContextBuilder) BuildContext(query string) (string, error) {
-	log.Printf("🔍 Building context for query: %s", query)
-
-	// Analyze query to determine what data to include
-	context := &PersonalContext{}
-
-	// Always include basic about info
-	about, err := cb.getAboutInfo()
+	retrieved, err := cb.RetrieveContext(query)
 	if err != nil {
-		log.Printf("⚠️ Error getting about info: %v", err)
-	} else {
-		context.About = about
+		return "", err
 	}
+	return retrieved.Prompt, nil
+}
 
-	// Always include contact info for contact-related queries
-	if cb.isContactQuery(query) {
-		contact, err := cb.getContactInfo()
-		if err != nil {
-			log.Printf("⚠️ Error getting contact info: %v", err)
-		} else {
-			context.Contact = contact
-		}
-	}
+// RetrieveContext selects the intents of a query, collects the snippets of
+// every configured source and renders the prompt
+func (cb *ContextBuilder) RetrieveContext(query string) (*RetrievedContext, error) {
+	return cb.RetrieveContextForMode(query, DefaultChatMode())
+}
 
-	// Include skills if query mentions skills, technologies, or capabilities
-	if cb.isSkillsQuery(query) {
-		skills, err := cb.getRelevantSkills(query)
-		if err != nil {
-			log.Printf("⚠️ Error getting skills: %v", err)
-		} else {
-			context.Skills = skills
-		}
+// RetrieveContextForMode retrieves context shaped by a chat mode: its focus
+// intents are always selected, snippets follow its section priorities and a
+// project mode leads with the details of its project
+func (cb *ContextBuilder) RetrieveContextForMode(query string, mode ChatMode) (*RetrievedContext, error) {
+	// The query is visitor text that may hold PII, so only its length is logged
+	log.Printf("🔍 Building context for a %d-character query (mode %s)", utf8.RuneCountInString(query), mode)
+
+	// Analyze query to determine what data to include
+	retrieved := &RetrievedContext{
+		Mode:     mode.String(),
+		Intents:  mode.focusIntents(cb.ClassifyIntents(query)),
+		Snippets: []ContextSnippet{},
 	}
+	log.Printf("🧭 Intents: %s", retrieved.Intents)
 
-	// Include experience if query mentions work, experience, or companies
-	if cb.isExperienceQuery(query) {
-		experience, err := cb.getRelevantExperience(query)
+	if mode.ProjectID > 0 {
+		project, err := cb.findProject(mode.ProjectID)
 		if err != nil {
-			log.Printf("⚠️ Error getting experience: %v", err)
-		} else {
-			context.Experience = experience
+			return nil, err
+		}
+		if project == nil {
+			return nil, &ChatModeError{Mode: mode.String(), Reason: fmt.Sprintf("project %d not found", mode.ProjectID)}
 		}
+		retrieved.Snippets = append(retrieved.Snippets, projectFocusSnippet(*project))
 	}
 
-	// Include projects if query mentions projects, work, or specific technologies
-	if cb.isProjectsQuery(query) {
-		projects, err := cb.getRelevantProjects(query)
+	// Each source decides from the intents whether it is relevant; a failing
+	// source only leaves its section out
+	for _, source := range cb.sources {
+		// Other projects would dilute the context of a project mode
+		if mode.ProjectID > 0 && source.Name() == ContextSourceProjects {
+			continue
+		}
+		snippets, err := source.Snippets(query, retrieved.Intents)
 		if err != nil {
-			log.Printf("⚠️ Error getting projects: %v", err)
-		} else {
-			context.Projects = projects
+			log.Printf("⚠️ Error getting %s context: %v", source.Name(), err)
+			continue
 		}
+		retrieved.Snippets = append(retrieved.Snippets, snippets...)
 	}
+	retrieved.Snippets = mode.prioritize(retrieved.Snippets)
 
 	// Convert to formatted string for LLM
-	return cb.formatContextForLLM(context, query), nil
+	retrieved.Prompt = formatSnippetsForLLM(retrieved.Snippets, query, mode)
+	return retrieved, nil
 }
 
-// Query analysis methods
-func (cb *ContextBuilder) isContactQuery(query string) bool {
-	contactKeywords := []string{"contact", "email", "reach", "hire", "available", "linkedin", "github"}
-	return cb.containsKeywords(query, contactKeywords)
+// findProject returns the active project with the given ID, or nil when there is none
+func (cb *ContextBuilder) findProject(id int) (*ProjectInfo, error) {
+	projects, err := cb.ListProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %v", err)
+	}
+	for i := range projects {
+		if projects[i].ID == id {
+			return &projects[i], nil
+		}
+	}
+	return nil, nil
 }
 
-func (cb *ContextBuilder) isSkillsQuery(query string) bool {
-	skillKeywords := []string{"skill", "technology", "tech", "stack", "tools", "languages", "kubernetes", "aws", "go", "python", "devops", "sre"}
-	return cb.containsKeywords(query, skillKeywords)
-}
+// LoadPersonalContext loads the complete portfolio without filtering by query
+func (cb *ContextBuilder) LoadPersonalContext() (*PersonalContext, error) {
+	context := &PersonalContext{}
 
-func (cb *ContextBuilder) isExperienceQuery(query string) bool {
-	expKeywords := []string{"experience", "work", "job", "career", "company", "role", "position", "background", "mobimeo", "notifi", "crealytics"}
-	return cb.containsKeywords(query, expKeywords)
+	skills, err := cb.ListSkills()
+	if err != nil {
+		return nil, err
+	}
+	context.Skills = skills
+
+	experience, err := cb.ListExperience()
+	if err != nil {
+		return nil, err
+	}
+	context.Experience = experience
+
+	projects, err := cb.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	context.Projects = projects
+
+	// About and contact are optional content blocks
+	if about, err := cb.GetAbout(); err == nil {
+		context.About = about
+	}
+	if contact, err := cb.GetContact(); err == nil {
+		context.Contact = contact
+	}
+
+	return context, nil
 }
 
-func (cb *ContextBuilder) isProjectsQuery(query string) bool {
-	projectKeywords := []string{"project", "site", "github", "build", "created", "developed", "bruno site", "knative"}
-	return cb.containsKeywords(query, projectKeywords)
+// Intents returns the matcher that routes questions to portfolio sections
+func (cb *ContextBuilder) Intents() *IntentMatcher {
+	return cb.intents
 }
 
-func (cb *ContextBuilder) containsKeywords(query string, keywords []string) bool {
-	queryLower := strings.ToLower(query)
-	for _, keyword := range keywords {
-		if strings.Contains(queryLower, keyword) {
-			return true
-		}
-	}
-	return false
+// ClassifyIntents decides which portfolio sections a question needs, combining
+// the trained classifier with the keywords of the intent taxonomy
+func (cb *ContextBuilder) ClassifyIntents(query string) IntentScores {
+	return routeIntents(query, SectionIntents, cb.classifier, cb.intents, cb.intentThreshold)
 }
 
 // Data retrieval methods
-func (cb *ContextBuilder) getAboutInfo() (AboutInfo, error) {
+
+// GetAbout returns the about section
+func (cb *ContextBuilder) GetAbout() (AboutInfo, error) {
 	var about AboutInfo
 
 	// Check if database connection is available
@@ -182,7 +232,8 @@ func (cb *ContextBuilder) getAboutInfo() (AboutInfo, error) {
 	return about, nil
 }
 
-func (cb *ContextBuilder) getContactInfo() (ContactInfo, error) {
+// GetContact returns the public contact information
+func (cb *ContextBuilder) GetContact() (ContactInfo, error) {
 	var contact ContactInfo
 
 	// Check if database connection is available
@@ -221,7 +272,104 @@ func (cb *ContextBuilder) getContactInfo() (ContactInfo, error) {
 	return contact, nil
 }
 
+// getRelevantSkills returns the skills that best match the query
 func (cb *ContextBuilder) getRelevantSkills(query string) ([]SkillInfo, error) {
+	skills, err := cb.ListSkills()
+	if err != nil {
+		return skills, err
+	}
+	return rankSkills(query, skills, contextLimit("CONTEXT_MAX_SKILLS", 15)), nil
+}
+
+// getRelevantExperience returns the experience entries that best match the query
+func (cb *ContextBuilder) getRelevantExperience(query string) ([]ExpInfo, error) {
+	experiences, err := cb.ListExperience()
+	if err != nil {
+		return experiences, err
+	}
+	return rankExperience(query, experiences, contextLimit("CONTEXT_MAX_EXPERIENCE", 4)), nil
+}
+
+// getRelevantProjects returns the projects that best match the query
+func (cb *ContextBuilder) getRelevantProjects(query string) ([]ProjectInfo, error) {
+	projects, err := cb.ListProjects()
+	if err != nil {
+		return projects, err
+	}
+	return rankProjects(query, projects, contextLimit("CONTEXT_MAX_PROJECTS", 4)), nil
+}
+
+// rankSkills scores skills against the query and keeps the best ones
+func rankSkills(query string, skills []SkillInfo, limit int) []SkillInfo {
+	q := newRelevanceQuery(query, newSkillVocabulary(&PersonalContext{Skills: skills}))
+
+	scores := make([]float64, len(skills))
+	for i, skill := range skills {
+		scores[i] = scoreSkill(q, skill)
+	}
+
+	var ranked []SkillInfo
+	var labels []string
+	var selectedScores []float64
+	for _, i := range rankByScore(scores, limit) {
+		skill := skills[i]
+		skill.Score = scores[i]
+		ranked = append(ranked, skill)
+		labels = append(labels, skill.Name)
+		selectedScores = append(selectedScores, scores[i])
+	}
+	logRelevance("Skills", labels, selectedScores)
+	return ranked
+}
+
+// rankExperience scores experience entries against the query and keeps the best ones
+func rankExperience(query string, experiences []ExpInfo, limit int) []ExpInfo {
+	q := newRelevanceQuery(query, newSkillVocabulary(&PersonalContext{Experience: experiences}))
+
+	scores := make([]float64, len(experiences))
+	for i, exp := range experiences {
+		scores[i] = scoreExperience(q, exp)
+	}
+
+	var ranked []ExpInfo
+	var labels []string
+	var selectedScores []float64
+	for _, i := range rankByScore(scores, limit) {
+		exp := experiences[i]
+		exp.Score = scores[i]
+		ranked = append(ranked, exp)
+		labels = append(labels, exp.Title+" at "+exp.Company)
+		selectedScores = append(selectedScores, scores[i])
+	}
+	logRelevance("Experience", labels, selectedScores)
+	return ranked
+}
+
+// rankProjects scores projects against the query and keeps the best ones
+func rankProjects(query string, projects []ProjectInfo, limit int) []ProjectInfo {
+	q := newRelevanceQuery(query, newSkillVocabulary(&PersonalContext{Projects: projects}))
+
+	scores := make([]float64, len(projects))
+	for i, project := range projects {
+		scores[i] = scoreProject(q, project)
+	}
+
+	var ranked []ProjectInfo
+	var labels []string
+	var selectedScores []float64
+	for _, i := range rankByScore(scores, limit) {
+		project := projects[i]
+		project.Score = scores[i]
+		ranked = append(ranked, project)
+		labels = append(labels, project.Title)
+		selectedScores = append(selectedScores, scores[i])
+	}
+	logRelevance("Projects", labels, selectedScores)
+	return ranked
+}
+
+// ListSkills returns all active skills, ordered by proficiency and category
+func (cb *ContextBuilder) ListSkills() ([]SkillInfo, error) {
 	var skills []SkillInfo
 
 	// Check if database connection is available
@@ -229,13 +377,11 @@ func (cb *ContextBuilder) getRelevantSkills(query string) ([]SkillInfo, error) {
 		return skills, fmt.Errorf("database connection not available")
 	}
 
-	// Get all active skills, ordered by proficiency and category
 	rows, err := cb.db.Query(`
-		SELECT name, category, proficiency 
+		SELECT id, name, category, proficiency 
 		FROM skills 
 		WHERE active = true 
 		ORDER BY proficiency DESC, category, name
-		LIMIT 20
 	`)
 	if err != nil {
 		return skills, err
@@ -244,7 +390,7 @@ func (cb *ContextBuilder) getRelevantSkills(query string) ([]SkillInfo, error) {
 
 	for rows.Next() {
 		var skill SkillInfo
-		err := rows.Scan(&skill.Name, &skill.Category, &skill.Proficiency)
+		err := rows.Scan(&skill.ID, &skill.Name, &skill.Category, &skill.Proficiency)
 		if err != nil {
 			continue
 		}
@@ -254,7 +400,8 @@ func (cb *ContextBuilder) getRelevantSkills(query string) ([]SkillInfo, error) {
 	return skills, nil
 }
 
-func (cb *ContextBuilder) getRelevantExperience(query string) ([]ExpInfo, error) {
+// ListExperience returns all active experience entries, most recent first
+func (cb *ContextBuilder) ListExperience() ([]ExpInfo, error) {
 	var experiences []ExpInfo
 
 	// Check if database connection is available
@@ -263,7 +410,7 @@ func (cb *ContextBuilder) getRelevantExperience(query string) ([]ExpInfo, error)
 	}
 
 	rows, err := cb.db.Query(`
-		SELECT title, company, 
+		SELECT id, title, company, 
 			CASE 
 				WHEN current = true THEN start_date::text || ' - Present'
 				ELSE start_date::text || ' - ' || end_date::text
@@ -282,7 +429,7 @@ func (cb *ContextBuilder) getRelevantExperience(query string) ([]ExpInfo, error)
 		var exp ExpInfo
 		var techArray sql.NullString
 
-		err := rows.Scan(&exp.Title, &exp.Company, &exp.Period, &exp.Current, &exp.Description, &techArray)
+		err := rows.Scan(&exp.ID, &exp.Title, &exp.Company, &exp.Period, &exp.Current, &exp.Description, &techArray)
 		if err != nil {
 			continue
 		}
@@ -298,7 +445,8 @@ func (cb *ContextBuilder) getRelevantExperience(query string) ([]ExpInfo, error)
 	return experiences, nil
 }
 
-func (cb *ContextBuilder) getRelevantProjects(query string) ([]ProjectInfo, error) {
+// ListProjects returns all active projects, featured first
+func (cb *ContextBuilder) ListProjects() ([]ProjectInfo, error) {
 	var projects []ProjectInfo
 
 	// Check if database connection is available
@@ -307,7 +455,7 @@ func (cb *ContextBuilder) getRelevantProjects(query string) ([]ProjectInfo, erro
 	}
 
 	rows, err := cb.db.Query(`
-		SELECT title, description, type, github_url, live_url, technologies, featured
+		SELECT id, title, description, type, github_url, live_url, technologies, featured
 		FROM projects 
 		WHERE active = true 
 		ORDER BY featured DESC, "order"
@@ -322,7 +470,7 @@ func (cb *ContextBuilder) getRelevantProjects(query string) ([]ProjectInfo, erro
 		var techArray sql.NullString
 		var githubURL, liveURL sql.NullString
 
-		err := rows.Scan(&project.Title, &project.Description, &project.Type,
+		err := rows.Scan(&project.ID, &project.Title, &project.Description, &project.Type,
 			&githubURL, &liveURL, &techArray, &project.Featured)
 		if err != nil {
 			continue
@@ -361,84 +509,3 @@ func (cb *ContextBuilder) parsePostgreSQLArray(arrayStr string) []string {
 	}
 	return result
 }
-
-// Format context for LLM prompt
-func (cb *ContextBuilder) formatContextForLLM(context *PersonalContext, query string) string {
-	var builder strings.Builder
-
-	builder.WriteString("SYSTEM: Answer questions directly with facts. NO greetings, NO introductions. Maximum 2 sentences.\n\n")
-
-	// About section
-	if context.About.Description != "" {
-		builder.WriteString(fmt.Sprintf("ABOUT BRUNO:\n%s\n\n", context.About.Description))
-	}
-
-	// Contact information
-	if context.Contact.Email != "" {
-		builder.WriteString("CONTACT INFORMATION:\n")
-		builder.WriteString(fmt.Sprintf("- Email: %s\n", context.Contact.Email))
-		if context.Contact.Location != "" {
-			builder.WriteString(fmt.Sprintf("- Location: %s\n", context.Contact.Location))
-		}
-		if context.Contact.LinkedIn != "" {
-			builder.WriteString(fmt.Sprintf("- LinkedIn: %s\n", context.Contact.LinkedIn))
-		}
-		if context.Contact.GitHub != "" {
-			builder.WriteString(fmt.Sprintf("- GitHub: %s\n", context.Contact.GitHub))
-		}
-		if context.Contact.Availability != "" {
-			builder.WriteString(fmt.Sprintf("- Availability: %s\n", context.Contact.Availability))
-		}
-		builder.WriteString("\n")
-	}
-
-	// Skills section
-	if len(context.Skills) > 0 {
-		builder.WriteString("SKILLS & TECHNOLOGIES:\n")
-		skillsByCategory := make(map[string][]SkillInfo)
-		for _, skill := range context.Skills {
-			skillsByCategory[skill.Category] = append(skillsByCategory[skill.Category], skill)
-		}
-
-		for category, skills := range skillsByCategory {
-			builder.WriteString(fmt.Sprintf("- %s: ", category))
-			var skillNames []string
-			for _, skill := range skills {
-				skillNames = append(skillNames, fmt.Sprintf("%s (%d/5)", skill.Name, skill.Proficiency))
-			}
-			builder.WriteString(strings.Join(skillNames, ", "))
-			builder.WriteString("\n")
-		}
-		builder.WriteString("\n")
-	}
-
-	// Experience section
-	if len(context.Experience) > 0 {
-		builder.WriteString("PROFESSIONAL EXPERIENCE:\n")
-		for _, exp := range context.Experience {
-			builder.WriteString(fmt.Sprintf("- %s at %s (%s)\n", exp.Title, exp.Company, exp.Period))
-			if len(exp.Technologies) > 0 {
-				builder.WriteString(fmt.Sprintf("  Tech: %s\n", strings.Join(exp.Technologies, ", ")))
-			}
-		}
-		builder.WriteString("\n")
-	}
-
-	// Projects section
-	if len(context.Projects) > 0 {
-		builder.WriteString("KEY PROJECTS:\n")
-		for _, project := range context.Projects {
-			builder.WriteString(fmt.Sprintf("- %s (%s)\n", project.Title, project.Type))
-			if len(project.Technologies) > 0 {
-				builder.WriteString(fmt.Sprintf("  Tech: %s\n", strings.Join(project.Technologies, ", ")))
-			}
-		}
-		builder.WriteString("\n")
-	}
-
-	builder.WriteString("CRITICAL: Keep responses SHORT and DIRECT. Maximum 2-3 sentences only.\n\n")
-
-	builder.WriteString(fmt.Sprintf("USER QUESTION: %s\n", query))
-
-	return builder.String()
-}
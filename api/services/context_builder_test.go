@@ -60,139 +60,3 @@ func TestBuildContext(t *testing.T) {
 		t.Log("Context is empty (expected with nil database)")
 	}
 }
-
-// TestIsContactQuery tests the isContactQuery method
-func TestIsContactQuery(t *testing.T) {
-	var db *sql.DB = nil
-	builder := NewContextBuilder(db)
-
-	tests := []struct {
-		query    string
-		expected bool
-	}{
-		{"contact", true},
-		{"email", true},
-		{"reach", true},
-		{"hire", true},
-		{"available", true},
-		{"linkedin", true},
-		{"github", true},
-		{"phone", false},
-		{"get in touch", false},
-		{"hello", false},
-		{"skills", false},
-		{"", false},
-	}
-
-	for _, tt := range tests {
-		result := builder.isContactQuery(tt.query)
-		if result != tt.expected {
-			t.Errorf("isContactQuery(%q) = %v, want %v", tt.query, result, tt.expected)
-		}
-	}
-}
-
-// TestIsSkillsQuery tests the isSkillsQuery method
-func TestIsSkillsQuery(t *testing.T) {
-	var db *sql.DB = nil
-	builder := NewContextBuilder(db)
-
-	tests := []struct {
-		query    string
-		expected bool
-	}{
-		{"skill", true},
-		{"technology", true},
-		{"tech", true},
-		{"stack", true},
-		{"tools", true},
-		{"languages", true},
-		{"kubernetes", true},
-		{"aws", true},
-		{"go", true},
-		{"python", true},
-		{"devops", true},
-		{"sre", true},
-		{"programming", false},
-		{"capabilities", false},
-		{"contact", false},
-		{"hello", false},
-		{"", false},
-	}
-
-	for _, tt := range tests {
-		result := builder.isSkillsQuery(tt.query)
-		if result != tt.expected {
-			t.Errorf("isSkillsQuery(%q) = %v, want %v", tt.query, result, tt.expected)
-		}
-	}
-}
-
-// TestIsExperienceQuery tests the isExperienceQuery method
-func TestIsExperienceQuery(t *testing.T) {
-	var db *sql.DB = nil
-	builder := NewContextBuilder(db)
-
-	tests := []struct {
-		query    string
-		expected bool
-	}{
-		{"experience", true},
-		{"work", true},
-		{"job", true},
-		{"career", true},
-		{"company", true},
-		{"role", true},
-		{"position", true},
-		{"background", true},
-		{"mobimeo", true},
-		{"notifi", true},
-		{"crealytics", true},
-		{"employment", false},
-		{"contact", false},
-		{"skills", false},
-		{"", false},
-	}
-
-	for _, tt := range tests {
-		result := builder.isExperienceQuery(tt.query)
-		if result != tt.expected {
-			t.Errorf("isExperienceQuery(%q) = %v, want %v", tt.query, result, tt.expected)
-		}
-	}
-}
-
-// TestIsProjectsQuery tests the isProjectsQuery method
-func TestIsProjectsQuery(t *testing.T) {
-	var db *sql.DB = nil
-	builder := NewContextBuilder(db)
-
-	tests := []struct {
-		query    string
-		expected bool
-	}{
-		{"project", true},
-		{"projects", true},
-		{"site", true},
-		{"github", true},
-		{"build", true},
-		{"created", true},
-		{"developed", true},
-		{"bruno site", true},
-		{"knative", true},
-		{"portfolio", false},
-		{"work", false},
-		{"applications", false},
-		{"apps", false},
-		{"contact", false},
-		{"skills", false},
-		{"", false},
-	}
-
-	for _, tt := range tests {
-		result := builder.isProjectsQuery(tt.query)
-		if result != tt.expected {
-			t.Errorf("isProjectsQuery(%q) = %v, want %v", tt.query, result, tt.expected)
-		}
-	}
-}
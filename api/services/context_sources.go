@@ -0,0 +1,553 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Context source names accepted in CONTEXT_SOURCES
+const (
+	ContextSourceAbout      = "about"
+	ContextSourceContact    = "contact"
+	ContextSourceSkills     = "skills"
+	ContextSourceExperience = "experience"
+	ContextSourceProjects   = "projects"
+	ContextSourceNotes      = "notes"
+	ContextSourceFAQ        = "faq"
+	ContextSourceDocuments  = "documents"
+)
+
+// defaultContextSources keeps the portfolio tables as the only knowledge
+const defaultContextSources = "about,contact,skills,experience,projects"
+
+// Prompt section headings
+const (
+	sectionAbout      = "ABOUT BRUNO"
+	sectionContact    = "CONTACT INFORMATION"
+	sectionSkills     = "SKILLS & TECHNOLOGIES"
+	sectionExperience = "PROFESSIONAL EXPERIENCE"
+	sectionProjects   = "KEY PROJECTS"
+	sectionKnowledge  = "KNOWLEDGE BASE"
+)
+
+const (
+	knowledgeMinScore      = 2.0  // one title word or two body words
+	knowledgeChunkChars    = 1200 // longer sections are split by paragraph
+	notesRefreshInterval   = time.Minute
+	knowledgeSnippetsLimit = 3
+)
+
+// ContextSnippet is a piece of knowledge a source contributes to the prompt
+type ContextSnippet struct {
+	Source    string  `json:"source"`
+	Section   string  `json:"section"`
+	Reference string  `json:"reference"`
+	Title     string  `json:"title"`
+	Text      string  `json:"text"`
+	Score     float64 `json:"score"`
+}
+
+// ContextSource contributes snippets for a question. Sources decide from the
+// intents whether they are relevant and score their own snippets.
+type ContextSource interface {
+	Name() string
+	Snippets(query string, intents IntentScores) ([]ContextSnippet, error)
+}
+
+// RetrievedContext is everything selected to answer a question
+type RetrievedContext struct {
+	Mode     string           `json:"mode"`
+	Intents  IntentScores     `json:"intents"`
+	Snippets []ContextSnippet `json:"snippets"`
+	Prompt   string           `json:"prompt"`
+}
+
+// References returns the distinct references of the snippets, in prompt order
+func (rc *RetrievedContext) References() []string {
+	seen := make(map[string]bool)
+	var references []string
+	for _, snippet := range rc.Snippets {
+		if !seen[snippet.Reference] {
+			seen[snippet.Reference] = true
+			references = append(references, snippet.Reference)
+		}
+	}
+	return references
+}
+
+// newContextSources creates the sources listed in a comma-separated
+// configuration value, skipping unknown names
+func newContextSources(cb *ContextBuilder, config string) []ContextSource {
+	var sources []ContextSource
+	for _, name := range strings.Split(config, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "":
+			continue
+		case ContextSourceAbout:
+			sources = append(sources, &aboutSource{cb: cb})
+		case ContextSourceContact:
+			sources = append(sources, &contactSource{cb: cb})
+		case ContextSourceSkills:
+			sources = append(sources, &skillsSource{cb: cb})
+		case ContextSourceExperience:
+			sources = append(sources, &experienceSource{cb: cb})
+		case ContextSourceProjects:
+			sources = append(sources, &projectsSource{cb: cb})
+		case ContextSourceNotes:
+			sources = append(sources, newNotesSource(getEnv("CONTEXT_NOTES_DIR", "notes")))
+		case ContextSourceFAQ:
+			sources = append(sources, &faqSource{db: cb.db})
+		case ContextSourceDocuments:
+			sources = append(sources, &documentsSource{db: cb.db})
+		default:
+			log.Printf("⚠️ Unknown context source %q ignored", name)
+		}
+	}
+	return sources
+}
+
+// =============================================================================
+// 🗄️ PORTFOLIO SOURCES
+// =============================================================================
+
+// aboutSource always contributes the about text
+type aboutSource struct{ cb *ContextBuilder }
+
+func (s *aboutSource) Name() string { return ContextSourceAbout }
+
+func (s *aboutSource) Snippets(query string, intents IntentScores) ([]ContextSnippet, error) {
+	about, err := s.cb.GetAbout()
+	if err != nil || about.Description == "" {
+		return nil, err
+	}
+	return []ContextSnippet{{
+		Source:    ContextSourceAbout,
+		Section:   sectionAbout,
+		Reference: "content#about",
+		Title:     "About",
+		Text:      about.Description,
+	}}, nil
+}
+
+// contactSource contributes contact details for contact questions
+type contactSource struct{ cb *ContextBuilder }
+
+func (s *contactSource) Name() string { return ContextSourceContact }
+
+func (s *contactSource) Snippets(query string, intents IntentScores) ([]ContextSnippet, error) {
+	if !intents.Selected(IntentContact) {
+		return nil, nil
+	}
+
+	contact, err := s.cb.GetContact()
+	if err != nil || contact.Email == "" {
+		return nil, err
+	}
+
+	fields := []struct{ label, value string }{
+		{"Email", contact.Email},
+		{"Location", contact.Location},
+		{"LinkedIn", contact.LinkedIn},
+		{"GitHub", contact.GitHub},
+		{"Availability", contact.Availability},
+	}
+
+	var lines []string
+	for _, field := range fields {
+		if field.value != "" {
+			lines = append(lines, fmt.Sprintf("- %s: %s", field.label, field.value))
+		}
+	}
+	return []ContextSnippet{{
+		Source:    ContextSourceContact,
+		Section:   sectionContact,
+		Reference: "content#contact",
+		Title:     "Contact",
+		Text:      strings.Join(lines, "\n"),
+	}}, nil
+}
+
+// skillsSource contributes the most relevant skills, one snippet per category
+type skillsSource struct{ cb *ContextBuilder }
+
+func (s *skillsSource) Name() string { return ContextSourceSkills }
+
+func (s *skillsSource) Snippets(query string, intents IntentScores) ([]ContextSnippet, error) {
+	if !intents.Selected(IntentSkills) {
+		return nil, nil
+	}
+
+	skills, err := s.cb.getRelevantSkills(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []string
+	byCategory := make(map[string][]SkillInfo)
+	for _, skill := range skills {
+		if _, ok := byCategory[skill.Category]; !ok {
+			categories = append(categories, skill.Category)
+		}
+		byCategory[skill.Category] = append(byCategory[skill.Category], skill)
+	}
+
+	snippets := make([]ContextSnippet, 0, len(categories))
+	for _, category := range categories {
+		var names []string
+		score := 0.0
+		for _, skill := range byCategory[category] {
+			names = append(names, fmt.Sprintf("%s (%d/5)", skill.Name, skill.Proficiency))
+			score = max(score, skill.Score)
+		}
+		snippets = append(snippets, ContextSnippet{
+			Source:    ContextSourceSkills,
+			Section:   sectionSkills,
+			Reference: "skills#" + strings.ToLower(category),
+			Title:     category,
+			Text:      fmt.Sprintf("- %s: %s", category, strings.Join(names, ", ")),
+			Score:     score,
+		})
+	}
+	return snippets, nil
+}
+
+// experienceSource contributes the most relevant experience entries
+type experienceSource struct{ cb *ContextBuilder }
+
+func (s *experienceSource) Name() string { return ContextSourceExperience }
+
+func (s *experienceSource) Snippets(query string, intents IntentScores) ([]ContextSnippet, error) {
+	if !intents.Selected(IntentExperience) {
+		return nil, nil
+	}
+
+	experiences, err := s.cb.getRelevantExperience(query)
+	if err != nil {
+		return nil, err
+	}
+
+	snippets := make([]ContextSnippet, 0, len(experiences))
+	for _, exp := range experiences {
+		text := fmt.Sprintf("- %s at %s (%s)", exp.Title, exp.Company, exp.Period)
+		if len(exp.Technologies) > 0 {
+			text += fmt.Sprintf("\n  Tech: %s", strings.Join(exp.Technologies, ", "))
+		}
+		snippets = append(snippets, ContextSnippet{
+			Source:    ContextSourceExperience,
+			Section:   sectionExperience,
+			Reference: fmt.Sprintf("experience#%d", exp.ID),
+			Title:     exp.Title + " at " + exp.Company,
+			Text:      text,
+			Score:     exp.Score,
+		})
+	}
+	return snippets, nil
+}
+
+// projectsSource contributes the most relevant projects
+type projectsSource struct{ cb *ContextBuilder }
+
+func (s *projectsSource) Name() string { return ContextSourceProjects }
+
+func (s *projectsSource) Snippets(query string, intents IntentScores) ([]ContextSnippet, error) {
+	if !intents.Selected(IntentProjects) {
+		return nil, nil
+	}
+
+	projects, err := s.cb.getRelevantProjects(query)
+	if err != nil {
+		return nil, err
+	}
+
+	snippets := make([]ContextSnippet, 0, len(projects))
+	for _, project := range projects {
+		text := fmt.Sprintf("- %s (%s)", project.Title, project.Type)
+		if len(project.Technologies) > 0 {
+			text += fmt.Sprintf("\n  Tech: %s", strings.Join(project.Technologies, ", "))
+		}
+		snippets = append(snippets, ContextSnippet{
+			Source:    ContextSourceProjects,
+			Section:   sectionProjects,
+			Reference: fmt.Sprintf("projects#%d", project.ID),
+			Title:     project.Title,
+			Text:      text,
+			Score:     project.Score,
+		})
+	}
+	return snippets, nil
+}
+
+// =============================================================================
+// 📚 KNOWLEDGE SOURCES
+// =============================================================================
+
+// knowledgeChunk is a scorable piece of free text
+type knowledgeChunk struct {
+	Reference string
+	Title     string
+	Text      string
+}
+
+// notesSource contributes sections of the Markdown notes in a directory
+type notesSource struct {
+	dir string
+
+	mu       sync.Mutex
+	chunks   []knowledgeChunk
+	loadedAt time.Time
+}
+
+// newNotesSource creates a source over the Markdown and text files in dir
+func newNotesSource(dir string) *notesSource {
+	return &notesSource{dir: dir}
+}
+
+func (s *notesSource) Name() string { return ContextSourceNotes }
+
+func (s *notesSource) Snippets(query string, intents IntentScores) ([]ContextSnippet, error) {
+	chunks, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return rankKnowledgeChunks(query, ContextSourceNotes, chunks), nil
+}
+
+// load reads the notes directory, at most once per refresh interval
+func (s *notesSource) load() ([]knowledgeChunk, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < notesRefreshInterval {
+		return s.chunks, nil
+	}
+
+	var chunks []knowledgeChunk
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isKnowledgeFile(path) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel(s.dir, path)
+		chunks = append(chunks, splitKnowledgeText("notes/"+filepath.ToSlash(name), string(content))...)
+		return nil
+	})
+	s.loadedAt = time.Now()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes from %s: %v", s.dir, err)
+	}
+
+	s.chunks = chunks
+	return chunks, nil
+}
+
+// faqSource contributes the FAQ entries that match the question
+type faqSource struct{ db *sql.DB }
+
+func (s *faqSource) Name() string { return ContextSourceFAQ }
+
+func (s *faqSource) Snippets(query string, intents IntentScores) ([]ContextSnippet, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not available")
+	}
+
+	rows, err := s.db.Query(`SELECT id, question, answer FROM faq_entries WHERE active = true`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chunks []knowledgeChunk
+	for rows.Next() {
+		var id int
+		var question, answer string
+		if err := rows.Scan(&id, &question, &answer); err != nil {
+			continue
+		}
+		chunks = append(chunks, knowledgeChunk{
+			Reference: fmt.Sprintf("faq#%d", id),
+			Title:     question,
+			Text:      answer,
+		})
+	}
+	return rankKnowledgeChunks(query, ContextSourceFAQ, chunks), nil
+}
+
+// documentsSource contributes sections of the uploaded text documents
+type documentsSource struct{ db *sql.DB }
+
+func (s *documentsSource) Name() string { return ContextSourceDocuments }
+
+func (s *documentsSource) Snippets(query string, intents IntentScores) ([]ContextSnippet, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not available")
+	}
+
+	rows, err := s.db.Query(`SELECT id, title, content FROM context_documents WHERE active = true`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chunks []knowledgeChunk
+	for rows.Next() {
+		var id int
+		var title, content string
+		if err := rows.Scan(&id, &title, &content); err != nil {
+			continue
+		}
+		for _, chunk := range splitKnowledgeText(fmt.Sprintf("documents#%d", id), content) {
+			if chunk.Title == "" {
+				chunk.Title = title
+			} else {
+				chunk.Title = title + " › " + chunk.Title
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+	return rankKnowledgeChunks(query, ContextSourceDocuments, chunks), nil
+}
+
+// isKnowledgeFile reports whether a notes file is read as knowledge
+func isKnowledgeFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".txt":
+		return true
+	}
+	return false
+}
+
+// splitKnowledgeText splits Markdown or plain text into chunks at headings,
+// and long sections at paragraph boundaries
+func splitKnowledgeText(reference, content string) []knowledgeChunk {
+	var chunks []knowledgeChunk
+	title := ""
+	var paragraphs []string
+	var current strings.Builder
+
+	flushParagraph := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+		current.Reset()
+	}
+	flushSection := func() {
+		flushParagraph()
+		var text strings.Builder
+		for _, paragraph := range paragraphs {
+			if text.Len() > 0 && text.Len()+len(paragraph) > knowledgeChunkChars {
+				chunks = append(chunks, knowledgeChunk{Reference: reference, Title: title, Text: text.String()})
+				text.Reset()
+			}
+			if text.Len() > 0 {
+				text.WriteString("\n")
+			}
+			text.WriteString(paragraph)
+		}
+		if text.Len() > 0 {
+			chunks = append(chunks, knowledgeChunk{Reference: reference, Title: title, Text: text.String()})
+		}
+		paragraphs = nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			flushSection()
+			title = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		case trimmed == "":
+			flushParagraph()
+		default:
+			if current.Len() > 0 {
+				current.WriteString(" ")
+			}
+			current.WriteString(trimmed)
+		}
+	}
+	flushSection()
+
+	return chunks
+}
+
+// rankKnowledgeChunks scores free-text chunks against the question and keeps
+// the best matches as snippets
+func rankKnowledgeChunks(query, source string, chunks []knowledgeChunk) []ContextSnippet {
+	q := newRelevanceQuery(query, nil)
+
+	var snippets []ContextSnippet
+	for _, chunk := range chunks {
+		score := scoreKnowledge(q, chunk.Title, chunk.Text)
+		if score < knowledgeMinScore {
+			continue
+		}
+
+		label := chunk.Reference
+		if chunk.Title != "" {
+			label += " › " + chunk.Title
+		}
+		snippets = append(snippets, ContextSnippet{
+			Source:    source,
+			Section:   sectionKnowledge,
+			Reference: chunk.Reference,
+			Title:     chunk.Title,
+			Text:      fmt.Sprintf("- [%s] %s", label, chunk.Text),
+			Score:     score,
+		})
+	}
+
+	sort.SliceStable(snippets, func(i, j int) bool {
+		return snippets[i].Score > snippets[j].Score
+	})
+	limit := contextLimit("CONTEXT_MAX_SNIPPETS", knowledgeSnippetsLimit)
+	if len(snippets) > limit {
+		snippets = snippets[:limit]
+	}
+	return snippets
+}
+
+// formatSnippetsForLLM groups snippets into prompt sections between the
+// instruction and reminder of the chat mode
+func formatSnippetsForLLM(snippets []ContextSnippet, query string, mode ChatMode) string {
+	var builder strings.Builder
+
+	builder.WriteString(mode.Instruction + "\n\n")
+
+	var sections []string
+	bySection := make(map[string][]ContextSnippet)
+	for _, snippet := range snippets {
+		if _, ok := bySection[snippet.Section]; !ok {
+			sections = append(sections, snippet.Section)
+		}
+		bySection[snippet.Section] = append(bySection[snippet.Section], snippet)
+	}
+
+	for _, section := range sections {
+		builder.WriteString(section + ":\n")
+		for _, snippet := range bySection[section] {
+			builder.WriteString(snippet.Text + "\n")
+		}
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(mode.Reminder + "\n\n")
+
+	builder.WriteString(fmt.Sprintf("USER QUESTION: %s\n", query))
+
+	return builder.String()
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitKnowledgeText(t *testing.T) {
+	content := "Intro line\n\n# Homelab\nRuns on Talos.\nManaged with Flux.\n\n## Backups\nVelero every night.\n"
+	chunks := splitKnowledgeText("notes/homelab.md", content)
+
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d: %+v", len(chunks), chunks)
+	}
+	if chunks[0].Title != "" || chunks[0].Text != "Intro line" {
+		t.Errorf("Unexpected intro chunk: %+v", chunks[0])
+	}
+	if chunks[1].Title != "Homelab" || chunks[1].Text != "Runs on Talos. Managed with Flux." {
+		t.Errorf("Unexpected homelab chunk: %+v", chunks[1])
+	}
+	if chunks[2].Title != "Backups" || chunks[2].Reference != "notes/homelab.md" {
+		t.Errorf("Unexpected backups chunk: %+v", chunks[2])
+	}
+
+	long := strings.Repeat("word ", knowledgeChunkChars/5) + "\n\n" + strings.Repeat("more ", 50)
+	if chunks := splitKnowledgeText("doc", long); len(chunks) != 2 {
+		t.Errorf("Expected long sections to be split by paragraph, got %d chunks", len(chunks))
+	}
+}
+
+func TestNotesSource(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"homelab.md":       "# Homelab\nThe homelab runs Talos Linux with Flux and Pulumi.\n",
+		"talks/kubecon.md": "# KubeCon talk\nA talk about Knative autoscaling.\n",
+		"ignored.json":     `{"homelab": true}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source := newNotesSource(dir)
+	snippets, err := source.Snippets("What runs in the homelab?", nil)
+	if err != nil {
+		t.Fatalf("Snippets returned error: %v", err)
+	}
+	if len(snippets) != 1 || snippets[0].Reference != "notes/homelab.md" {
+		t.Fatalf("Expected the homelab note, got %+v", snippets)
+	}
+	if snippets[0].Section != sectionKnowledge || !strings.HasPrefix(snippets[0].Text, "- [notes/homelab.md › Homelab]") {
+		t.Errorf("Expected an attributed knowledge snippet, got %+v", snippets[0])
+	}
+
+	if snippets, _ := source.Snippets("Tell me about his Knative talk", nil); len(snippets) != 1 || snippets[0].Reference != "notes/talks/kubecon.md" {
+		t.Errorf("Expected the nested KubeCon note, got %+v", snippets)
+	}
+	if snippets, _ := source.Snippets("Hello there", nil); len(snippets) != 0 {
+		t.Errorf("Expected no snippets for small talk, got %+v", snippets)
+	}
+}
+
+func TestRetrieveContextWithConfiguredSources(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "oncall.md"), []byte("# On-call\nBruno ran the on-call rotation and wrote the incident runbooks.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONTEXT_SOURCES", "notes, skills, unknown")
+	t.Setenv("CONTEXT_NOTES_DIR", dir)
+
+	builder := NewContextBuilder(nil)
+	if len(builder.sources) != 2 {
+		t.Fatalf("Expected notes and skills sources, got %d", len(builder.sources))
+	}
+
+	retrieved, err := builder.RetrieveContext("Who wrote the incident runbooks?")
+	if err != nil {
+		t.Fatalf("RetrieveContext returned error: %v", err)
+	}
+	if references := retrieved.References(); len(references) != 1 || references[0] != "notes/oncall.md" {
+		t.Errorf("Expected the on-call note as the only reference, got %v", references)
+	}
+	if !strings.Contains(retrieved.Prompt, "KNOWLEDGE BASE:\n- [notes/oncall.md › On-call]") {
+		t.Errorf("Expected the knowledge section in the prompt, got:\n%s", retrieved.Prompt)
+	}
+	if !strings.HasSuffix(retrieved.Prompt, "USER QUESTION: Who wrote the incident runbooks?\n") {
+		t.Errorf("Expected the question at the end of the prompt, got:\n%s", retrieved.Prompt)
+	}
+}
+
+func TestFormatSnippetsForLLM(t *testing.T) {
+	prompt := formatSnippetsForLLM([]ContextSnippet{
+		{Section: sectionAbout, Text: "SRE based in Berlin."},
+		{Section: sectionExperience, Text: "- SRE at Notifi (2023 - Present)\n  Tech: Kubernetes"},
+		{Section: sectionExperience, Text: "- Infrastructure Engineer at Mobimeo (2020 - 2023)"},
+	}, "Where has he worked?", DefaultChatMode())
+
+	want := "ABOUT BRUNO:\nSRE based in Berlin.\n\nPROFESSIONAL EXPERIENCE:\n- SRE at Notifi (2023 - Present)\n  Tech: Kubernetes\n- Infrastructure Engineer at Mobimeo (2020 - 2023)\n\n"
+	if !strings.Contains(prompt, want) {
+		t.Errorf("Expected grouped sections, got:\n%s", prompt)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+)
+
+// EmbeddingClient generates text embeddings through Ollama
+type EmbeddingClient struct {
+	ollamaURL  string
+	model      string
+	usage      *UsageTracker
+	httpClient *http.Client
+}
+
+// ollamaEmbeddingRequest represents request format for the Ollama embed API
+type ollamaEmbeddingRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+// ollamaEmbeddingResponse represents response format from the Ollama embed API
+type ollamaEmbeddingResponse struct {
+	Embeddings      [][]float64 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	TotalDuration   int64       `json:"total_duration"`
+	LoadDuration    int64       `json:"load_duration"`
+}
+
+// NewEmbeddingClient creates a new embedding client that records its usage
+// with the tracker and stops once the daily budget is spent
+func NewEmbeddingClient(ollamaURL, model string, usage *UsageTracker) *EmbeddingClient {
+	return &EmbeddingClient{
+		ollamaURL: ollamaURL,
+		model:     model,
+		usage:     usage,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Model returns the embedding model name
+func (ec *EmbeddingClient) Model() string {
+	return ec.model
+}
+
+// Embed returns the embedding vector for text
+func (ec *EmbeddingClient) Embed(ctx context.Context, text string) (embedding []float64, err error) {
+	endpoint := ModelEndpoint{Role: ModelRoleEmbed, Model: ec.model, Backend: ec.ollamaURL}
+	defer func(startTime time.Time) { observeModelRequest(endpoint, startTime, err) }(time.Now())
+
+	if ec.usage.Status(ctx).Exceeded {
+		return nil, ErrBudgetExceeded
+	}
+
+	payload, err := json.Marshal(ollamaEmbeddingRequest{Model: ec.model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embedding request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ec.ollamaURL+"/api/embed", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ec.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("embedding request returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result ollamaEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode embedding response: %v", err)
+	}
+	if len(result.Embeddings) == 0 || len(result.Embeddings[0]) == 0 {
+		return nil, fmt.Errorf("embedding response was empty")
+	}
+
+	// Embedding requests have no completion; compute time excludes model loading
+	ec.usage.recordRequest(ctx, ModelUsage{
+		Role:               ModelRoleEmbed,
+		Model:              ec.model,
+		PromptTokens:       result.PromptEvalCount,
+		GenerationDuration: time.Duration(result.TotalDuration - result.LoadDuration),
+	})
+
+	return result.Embeddings[0], nil
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, or 0 when
+// they cannot be compared
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+)
+
+// Response sources of a chat answer
+const (
+	ResponseSourceLLM    = "llm"
+	ResponseSourceFAQ    = "faq"
+	ResponseSourceBudget = "budget" // daily budget spent, see UsageTracker
+)
+
+// FAQEntry is an owner-approved answer with the questions it answers
+type FAQEntry struct {
+	ID       int      `json:"id"`
+	Question string   `json:"question"`
+	Variants []string `json:"variants"`
+	Answer   string   `json:"answer"`
+}
+
+// FAQMatch is an FAQ entry matched by a chat message
+type FAQMatch struct {
+	Entry     FAQEntry `json:"entry"`
+	MatchedOn string   `json:"matched_on"`
+	Score     float64  `json:"score"`
+}
+
+// FAQMatcher answers chat messages from the FAQ table without calling the model
+type FAQMatcher struct {
+	db              *sql.DB
+	threshold       float64
+	refreshInterval time.Duration
+
+	mu       sync.RWMutex
+	entries  []faqCandidate
+	loadedAt time.Time
+
+	refreshMu sync.Mutex
+}
+
+// faqCandidate is a normalized question or variant of an entry
+type faqCandidate struct {
+	entry      *FAQEntry
+	text       string
+	normalized string
+	tokens     map[string]bool
+}
+
+// NewFAQMatcher creates a matcher that loads the FAQ table on first use
+func NewFAQMatcher(db *sql.DB) *FAQMatcher {
+	refreshMinutes, err := strconv.Atoi(getEnv("FAQ_REFRESH_MINUTES", "5"))
+	if err != nil || refreshMinutes <= 0 {
+		refreshMinutes = 5
+	}
+
+	return &FAQMatcher{
+		db:              db,
+		threshold:       parseEnvFloat("FAQ_MATCH_THRESHOLD", 0.85),
+		refreshInterval: time.Duration(refreshMinutes) * time.Minute,
+	}
+}
+
+// Match returns the best FAQ entry for a message, or nil when none is close enough
+func (m *FAQMatcher) Match(message string) *FAQMatch {
+	m.refreshIfStale()
+
+	normalized := normalizeFAQText(message)
+	if normalized == "" {
+		return nil
+	}
+	tokens := faqTokens(message)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var best *FAQMatch
+	for _, candidate := range m.entries {
+		score := faqSimilarity(normalized, tokens, candidate)
+		if score >= m.threshold && (best == nil || score > best.Score) {
+			best = &FAQMatch{Entry: *candidate.entry, MatchedOn: candidate.text, Score: score}
+		}
+	}
+	return best
+}
+
+// Refresh reloads the active FAQ entries
+func (m *FAQMatcher) Refresh() error {
+	if m.db == nil {
+		return fmt.Errorf("database connection not available")
+	}
+
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	rows, err := m.db.Query(`SELECT id, question, COALESCE(variants, '{}'), answer FROM faq_entries WHERE active = true ORDER BY id`)
+	if err != nil {
+		m.mu.Lock()
+		m.loadedAt = time.Now()
+		m.mu.Unlock()
+		return fmt.Errorf("failed to load FAQ entries: %v", err)
+	}
+	defer rows.Close()
+
+	var entries []FAQEntry
+	for rows.Next() {
+		var entry FAQEntry
+		var variants pq.StringArray
+		if err := rows.Scan(&entry.ID, &entry.Question, &variants, &entry.Answer); err != nil {
+			continue
+		}
+		entry.Variants = []string(variants)
+		entries = append(entries, entry)
+	}
+
+	candidates := buildFAQCandidates(entries)
+
+	m.mu.Lock()
+	m.entries = candidates
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+
+	log.Printf("❓ FAQ matcher refreshed: %d entries, %d questions", len(entries), len(candidates))
+	return nil
+}
+
+// refreshIfStale reloads the entries when they are older than the refresh interval
+func (m *FAQMatcher) refreshIfStale() {
+	if m.db == nil {
+		return
+	}
+
+	m.mu.RLock()
+	stale := time.Since(m.loadedAt) >= m.refreshInterval
+	m.mu.RUnlock()
+
+	if stale {
+		if err := m.Refresh(); err != nil {
+			log.Printf("⚠️ FAQ matcher refresh failed, keeping current entries: %v", err)
+		}
+	}
+}
+
+// buildFAQCandidates indexes the question and variants of every entry
+func buildFAQCandidates(entries []FAQEntry) []faqCandidate {
+	var candidates []faqCandidate
+	for i := range entries {
+		entry := &entries[i]
+		for _, text := range append([]string{entry.Question}, entry.Variants...) {
+			normalized := normalizeFAQText(text)
+			if normalized == "" {
+				continue
+			}
+			candidates = append(candidates, faqCandidate{
+				entry:      entry,
+				text:       text,
+				normalized: normalized,
+				tokens:     faqTokens(text),
+			})
+		}
+	}
+	return candidates
+}
+
+// normalizeFAQText lowercases text and reduces punctuation and spacing to single spaces
+func normalizeFAQText(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+	return strings.Join(fields, " ")
+}
+
+// faqTokens returns the meaningful words of a question
+func faqTokens(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, token := range relevanceTokens(text) {
+		if len(token) > 1 {
+			tokens[token] = true
+		}
+	}
+	return tokens
+}
+
+// faqSimilarity scores a message against an FAQ question between 0 and 1. It
+// takes the better of word overlap (robust to word order and filler words) and
+// edit distance (robust to typos).
+func faqSimilarity(normalized string, tokens map[string]bool, candidate faqCandidate) float64 {
+	if normalized == candidate.normalized {
+		return 1
+	}
+
+	overlap := 0.0
+	if len(tokens) > 0 && len(candidate.tokens) > 0 {
+		shared := 0
+		for token := range tokens {
+			if candidate.tokens[token] {
+				shared++
+			}
+		}
+		overlap = 2 * float64(shared) / float64(len(tokens)+len(candidate.tokens))
+	}
+
+	return max(overlap, editSimilarity(normalized, candidate.normalized))
+}
+
+// editSimilarity is 1 minus the Levenshtein distance relative to the longer string
+func editSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return 1 - float64(previous[len(rb)])/float64(longest)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func newTestFAQMatcher(entries ...FAQEntry) *FAQMatcher {
+	matcher := NewFAQMatcher(nil)
+	matcher.entries = buildFAQCandidates(entries)
+	return matcher
+}
+
+func TestNormalizeFAQText(t *testing.T) {
+	tests := map[string]string{
+		"Is Bruno open to REMOTE work?": "is bruno open to remote work",
+		"  C++ / C#  experience?! ":     "c++ c# experience",
+		"---":                           "",
+	}
+	for input, expected := range tests {
+		if got := normalizeFAQText(input); got != expected {
+			t.Errorf("normalizeFAQText(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestFAQMatcherMatch(t *testing.T) {
+	matcher := newTestFAQMatcher(
+		FAQEntry{ID: 1, Question: "Is Bruno open to remote work?", Variants: []string{"Does he work remotely?"}, Answer: "Yes, fully remote."},
+		FAQEntry{ID: 2, Question: "Where is Bruno based?", Answer: "Berlin."},
+	)
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"is bruno open to remote work", 1}, // punctuation and case are ignored
+		{"Is Bruno open to remot work?", 1}, // typos
+		{"Does he work remotely??", 1},      // variants
+		{"where is bruno based", 2},
+		{"What remote tools does Bruno use for monitoring?", 0}, // related but different question
+		{"Tell me about his Kubernetes projects", 0},
+	}
+
+	for _, tt := range tests {
+		match := matcher.Match(tt.query)
+		got := 0
+		if match != nil {
+			got = match.Entry.ID
+		}
+		if got != tt.expected {
+			t.Errorf("Match(%q) = entry %d, want %d", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestProcessChatAnswersFromFAQ(t *testing.T) {
+	llm := &LLMService{
+		model:          "test-model",
+		contextBuilder: &ContextBuilder{},
+		sessions:       NewSessionStore(nil, nil),
+		faqs:           newTestFAQMatcher(FAQEntry{ID: 7, Question: "Where is Bruno based?", Answer: "Berlin."}),
+	}
+
+	var streamed string
+	response, err := llm.ProcessChatStream(context.Background(), ChatRequest{
+		Message: "Where is Bruno based?",
+		History: []ChatMessage{},
+	}, func(token string) error {
+		streamed += token
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected FAQ answer without calling the model, got error: %v", err)
+	}
+	if response.Source != ResponseSourceFAQ || response.Response != "Berlin." || streamed != "Berlin." {
+		t.Errorf("Unexpected FAQ response: %+v (streamed %q)", response, streamed)
+	}
+	if len(response.Sources) != 1 || response.Sources[0] != "faq#7" {
+		t.Errorf("Expected faq#7 source, got %v", response.Sources)
+	}
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Completer generates free text from a prompt; implemented by LLMService
+type Completer interface {
+	Complete(ctx context.Context, systemPrompt, prompt string) (string, error)
+}
+
+// FitAnalyzer compares job descriptions against the portfolio
+type FitAnalyzer struct {
+	contextBuilder *ContextBuilder
+	completer      Completer
+}
+
+// FitReport describes how well the portfolio matches a job description
+type FitReport struct {
+	Score         int          `json:"score"`
+	Fit           string       `json:"fit"`
+	Matched       []SkillMatch `json:"matched"`
+	Missing       []SkillMatch `json:"missing"`
+	Summary       string       `json:"summary"`
+	SummarySource string       `json:"summary_source"`
+	Timestamp     string       `json:"timestamp"`
+}
+
+// SkillMatch is a skill requested by the job description with the portfolio
+// records that demonstrate it
+type SkillMatch struct {
+	Skill    string          `json:"skill"`
+	Required bool            `json:"required"`
+	Evidence []SkillEvidence `json:"evidence,omitempty"`
+}
+
+// SkillEvidence points at the portfolio record backing a matched skill
+type SkillEvidence struct {
+	Type      string `json:"type"` // skill, experience or project
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Detail    string `json:"detail,omitempty"`
+	MatchedOn string `json:"matched_on"` // name, technologies or description
+}
+
+// Summary sources
+const (
+	SummarySourceLLM           = "llm"
+	SummarySourceDeterministic = "deterministic"
+)
+
+// NewFitAnalyzer creates a new fit analyzer; completer may be nil to skip LLM summaries
+func NewFitAnalyzer(db *sql.DB, completer Completer) *FitAnalyzer {
+	return &FitAnalyzer{
+		contextBuilder: NewContextBuilder(db),
+		completer:      completer,
+	}
+}
+
+// Analyze builds a fit report for a job description. Skill matching is
+// deterministic; the LLM only writes the summary and falls back to a
+// generated one when unavailable.
+func (fa *FitAnalyzer) Analyze(ctx context.Context, jobDescription string, withLLMSummary bool) (*FitReport, error) {
+	portfolio, err := fa.contextBuilder.LoadPersonalContext()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio: %v", err)
+	}
+
+	report := AnalyzeFit(jobDescription, portfolio)
+
+	if withLLMSummary && fa.completer != nil {
+		summary, err := fa.completer.Complete(ctx, fitSummarySystemPrompt, buildFitSummaryPrompt(report, jobDescription))
+		if err != nil {
+			log.Printf("⚠️ LLM fit summary failed, using deterministic summary: %v", err)
+		} else if summary = strings.TrimSpace(summary); summary != "" {
+			report.Summary = summary
+			report.SummarySource = SummarySourceLLM
+		}
+	}
+
+	log.Printf("🎯 Job fit analyzed: score=%d fit=%s matched=%d missing=%d summary=%s",
+		report.Score, report.Fit, len(report.Matched), len(report.Missing), report.SummarySource)
+
+	return report, nil
+}
+
+// AnalyzeFit matches the skills extracted from a job description against the
+// portfolio without calling the LLM
+func AnalyzeFit(jobDescription string, portfolio *PersonalContext) *FitReport {
+	vocabulary := newSkillVocabulary(portfolio)
+
+	report := &FitReport{
+		Matched:       []SkillMatch{},
+		Missing:       []SkillMatch{},
+		SummarySource: SummarySourceDeterministic,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+	}
+
+	var totalWeight, matchedWeight int
+	for _, skill := range vocabulary.extract(jobDescription) {
+		weight := 1
+		if skill.Required {
+			weight = 2
+		}
+		totalWeight += weight
+
+		match := SkillMatch{
+			Skill:    skill.Name,
+			Required: skill.Required,
+			Evidence: findSkillEvidence(vocabulary.byCanonical[canonicalSkill(skill.Name)], portfolio),
+		}
+		if len(match.Evidence) > 0 {
+			matchedWeight += weight
+			report.Matched = append(report.Matched, match)
+		} else {
+			report.Missing = append(report.Missing, match)
+		}
+	}
+
+	if totalWeight > 0 {
+		report.Score = matchedWeight * 100 / totalWeight
+	}
+	report.Fit = fitLevel(report.Score, totalWeight)
+	report.Summary = deterministicFitSummary(report)
+
+	return report
+}
+
+// findSkillEvidence lists the skills, experience and projects that demonstrate a skill
+func findSkillEvidence(term *skillTerm, portfolio *PersonalContext) []SkillEvidence {
+	if term == nil || portfolio == nil {
+		return nil
+	}
+
+	var evidence []SkillEvidence
+	for _, skill := range portfolio.Skills {
+		if canonicalSkill(skill.Name) == term.canonical {
+			evidence = append(evidence, SkillEvidence{
+				Type:      "skill",
+				ID:        skill.ID,
+				Title:     skill.Name,
+				Detail:    fmt.Sprintf("%s, proficiency %d/5", skill.Category, skill.Proficiency),
+				MatchedOn: "name",
+			})
+		}
+	}
+
+	for _, exp := range portfolio.Experience {
+		matchedOn := ""
+		if containsCanonicalSkill(exp.Technologies, term.canonical) {
+			matchedOn = "technologies"
+		} else if term.mentions(exp.Description) {
+			matchedOn = "description"
+		}
+		if matchedOn != "" {
+			evidence = append(evidence, SkillEvidence{
+				Type:      "experience",
+				ID:        exp.ID,
+				Title:     fmt.Sprintf("%s at %s", exp.Title, exp.Company),
+				Detail:    exp.Period,
+				MatchedOn: matchedOn,
+			})
+		}
+	}
+
+	for _, project := range portfolio.Projects {
+		matchedOn := ""
+		if containsCanonicalSkill(project.Technologies, term.canonical) {
+			matchedOn = "technologies"
+		} else if term.mentions(project.Description) {
+			matchedOn = "description"
+		}
+		if matchedOn != "" {
+			evidence = append(evidence, SkillEvidence{
+				Type:      "project",
+				ID:        project.ID,
+				Title:     project.Title,
+				Detail:    project.Type,
+				MatchedOn: matchedOn,
+			})
+		}
+	}
+
+	return evidence
+}
+
+func containsCanonicalSkill(technologies []string, canonical string) bool {
+	for _, tech := range technologies {
+		if canonicalSkill(tech) == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// fitLevel turns a score into a coarse label
+func fitLevel(score, totalWeight int) string {
+	switch {
+	case totalWeight == 0:
+		return "unknown"
+	case score >= 75:
+		return "strong"
+	case score >= 50:
+		return "good"
+	case score >= 25:
+		return "partial"
+	default:
+		return "weak"
+	}
+}
+
+// deterministicFitSummary describes a report without the LLM
+func deterministicFitSummary(report *FitReport) string {
+	if report.Fit == "unknown" {
+		return "No recognizable skills or technologies were found in the job description."
+	}
+
+	var matchedRequired, totalRequired, matchedOptional, totalOptional int
+	var matchedNames, missingNames []string
+	for _, match := range report.Matched {
+		matchedNames = append(matchedNames, match.Skill)
+		if match.Required {
+			matchedRequired++
+			totalRequired++
+		} else {
+			matchedOptional++
+			totalOptional++
+		}
+	}
+	for _, match := range report.Missing {
+		missingNames = append(missingNames, match.Skill)
+		if match.Required {
+			totalRequired++
+		} else {
+			totalOptional++
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%s fit (%d/100): ", strings.ToUpper(report.Fit[:1])+report.Fit[1:], report.Score))
+	builder.WriteString(fmt.Sprintf("matches %d of %d required skills", matchedRequired, totalRequired))
+	if totalOptional > 0 {
+		builder.WriteString(fmt.Sprintf(" and %d of %d nice-to-have skills", matchedOptional, totalOptional))
+	}
+	builder.WriteString(".")
+	if len(matchedNames) > 0 {
+		builder.WriteString(fmt.Sprintf(" Demonstrated: %s.", strings.Join(matchedNames, ", ")))
+	}
+	if len(missingNames) > 0 {
+		builder.WriteString(fmt.Sprintf(" Not found in the portfolio: %s.", strings.Join(missingNames, ", ")))
+	}
+	return builder.String()
+}
+
+const fitSummarySystemPrompt = "You are a recruiting assistant. Summarize how well Bruno's background fits a job description in 3-4 sentences. Use ONLY the facts provided. NO greetings, NO bullet points."
+
+// maxFitSummaryJobLength caps how much of the job description is sent to the LLM
+const maxFitSummaryJobLength = 2000
+
+// buildFitSummaryPrompt lists the matching results for the LLM to summarize
+func buildFitSummaryPrompt(report *FitReport, jobDescription string) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("FIT SCORE: %d/100 (%s)\n\n", report.Score, report.Fit))
+
+	builder.WriteString("MATCHED SKILLS:\n")
+	for _, match := range report.Matched {
+		var titles []string
+		for _, evidence := range match.Evidence {
+			titles = append(titles, evidence.Title)
+		}
+		builder.WriteString(fmt.Sprintf("- %s (required: %v): %s\n", match.Skill, match.Required, strings.Join(titles, "; ")))
+	}
+
+	builder.WriteString("\nMISSING SKILLS:\n")
+	for _, match := range report.Missing {
+		builder.WriteString(fmt.Sprintf("- %s (required: %v)\n", match.Skill, match.Required))
+	}
+
+	builder.WriteString(fmt.Sprintf("\nJOB DESCRIPTION:\n%s\n", truncateString(jobDescription, maxFitSummaryJobLength)))
+
+	return builder.String()
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func testPortfolio() *PersonalContext {
+	return &PersonalContext{
+		Skills: []SkillInfo{
+			{ID: 1, Name: "Kubernetes", Category: "Cloud", Proficiency: 5},
+			{ID: 2, Name: "Go", Category: "Programming", Proficiency: 5},
+			{ID: 3, Name: "AWS", Category: "Cloud", Proficiency: 5},
+			{ID: 4, Name: "AWS Lambda", Category: "Cloud", Proficiency: 4},
+			{ID: 5, Name: "Terraform", Category: "Infrastructure", Proficiency: 5},
+		},
+		Experience: []ExpInfo{
+			{
+				ID:           10,
+				Title:        "Senior Infrastructure Engineer",
+				Company:      "Mobimeo",
+				Period:       "2020-02-01 - 2023-03-31",
+				Description:  "Built observability with Prometheus and Grafana.",
+				Technologies: []string{"AWS", "EKS", "Kubernetes", "Terraform"},
+			},
+			{
+				ID:           11,
+				Title:        "Cloud Consultant",
+				Company:      "Namecheap, Inc",
+				Technologies: []string{"Golang", "Ansible"},
+			},
+		},
+		Projects: []ProjectInfo{
+			{ID: 20, Title: "Bruno Site", Type: "Web", Technologies: []string{"Go", "PostgreSQL", "Redis"}},
+		},
+	}
+}
+
+func TestExtractSkills(t *testing.T) {
+	vocabulary := newSkillVocabulary(testPortfolio())
+
+	tests := []struct {
+		name string
+		text string
+		want []ExtractedSkill
+	}{
+		{
+			name: "aliases resolve to portfolio names",
+			text: "Experience with golang and k8s on Postgres",
+			want: []ExtractedSkill{{"Go", true}, {"Kubernetes", true}, {"PostgreSQL", true}},
+		},
+		{
+			name: "common words do not match",
+			text: "A good engineer who can go the extra mile and rest",
+			want: []ExtractedSkill{},
+		},
+		{
+			name: "longest term wins",
+			text: "Serverless on AWS Lambda",
+			want: []ExtractedSkill{{"AWS Lambda", true}},
+		},
+		{
+			name: "nice to have section",
+			text: "Requirements:\n- Go and Terraform\n\nNice to have:\n- Kafka\n- Scala",
+			want: []ExtractedSkill{{"Go", true}, {"Terraform", true}, {"Kafka", false}, {"Scala", false}},
+		},
+		{
+			name: "inline optional marker",
+			text: "- Kubernetes\n- Java is a plus",
+			want: []ExtractedSkill{{"Kubernetes", true}, {"Java", false}},
+		},
+		{
+			name: "required mention overrides optional",
+			text: "Nice to have: Kubernetes\nRequirements:\n- Kubernetes",
+			want: []ExtractedSkill{{"Kubernetes", true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := vocabulary.extract(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("extract(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("extract(%q)[%d] = %v, want %v", tt.text, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeFit(t *testing.T) {
+	jobDescription := "Requirements:\n- Kubernetes and Golang\n- Java\n\nNice to have:\n- Prometheus"
+
+	report := AnalyzeFit(jobDescription, testPortfolio())
+
+	if len(report.Matched) != 3 || len(report.Missing) != 1 {
+		t.Fatalf("Expected 3 matched and 1 missing skill, got %+v / %+v", report.Matched, report.Missing)
+	}
+	if report.Missing[0].Skill != "Java" || !report.Missing[0].Required {
+		t.Errorf("Expected Java to be a missing required skill, got %+v", report.Missing[0])
+	}
+
+	// Required skills weigh 2, optional 1: (2+2+1) / (2+2+2+1)
+	if report.Score != 71 {
+		t.Errorf("Expected score 71, got %d", report.Score)
+	}
+	if report.Fit != "good" {
+		t.Errorf("Expected fit 'good', got %s", report.Fit)
+	}
+	if report.SummarySource != SummarySourceDeterministic || !strings.Contains(report.Summary, "Java") {
+		t.Errorf("Expected deterministic summary mentioning Java, got %q", report.Summary)
+	}
+
+	goMatch := report.Matched[1]
+	if goMatch.Skill != "Go" {
+		t.Fatalf("Expected second match to be Go, got %s", goMatch.Skill)
+	}
+	var types []string
+	for _, evidence := range goMatch.Evidence {
+		types = append(types, evidence.Type)
+	}
+	if strings.Join(types, ",") != "skill,experience,project" {
+		t.Errorf("Expected Go evidence from skill, experience and project, got %v", types)
+	}
+
+	prometheus := report.Matched[2]
+	if prometheus.Required || len(prometheus.Evidence) != 1 || prometheus.Evidence[0].MatchedOn != "description" {
+		t.Errorf("Expected optional Prometheus matched on experience description, got %+v", prometheus)
+	}
+}
+
+func TestAnalyzeFitWithoutSkills(t *testing.T) {
+	report := AnalyzeFit("We are a friendly team looking for a great colleague.", testPortfolio())
+
+	if report.Fit != "unknown" || report.Score != 0 {
+		t.Errorf("Expected unknown fit with score 0, got %s / %d", report.Fit, report.Score)
+	}
+	if report.Matched == nil || report.Missing == nil {
+		t.Error("Expected empty, non-nil skill lists")
+	}
+}
+
+type stubCompleter struct {
+	response string
+	err      error
+}
+
+func (s stubCompleter) Complete(ctx context.Context, systemPrompt, prompt string) (string, error) {
+	return s.response, s.err
+}
+
+func TestFitAnalyzerWithoutDatabase(t *testing.T) {
+	analyzer := NewFitAnalyzer(nil, stubCompleter{response: "summary"})
+
+	if _, err := analyzer.Analyze(context.Background(), "Kubernetes", true); err == nil {
+		t.Error("Expected error when database is not available")
+	}
+}
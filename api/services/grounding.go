@@ -0,0 +1,279 @@
+package services
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Claim types checked against the context
+const (
+	ClaimCompany    = "company"
+	ClaimTechnology = "technology"
+	ClaimProject    = "project"
+	ClaimDate       = "date"
+	ClaimURL        = "url"
+)
+
+// What to do with answers below the minimum grounding score
+const (
+	GroundingActionOff        = "off"
+	GroundingActionFlag       = "flag"
+	GroundingActionRegenerate = "regenerate"
+)
+
+var (
+	groundingURLPattern  = regexp.MustCompile(`https?://[^\s<>()\[\]"']+`)
+	groundingYearPattern = regexp.MustCompile(`\b(?:19|20)\d{2}\b`)
+	// groundingCompanyPattern finds capitalized names introduced like employers
+	// ("at Acme", "joined Acme Labs", "for Acme")
+	groundingCompanyPattern = regexp.MustCompile(`\b(?:at|joined|for)\s+([A-Z][\w&.-]*(?:\s+[A-Z][\w&.-]*){0,3})`)
+)
+
+var (
+	groundingScore = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "chat_grounding_score",
+		Help:    "Share of the checked claims of a chat answer found in its context",
+		Buckets: []float64{0.1, 0.25, 0.5, 0.6, 0.7, 0.8, 0.9, 0.95, 1},
+	})
+	groundingUngroundedClaims = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "chat_ungrounded_claims_total",
+		Help: "Claims of chat answers not found in their context, by claim type",
+	}, []string{"type"})
+	groundingRegenerations = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "chat_grounding_regenerations_total",
+		Help: "Chat answers regenerated because of ungrounded claims",
+	})
+)
+
+// GroundingClaim is an entity mentioned in an answer
+type GroundingClaim struct {
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Grounded bool   `json:"grounded"`
+}
+
+// GroundingReport is the result of checking an answer against its context
+type GroundingReport struct {
+	Score      float64          `json:"score"`
+	Claims     []GroundingClaim `json:"claims"`
+	Ungrounded []string         `json:"ungrounded,omitempty"`
+	Flagged    bool             `json:"flagged"`
+}
+
+// GroundingChecker verifies that the companies, technologies, projects, dates
+// and URLs of an answer appear in the snippets it was generated from
+type GroundingChecker struct {
+	cb              *ContextBuilder
+	action          string
+	minScore        float64
+	refreshInterval time.Duration
+
+	mu         sync.RWMutex
+	vocabulary *skillVocabulary
+	companies  []string
+	projects   []string
+	loadedAt   time.Time
+}
+
+// NewGroundingChecker creates a checker that knows the names in the portfolio
+func NewGroundingChecker(cb *ContextBuilder) *GroundingChecker {
+	action := strings.ToLower(getEnv("GROUNDING_ACTION", GroundingActionFlag))
+	switch action {
+	case GroundingActionOff, GroundingActionFlag, GroundingActionRegenerate:
+	default:
+		log.Printf("⚠️ Unknown GROUNDING_ACTION %q, flagging ungrounded answers", action)
+		action = GroundingActionFlag
+	}
+
+	return &GroundingChecker{
+		cb:              cb,
+		action:          action,
+		minScore:        parseEnvFloat("GROUNDING_MIN_SCORE", 0.8),
+		refreshInterval: 10 * time.Minute,
+		vocabulary:      newSkillVocabulary(nil),
+	}
+}
+
+// Action returns what happens to answers below the minimum score
+func (g *GroundingChecker) Action() string {
+	return g.action
+}
+
+// MinScore returns the score below which answers are flagged
+func (g *GroundingChecker) MinScore() float64 {
+	return g.minScore
+}
+
+// Check extracts the claims of an answer and looks each up in the snippets
+// and the question. Answers without claims score 1.
+func (g *GroundingChecker) Check(answer, question string, snippets []ContextSnippet) *GroundingReport {
+	g.refreshIfStale()
+
+	texts := []string{question}
+	for _, snippet := range snippets {
+		texts = append(texts, snippet.Title, snippet.Text)
+	}
+	grounding := strings.Join(texts, "\n")
+	groundingLower := strings.ToLower(grounding)
+
+	g.mu.RLock()
+	vocabulary, companies, projects := g.vocabulary, g.companies, g.projects
+	g.mu.RUnlock()
+
+	report := &GroundingReport{Claims: []GroundingClaim{}}
+	seen := make(map[string]bool)
+	add := func(claimType, value string, grounded bool) {
+		key := claimType + ":" + strings.ToLower(value)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		report.Claims = append(report.Claims, GroundingClaim{Type: claimType, Value: value, Grounded: grounded})
+	}
+
+	// Technologies compare by canonical name, so "k8s" is grounded by "Kubernetes"
+	groundedSkills := make(map[string]bool)
+	for _, skill := range vocabulary.extract(grounding) {
+		groundedSkills[canonicalSkill(skill.Name)] = true
+	}
+	technologies := make(map[string]bool)
+	for _, skill := range vocabulary.extract(answer) {
+		technologies[strings.ToLower(skill.Name)] = true
+		add(ClaimTechnology, skill.Name, groundedSkills[canonicalSkill(skill.Name)])
+	}
+
+	// Known employers are found by name; unknown ones by how they are introduced
+	mentionedCompanies := make(map[string]bool)
+	for _, company := range companies {
+		if name := normalizeCompanyName(company); name != "" && skillPattern(name, false).MatchString(answer) {
+			mentionedCompanies[name] = true
+			add(ClaimCompany, company, skillPattern(name, false).MatchString(grounding))
+		}
+	}
+	for _, match := range groundingCompanyPattern.FindAllStringSubmatch(answer, -1) {
+		name := strings.TrimRight(match[1], ".-")
+		normalized := normalizeCompanyName(name)
+		if normalized == "" || mentionedCompanies[normalized] || technologies[strings.ToLower(name)] ||
+			len(vocabulary.extract(name)) > 0 || containsFold(projects, name) {
+			continue
+		}
+		add(ClaimCompany, name, skillPattern(normalized, false).MatchString(grounding))
+	}
+
+	for _, title := range projects {
+		if pattern := skillPattern(strings.ToLower(title), false); pattern.MatchString(answer) {
+			add(ClaimProject, title, pattern.MatchString(grounding))
+		}
+	}
+
+	for _, year := range groundingYearPattern.FindAllString(answer, -1) {
+		add(ClaimDate, year, strings.Contains(grounding, year))
+	}
+
+	for _, url := range groundingURLPattern.FindAllString(answer, -1) {
+		url = strings.TrimRight(url, ".,;:!?")
+		add(ClaimURL, url, strings.Contains(groundingLower, strings.ToLower(strings.TrimRight(url, "/"))))
+	}
+
+	grounded := 0
+	for _, claim := range report.Claims {
+		if claim.Grounded {
+			grounded++
+		} else {
+			report.Ungrounded = append(report.Ungrounded, claim.Value)
+		}
+	}
+	report.Score = 1
+	if len(report.Claims) > 0 {
+		report.Score = float64(grounded) / float64(len(report.Claims))
+	}
+	report.Flagged = report.Score < g.minScore
+	return report
+}
+
+// Record exports the report of a final answer as metrics
+func (g *GroundingChecker) Record(report *GroundingReport) {
+	groundingScore.Observe(report.Score)
+	for _, claim := range report.Claims {
+		if !claim.Grounded {
+			groundingUngroundedClaims.WithLabelValues(claim.Type).Inc()
+		}
+	}
+}
+
+// regenerationPrompt asks the model to answer again without the ungrounded claims
+func regenerationPrompt(report *GroundingReport) string {
+	return "Your previous answer mentioned facts that are not in the context: " +
+		strings.Join(report.Ungrounded, ", ") +
+		". Answer the question again using ONLY the facts in the context."
+}
+
+// refreshIfStale reloads the portfolio names when they are older than the refresh interval
+func (g *GroundingChecker) refreshIfStale() {
+	if g.cb == nil || g.cb.db == nil {
+		return
+	}
+
+	g.mu.RLock()
+	stale := time.Since(g.loadedAt) >= g.refreshInterval
+	g.mu.RUnlock()
+	if !stale {
+		return
+	}
+
+	portfolio, err := g.cb.LoadPersonalContext()
+	if err != nil {
+		log.Printf("⚠️ Grounding checker refresh failed, keeping current names: %v", err)
+		g.mu.Lock()
+		g.loadedAt = time.Now()
+		g.mu.Unlock()
+		return
+	}
+	g.setPortfolio(portfolio)
+}
+
+// setPortfolio replaces the names the checker recognizes
+func (g *GroundingChecker) setPortfolio(portfolio *PersonalContext) {
+	companies := make(map[string]bool)
+	for _, exp := range portfolio.Experience {
+		companies[exp.Company] = true
+	}
+	var projects []string
+	for _, project := range portfolio.Projects {
+		projects = append(projects, project.Title)
+	}
+
+	g.mu.Lock()
+	g.vocabulary = newSkillVocabulary(portfolio)
+	g.companies = sortedKeys(companies)
+	g.projects = projects
+	g.loadedAt = time.Now()
+	g.mu.Unlock()
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+)
+
+func newTestGroundingChecker() *GroundingChecker {
+	checker := NewGroundingChecker(nil)
+	checker.setPortfolio(&PersonalContext{
+		Skills:     []SkillInfo{{Name: "Kubernetes"}, {Name: "Go"}, {Name: "Terraform"}},
+		Experience: []ExpInfo{{Company: "Notifi"}, {Company: "Mobimeo GmbH", Technologies: []string{"AWS"}}},
+		Projects:   []ProjectInfo{{Title: "Knative Lambda"}},
+	})
+	return checker
+}
+
+func TestGroundingCheckerGroundedAnswer(t *testing.T) {
+	checker := newTestGroundingChecker()
+	snippets := []ContextSnippet{
+		{Section: sectionExperience, Text: "- SRE at Notifi (2023 - Present)\n  Tech: Kubernetes, Go"},
+		{Section: sectionProjects, Title: "Knative Lambda", Text: "- Knative Lambda (Platform)\n  GitHub: https://github.com/brunovlucena/knative-lambda"},
+	}
+
+	report := checker.Check("Bruno has been an SRE at Notifi since 2023, running k8s and Go. See https://github.com/brunovlucena/knative-lambda/ for Knative Lambda.",
+		"Where does he work?", snippets)
+
+	if report.Score != 1 || report.Flagged {
+		t.Errorf("Expected a fully grounded answer, got %+v", report)
+	}
+	types := make(map[string]bool)
+	for _, claim := range report.Claims {
+		types[claim.Type] = true
+	}
+	for _, claimType := range []string{ClaimCompany, ClaimTechnology, ClaimProject, ClaimDate, ClaimURL} {
+		if !types[claimType] {
+			t.Errorf("Expected a %s claim, got %+v", claimType, report.Claims)
+		}
+	}
+}
+
+func TestGroundingCheckerFlagsInventedClaims(t *testing.T) {
+	checker := newTestGroundingChecker()
+	snippets := []ContextSnippet{{Section: sectionExperience, Text: "- SRE at Notifi (2023 - Present)\n  Tech: Kubernetes"}}
+
+	report := checker.Check("He worked at Google Cloud Labs in 2015 and at Mobimeo, writing Rust.", "Where has he worked?", snippets)
+
+	if !report.Flagged {
+		t.Errorf("Expected the answer to be flagged, got %+v", report)
+	}
+	ungrounded := make(map[string]bool)
+	for _, value := range report.Ungrounded {
+		ungrounded[value] = true
+	}
+	for _, want := range []string{"Google Cloud Labs", "2015", "Mobimeo GmbH", "Rust"} {
+		if !ungrounded[want] {
+			t.Errorf("Expected %q to be ungrounded, got %v", want, report.Ungrounded)
+		}
+	}
+}
+
+func TestGroundingCheckerNoClaims(t *testing.T) {
+	report := newTestGroundingChecker().Check("He is based in Berlin.", "Where is he based?", nil)
+	if report.Score != 1 || len(report.Claims) != 0 || report.Flagged {
+		t.Errorf("Expected an answer without claims to score 1, got %+v", report)
+	}
+}
+
+func TestGroundingCheckerQuestionGroundsClaims(t *testing.T) {
+	report := newTestGroundingChecker().Check("There is no Rust experience listed.", "Does he know Rust?", nil)
+	if report.Flagged {
+		t.Errorf("Expected technologies named in the question to be grounded, got %+v", report)
+	}
+}
@@ -0,0 +1,396 @@
+package services
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// IntentNone labels training queries that need no portfolio section
+const IntentNone = "none"
+
+// intentTrainingData is the labelled query file the default classifier is trained on
+//
+//go:embed data/intent_queries.tsv
+var intentTrainingData string
+
+// LabelledQuery is a training example for the intent classifier
+type LabelledQuery struct {
+	Query   string   `json:"query"`
+	Intents []string `json:"intents"`
+}
+
+// IntentClassifier is a one-vs-rest naive Bayes classifier over word unigrams
+// and bigrams. Each intent is an independent yes/no decision, so a question can
+// belong to several intents or to none.
+type IntentClassifier struct {
+	Intents    []string                     `json:"intents"`
+	Vocabulary int                          `json:"vocabulary"`
+	Examples   int                          `json:"examples"`
+	Models     map[string]*intentBayesModel `json:"models"`
+}
+
+// intentBayesModel holds the feature counts of one intent and its complement
+type intentBayesModel struct {
+	Positive intentClassCounts `json:"positive"`
+	Negative intentClassCounts `json:"negative"`
+}
+
+// intentClassCounts holds the document and feature counts of one class
+type intentClassCounts struct {
+	Documents int            `json:"documents"`
+	Features  int            `json:"features"`
+	Counts    map[string]int `json:"counts"`
+}
+
+// IntentEvaluation summarizes how well a predictor labels a set of queries
+type IntentEvaluation struct {
+	Examples   int                            `json:"examples"`
+	ExactMatch float64                        `json:"exact_match"`
+	PerIntent  map[string]IntentEvaluationRow `json:"per_intent"`
+}
+
+// IntentEvaluationRow holds precision and recall of a single intent
+type IntentEvaluationRow struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+}
+
+var (
+	defaultClassifierOnce sync.Once
+	defaultClassifier     *IntentClassifier
+)
+
+// DefaultIntentClassifier returns the classifier used for chat routing. It is
+// loaded from INTENT_MODEL_PATH when set, otherwise trained on the embedded
+// labelled queries. It returns nil when neither is usable.
+func DefaultIntentClassifier() *IntentClassifier {
+	defaultClassifierOnce.Do(func() {
+		if path := getEnv("INTENT_MODEL_PATH", ""); path != "" {
+			classifier, err := LoadIntentClassifier(path)
+			if err == nil {
+				log.Printf("🧭 Intent classifier loaded from %s (%d examples)", path, classifier.Examples)
+				defaultClassifier = classifier
+				return
+			}
+			log.Printf("⚠️ Failed to load intent model from %s, training on built-in data: %v", path, err)
+		}
+
+		examples, err := ParseLabelledQueries(strings.NewReader(intentTrainingData))
+		if err != nil {
+			log.Printf("⚠️ Failed to parse built-in intent training data: %v", err)
+			return
+		}
+		defaultClassifier = TrainIntentClassifier(examples)
+	})
+	return defaultClassifier
+}
+
+// ParseLabelledQueries reads "<intents><TAB><query>" lines. Intents are
+// comma-separated; "none" marks queries without intents. Blank lines and lines
+// starting with # are ignored.
+func ParseLabelledQueries(r io.Reader) ([]LabelledQuery, error) {
+	var examples []LabelledQuery
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		labels, query, ok := strings.Cut(line, "\t")
+		query = strings.TrimSpace(query)
+		if !ok || query == "" {
+			return nil, fmt.Errorf("line %d: expected <intents><TAB><query>", lineNumber)
+		}
+
+		example := LabelledQuery{Query: query, Intents: []string{}}
+		for _, label := range strings.Split(labels, ",") {
+			label = strings.ToLower(strings.TrimSpace(label))
+			if label == "" {
+				return nil, fmt.Errorf("line %d: empty intent", lineNumber)
+			}
+			if label != IntentNone {
+				example.Intents = append(example.Intents, label)
+			}
+		}
+		examples = append(examples, example)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("no labelled queries found")
+	}
+	return examples, nil
+}
+
+// TrainIntentClassifier trains a classifier with one model per intent found in the examples
+func TrainIntentClassifier(examples []LabelledQuery) *IntentClassifier {
+	intentSet := make(map[string]bool)
+	for _, example := range examples {
+		for _, intent := range example.Intents {
+			intentSet[intent] = true
+		}
+	}
+
+	classifier := &IntentClassifier{
+		Models:   make(map[string]*intentBayesModel, len(intentSet)),
+		Examples: len(examples),
+	}
+	for intent := range intentSet {
+		classifier.Intents = append(classifier.Intents, intent)
+		classifier.Models[intent] = &intentBayesModel{
+			Positive: intentClassCounts{Counts: make(map[string]int)},
+			Negative: intentClassCounts{Counts: make(map[string]int)},
+		}
+	}
+	sort.Strings(classifier.Intents)
+
+	vocabulary := make(map[string]bool)
+	for _, example := range examples {
+		features := intentFeatures(example.Query)
+		for _, feature := range features {
+			vocabulary[feature] = true
+		}
+
+		labels := make(map[string]bool, len(example.Intents))
+		for _, intent := range example.Intents {
+			labels[intent] = true
+		}
+
+		for intent, model := range classifier.Models {
+			class := &model.Negative
+			if labels[intent] {
+				class = &model.Positive
+			}
+			class.Documents++
+			class.Features += len(features)
+			for _, feature := range features {
+				class.Counts[feature]++
+			}
+		}
+	}
+	classifier.Vocabulary = len(vocabulary)
+
+	return classifier
+}
+
+// Predict returns the probability of every intent for a query
+func (ic *IntentClassifier) Predict(query string) map[string]float64 {
+	features := intentFeatures(query)
+	probabilities := make(map[string]float64, len(ic.Intents))
+	for _, intent := range ic.Intents {
+		probabilities[intent] = ic.Models[intent].probability(features, ic.Vocabulary)
+	}
+	return probabilities
+}
+
+// Classify returns the intents whose probability reaches the threshold
+func (ic *IntentClassifier) Classify(query string, threshold float64) []string {
+	intents := []string{}
+	for intent, probability := range ic.Predict(query) {
+		if probability >= threshold {
+			intents = append(intents, intent)
+		}
+	}
+	sort.Strings(intents)
+	return intents
+}
+
+// Save writes the trained model as JSON
+func (ic *IntentClassifier) Save(path string) error {
+	data, err := json.MarshalIndent(ic, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadIntentClassifier reads a model written by Save
+func LoadIntentClassifier(path string) (*IntentClassifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var classifier IntentClassifier
+	if err := json.Unmarshal(data, &classifier); err != nil {
+		return nil, fmt.Errorf("invalid intent model: %v", err)
+	}
+	if len(classifier.Intents) == 0 || classifier.Vocabulary == 0 {
+		return nil, fmt.Errorf("intent model has no intents")
+	}
+	for _, intent := range classifier.Intents {
+		if classifier.Models[intent] == nil {
+			return nil, fmt.Errorf("intent model is missing %q", intent)
+		}
+	}
+	return &classifier, nil
+}
+
+// probability returns P(intent | features) with Laplace smoothing. Features
+// never seen in training carry no evidence and are skipped.
+func (m *intentBayesModel) probability(features []string, vocabulary int) float64 {
+	total := m.Positive.Documents + m.Negative.Documents
+	if total == 0 || m.Positive.Documents == 0 {
+		return 0
+	}
+
+	logPositive := math.Log(float64(m.Positive.Documents+1) / float64(total+2))
+	logNegative := math.Log(float64(m.Negative.Documents+1) / float64(total+2))
+	for _, feature := range features {
+		positive, negative := m.Positive.Counts[feature], m.Negative.Counts[feature]
+		if positive == 0 && negative == 0 {
+			continue
+		}
+		logPositive += math.Log(float64(positive+1) / float64(m.Positive.Features+vocabulary))
+		logNegative += math.Log(float64(negative+1) / float64(m.Negative.Features+vocabulary))
+	}
+
+	return 1 / (1 + math.Exp(logNegative-logPositive))
+}
+
+// intentFeatures returns the distinct lowercase unigrams and bigrams of a query
+func intentFeatures(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' && r != '\''
+	})
+	for i, word := range words {
+		words[i] = stemIntentWord(strings.Trim(word, "'"))
+	}
+
+	seen := make(map[string]bool)
+	var features []string
+	add := func(feature string) {
+		if feature != "" && !seen[feature] {
+			seen[feature] = true
+			features = append(features, feature)
+		}
+	}
+
+	for i, word := range words {
+		add(word)
+		if i > 0 {
+			add(words[i-1] + " " + word)
+		}
+	}
+	return features
+}
+
+// EvaluateIntentPredictor compares the intents returned by predict with the labels
+func EvaluateIntentPredictor(examples []LabelledQuery, intents []string, predict func(string) []string) IntentEvaluation {
+	evaluation := IntentEvaluation{
+		Examples:  len(examples),
+		PerIntent: make(map[string]IntentEvaluationRow, len(intents)),
+	}
+
+	truePositives := make(map[string]int)
+	falsePositives := make(map[string]int)
+	falseNegatives := make(map[string]int)
+	exact := 0
+
+	for _, example := range examples {
+		expected := make(map[string]bool)
+		for _, intent := range example.Intents {
+			expected[intent] = true
+		}
+		predicted := make(map[string]bool)
+		for _, intent := range predict(example.Query) {
+			predicted[intent] = true
+		}
+
+		match := true
+		for _, intent := range intents {
+			switch {
+			case expected[intent] && predicted[intent]:
+				truePositives[intent]++
+			case predicted[intent]:
+				falsePositives[intent]++
+				match = false
+			case expected[intent]:
+				falseNegatives[intent]++
+				match = false
+			}
+		}
+		if match {
+			exact++
+		}
+	}
+
+	if len(examples) > 0 {
+		evaluation.ExactMatch = float64(exact) / float64(len(examples))
+	}
+	for _, intent := range intents {
+		row := IntentEvaluationRow{Support: truePositives[intent] + falseNegatives[intent]}
+		if predicted := truePositives[intent] + falsePositives[intent]; predicted > 0 {
+			row.Precision = float64(truePositives[intent]) / float64(predicted)
+		}
+		if row.Support > 0 {
+			row.Recall = float64(truePositives[intent]) / float64(row.Support)
+		}
+		if row.Precision+row.Recall > 0 {
+			row.F1 = 2 * row.Precision * row.Recall / (row.Precision + row.Recall)
+		}
+		evaluation.PerIntent[intent] = row
+	}
+	return evaluation
+}
+
+// CrossValidateIntentClassifier trains on all folds but one and evaluates on
+// the remaining fold, for every fold. Examples are assigned to folds in order
+// so results are reproducible. When a matcher is given, its keyword matches
+// are combined with the classifier as BuildContext does.
+func CrossValidateIntentClassifier(examples []LabelledQuery, folds int, threshold float64, matcher *IntentMatcher) IntentEvaluation {
+	if folds < 2 {
+		folds = 2
+	}
+
+	intents := TrainIntentClassifier(examples).Intents
+	predictions := make(map[string][]string, len(examples))
+	for fold := 0; fold < folds; fold++ {
+		var train, test []LabelledQuery
+		for i, example := range examples {
+			if i%folds == fold {
+				test = append(test, example)
+			} else {
+				train = append(train, example)
+			}
+		}
+
+		classifier := TrainIntentClassifier(train)
+		for _, example := range test {
+			predictions[example.Query] = routeIntents(example.Query, intents, classifier, matcher, threshold).SelectedIntents()
+		}
+	}
+
+	return EvaluateIntentPredictor(examples, intents, func(query string) []string {
+		return predictions[query]
+	})
+}
+
+// stemIntentWord strips common English suffixes so "worked", "working" and
+// "works" share a feature
+func stemIntentWord(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+3 && strings.HasSuffix(word, suffix) && !strings.HasSuffix(word, "ss") {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLabelledQueries(t *testing.T) {
+	examples, err := ParseLabelledQueries(strings.NewReader("# comment\n\nskills,experience\tUsed Go at work?\nnone\tHello\n"))
+	if err != nil {
+		t.Fatalf("ParseLabelledQueries returned error: %v", err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("Expected 2 examples, got %d", len(examples))
+	}
+	if len(examples[0].Intents) != 2 || examples[0].Intents[1] != IntentExperience {
+		t.Errorf("Expected skills and experience, got %v", examples[0].Intents)
+	}
+	if len(examples[1].Intents) != 0 {
+		t.Errorf("Expected no intents for none, got %v", examples[1].Intents)
+	}
+
+	for _, invalid := range []string{"skills What is missing a tab", "skills\t", ",skills\tEmpty label", "# only comments"} {
+		if _, err := ParseLabelledQueries(strings.NewReader(invalid)); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestBuiltInTrainingData(t *testing.T) {
+	examples, err := ParseLabelledQueries(strings.NewReader(intentTrainingData))
+	if err != nil {
+		t.Fatalf("Built-in training data is invalid: %v", err)
+	}
+
+	classifier := DefaultIntentClassifier()
+	if classifier == nil {
+		t.Fatal("Expected the default classifier to be trained")
+	}
+	for _, intent := range SectionIntents {
+		if classifier.Models[intent] == nil {
+			t.Errorf("Training data has no examples for %s", intent)
+		}
+	}
+
+	// Combined with keywords, the classifier must beat keyword matching alone
+	matcher := NewIntentMatcher(nil)
+	keywords := EvaluateIntentPredictor(examples, SectionIntents, func(query string) []string {
+		return routeIntents(query, SectionIntents, nil, matcher, 1.1).SelectedIntents()
+	})
+	combined := CrossValidateIntentClassifier(examples, 5, 0.6, matcher)
+	if combined.ExactMatch <= keywords.ExactMatch {
+		t.Errorf("Expected cross-validated accuracy above the keyword baseline, got %.2f <= %.2f",
+			combined.ExactMatch, keywords.ExactMatch)
+	}
+}
+
+func TestClassifyIntents(t *testing.T) {
+	builder := NewContextBuilder(nil)
+
+	tests := []struct {
+		query    string
+		selected []string
+	}{
+		{"That's good to know", []string{}},
+		{"What did he do two years ago?", []string{IntentExperience}},
+		{"Does he know Go?", []string{IntentSkills}},
+		{"Where can I email him?", []string{IntentContact}},
+		{"What has he built with Knative?", []string{IntentProjects}},
+	}
+
+	for _, tt := range tests {
+		scores := builder.ClassifyIntents(tt.query)
+		selected := scores.SelectedIntents()
+		if strings.Join(selected, ",") != strings.Join(tt.selected, ",") {
+			t.Errorf("ClassifyIntents(%q) selected %v, want %v (%s)", tt.query, selected, tt.selected, scores)
+		}
+	}
+}
+
+func TestIntentClassifierSaveLoad(t *testing.T) {
+	classifier := TrainIntentClassifier([]LabelledQuery{
+		{Query: "What are his skills?", Intents: []string{IntentSkills}},
+		{Query: "Where did he work?", Intents: []string{IntentExperience}},
+		{Query: "Hello", Intents: []string{}},
+	})
+
+	path := filepath.Join(t.TempDir(), "intent_model.json")
+	if err := classifier.Save(path); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded, err := LoadIntentClassifier(path)
+	if err != nil {
+		t.Fatalf("LoadIntentClassifier returned error: %v", err)
+	}
+
+	query := "Which skills does he have?"
+	if got, want := loaded.Predict(query)[IntentSkills], classifier.Predict(query)[IntentSkills]; got != want {
+		t.Errorf("Loaded model predicts %.4f, want %.4f", got, want)
+	}
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Intent names used to decide which portfolio sections go into the prompt
+const (
+	IntentContact    = "contact"
+	IntentSkills     = "skills"
+	IntentExperience = "experience"
+	IntentProjects   = "projects"
+)
+
+// IntentHiring marks recruiting conversations. It selects no portfolio section;
+// it makes the chat offer to collect the visitor's contact details.
+const IntentHiring = "hiring"
+
+// SectionIntents are the intents that select portfolio sections for the prompt
+var SectionIntents = []string{IntentContact, IntentSkills, IntentExperience, IntentProjects}
+
+// defaultIntentKeywords is used until the intent tables are loaded, and when
+// they are unavailable. It mirrors the seed data of 003_chat_intents.sql.
+var defaultIntentKeywords = map[string][]string{
+	IntentContact:    {"contact", "email", "reach", "hire", "available", "linkedin", "github"},
+	IntentSkills:     {"skill", "technology", "tech", "stack", "tools", "languages", "kubernetes", "aws", "go", "python", "devops", "sre"},
+	IntentExperience: {"experience", "work", "job", "career", "company", "role", "position", "background"},
+	IntentProjects:   {"project", "site", "github", "build", "created", "developed", "bruno site", "knative"},
+	IntentHiring:     {"hiring", "recruiting", "recruiter", "recruitment", "vacancy", "job offer", "job opening", "open role", "interview", "headhunter", "join our team"},
+}
+
+// companySuffixes are dropped from company names before they become intent terms
+var companySuffixes = []string{", inc", " inc", " inc.", " gmbh", " ltd", " llc", " ag", " se"}
+
+// IntentMatcher routes questions to intents using keywords and synonyms from
+// the database, augmented with names taken from the portfolio data
+type IntentMatcher struct {
+	db              *sql.DB
+	refreshInterval time.Duration
+
+	mu       sync.RWMutex
+	terms    map[string][]intentTerm
+	loadedAt time.Time
+
+	refreshMu  sync.Mutex
+	refreshing atomic.Bool
+}
+
+// intentTerm is a keyword, synonym or data-derived name of an intent. Terms
+// match whole words, so "go" does not match "good"; keywords also match their
+// plural ("project" matches "projects").
+type intentTerm struct {
+	text    string
+	derived bool
+	pattern *regexp.Regexp
+}
+
+// intentData holds the portfolio names that augment the intents
+type intentData struct {
+	Companies     []string
+	Technologies  []string
+	ProjectTitles []string
+}
+
+// IntentTerms lists the terms of an intent, split by origin
+type IntentTerms struct {
+	Keywords []string `json:"keywords"`
+	Derived  []string `json:"derived"`
+}
+
+// IntentScore is the routing decision for one intent
+type IntentScore struct {
+	Intent     string   `json:"intent"`
+	Confidence float64  `json:"confidence"`
+	Matched    []string `json:"matched,omitempty"`
+	Selected   bool     `json:"selected"`
+}
+
+// IntentScores holds the routing decisions for a question
+type IntentScores []IntentScore
+
+// Selected reports whether an intent was selected
+func (scores IntentScores) Selected(intent string) bool {
+	for _, score := range scores {
+		if score.Intent == intent {
+			return score.Selected
+		}
+	}
+	return false
+}
+
+// SelectedIntents returns the names of the selected intents
+func (scores IntentScores) SelectedIntents() []string {
+	intents := []string{}
+	for _, score := range scores {
+		if score.Selected {
+			intents = append(intents, score.Intent)
+		}
+	}
+	return intents
+}
+
+// String formats the scores for logging, e.g. "skills=0.92* contact=0.03"
+func (scores IntentScores) String() string {
+	parts := make([]string, len(scores))
+	for i, score := range scores {
+		parts[i] = fmt.Sprintf("%s=%.2f", score.Intent, score.Confidence)
+		if score.Selected {
+			parts[i] += "*"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// routeIntents scores a question against each intent. An intent is selected
+// when the classifier is confident enough or when one of its terms appears in
+// the question, since the classifier cannot know names added after training.
+func routeIntents(query string, intents []string, classifier *IntentClassifier, matcher *IntentMatcher, threshold float64) IntentScores {
+	var probabilities map[string]float64
+	if classifier != nil {
+		probabilities = classifier.Predict(query)
+	}
+
+	scores := make(IntentScores, 0, len(intents))
+	for _, intent := range intents {
+		score := IntentScore{Intent: intent, Confidence: probabilities[intent]}
+		if matcher != nil {
+			score.Matched = matcher.MatchedTerms(intent, query)
+		}
+		score.Selected = score.Confidence >= threshold || len(score.Matched) > 0
+		scores = append(scores, score)
+	}
+	return scores
+}
+
+// NewIntentMatcher creates an intent matcher that starts with the default
+// keywords and loads the database taxonomy in the background on first use
+func NewIntentMatcher(db *sql.DB) *IntentMatcher {
+	refreshMinutes, err := strconv.Atoi(getEnv("INTENT_REFRESH_MINUTES", "10"))
+	if err != nil || refreshMinutes <= 0 {
+		refreshMinutes = 10
+	}
+
+	return &IntentMatcher{
+		db:              db,
+		refreshInterval: time.Duration(refreshMinutes) * time.Minute,
+		terms:           buildIntentTerms(defaultIntentKeywords, intentData{}),
+	}
+}
+
+// Matches reports whether a query belongs to an intent
+func (m *IntentMatcher) Matches(intent, query string) bool {
+	return len(m.MatchedTerms(intent, query)) > 0
+}
+
+// MatchedTerms returns the terms of an intent found in a query
+func (m *IntentMatcher) MatchedTerms(intent, query string) []string {
+	m.refreshIfStale()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matched []string
+	for _, term := range m.terms[intent] {
+		if term.pattern.MatchString(query) {
+			matched = append(matched, term.text)
+		}
+	}
+	return matched
+}
+
+// Terms returns the current terms of every intent
+func (m *IntentMatcher) Terms() map[string]IntentTerms {
+	m.refreshIfStale()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make(map[string]IntentTerms, len(m.terms))
+	for intent, terms := range m.terms {
+		entry := IntentTerms{Keywords: []string{}, Derived: []string{}}
+		for _, term := range terms {
+			if term.derived {
+				entry.Derived = append(entry.Derived, term.text)
+			} else {
+				entry.Keywords = append(entry.Keywords, term.text)
+			}
+		}
+		result[intent] = entry
+	}
+	return result
+}
+
+// Refresh reloads intents, keywords and synonyms from the database and
+// re-derives the names taken from the portfolio data
+func (m *IntentMatcher) Refresh() error {
+	if m.db == nil {
+		return fmt.Errorf("database connection not available")
+	}
+
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	keywords, err := m.loadKeywords()
+	if err != nil {
+		m.markLoaded()
+		return fmt.Errorf("failed to load intents: %v", err)
+	}
+
+	data, err := m.loadIntentData()
+	if err != nil {
+		log.Printf("⚠️ Failed to load intent data from portfolio, using keywords only: %v", err)
+	}
+
+	terms := buildIntentTerms(keywords, data)
+
+	m.mu.Lock()
+	m.terms = terms
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+
+	log.Printf("🧭 Intent matcher refreshed: %d intents, %d companies, %d technologies, %d projects",
+		len(keywords), len(data.Companies), len(data.Technologies), len(data.ProjectTitles))
+	return nil
+}
+
+// refreshIfStale reloads the taxonomy in the background when it is older than
+// the refresh interval, so chat requests never wait on the database. The
+// current terms keep matching until the reload finishes.
+func (m *IntentMatcher) refreshIfStale() {
+	if m.db == nil {
+		return
+	}
+
+	m.mu.RLock()
+	stale := time.Since(m.loadedAt) >= m.refreshInterval
+	m.mu.RUnlock()
+
+	if stale && m.refreshing.CompareAndSwap(false, true) {
+		go func() {
+			defer m.refreshing.Store(false)
+			if err := m.Refresh(); err != nil {
+				log.Printf("⚠️ Intent matcher refresh failed, keeping current terms: %v", err)
+			}
+		}()
+	}
+}
+
+// markLoaded delays the next refresh attempt after a failure
+func (m *IntentMatcher) markLoaded() {
+	m.mu.Lock()
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+}
+
+// loadKeywords reads the active intents with their keywords and synonyms
+func (m *IntentMatcher) loadKeywords() (map[string][]string, error) {
+	rows, err := m.db.Query(`
+		SELECT i.name, k.keyword, k.synonyms
+		FROM chat_intents i
+		LEFT JOIN chat_intent_keywords k ON k.intent_id = i.id
+		WHERE i.active = true
+		ORDER BY i.name, k.keyword
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keywords := make(map[string][]string)
+	for rows.Next() {
+		var name string
+		var keyword sql.NullString
+		var synonyms pq.StringArray
+		if err := rows.Scan(&name, &keyword, &synonyms); err != nil {
+			return nil, err
+		}
+		if _, ok := keywords[name]; !ok {
+			keywords[name] = []string{}
+		}
+		if keyword.Valid {
+			keywords[name] = append(keywords[name], keyword.String)
+		}
+		keywords[name] = append(keywords[name], synonyms...)
+	}
+	return keywords, rows.Err()
+}
+
+// loadIntentData reads the company names, technologies and project titles
+func (m *IntentMatcher) loadIntentData() (intentData, error) {
+	var data intentData
+
+	queries := []struct {
+		target *[]string
+		query  string
+	}{
+		{&data.Companies, `SELECT DISTINCT company FROM experience WHERE active = true`},
+		{&data.Technologies, `
+			SELECT name FROM skills WHERE active = true
+			UNION SELECT unnest(technologies) FROM experience WHERE active = true
+			UNION SELECT unnest(technologies) FROM projects WHERE active = true`},
+		{&data.ProjectTitles, `SELECT title FROM projects WHERE active = true`},
+	}
+
+	for _, q := range queries {
+		rows, err := m.db.Query(q.query)
+		if err != nil {
+			return data, err
+		}
+		for rows.Next() {
+			var value string
+			if err := rows.Scan(&value); err == nil {
+				*q.target = append(*q.target, value)
+			}
+		}
+		rows.Close()
+	}
+
+	return data, nil
+}
+
+// buildIntentTerms combines keywords with the names derived from the data
+func buildIntentTerms(keywords map[string][]string, data intentData) map[string][]intentTerm {
+	terms := make(map[string][]intentTerm, len(keywords))
+	seen := make(map[string]map[string]bool, len(keywords))
+
+	add := func(intent, text string, derived bool) {
+		text = strings.Join(strings.Fields(strings.ToLower(text)), " ")
+		if text == "" {
+			return
+		}
+		if seen[intent] == nil {
+			seen[intent] = make(map[string]bool)
+		}
+		if seen[intent][text] {
+			return
+		}
+		seen[intent][text] = true
+
+		term := intentTerm{text: text, derived: derived, pattern: intentKeywordPattern(text)}
+		if derived {
+			term.pattern = skillPattern(text, false)
+		}
+		terms[intent] = append(terms[intent], term)
+	}
+
+	for intent, words := range keywords {
+		terms[intent] = []intentTerm{}
+		for _, word := range words {
+			add(intent, word, false)
+		}
+	}
+
+	// Derived names only augment intents that exist and are active
+	if _, ok := terms[IntentExperience]; ok {
+		for _, company := range data.Companies {
+			add(IntentExperience, normalizeCompanyName(company), true)
+		}
+	}
+	if _, ok := terms[IntentSkills]; ok {
+		for _, tech := range data.Technologies {
+			add(IntentSkills, tech, true)
+		}
+	}
+	if _, ok := terms[IntentProjects]; ok {
+		for _, title := range data.ProjectTitles {
+			add(IntentProjects, title, true)
+		}
+	}
+
+	for intent := range terms {
+		sort.SliceStable(terms[intent], func(i, j int) bool {
+			return !terms[intent][i].derived && terms[intent][j].derived
+		})
+	}
+	return terms
+}
+
+// intentKeywordPattern matches a keyword as whole words, optionally pluralized
+func intentKeywordPattern(keyword string) *regexp.Regexp {
+	words := strings.Fields(keyword)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	return regexp.MustCompile(`(?i)(?:^|[^\pL\pN])(` + strings.Join(words, `[\s-]+`) + `)(?:e?s)?(?:$|[^\pL\pN+#])`)
+}
+
+// normalizeCompanyName drops legal suffixes so "Namecheap, Inc" matches "namecheap"
+func normalizeCompanyName(company string) string {
+	name := strings.ToLower(strings.TrimSpace(company))
+	for _, suffix := range companySuffixes {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return strings.TrimSpace(name)
+}
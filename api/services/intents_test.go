@@ -0,0 +1,140 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestIntentMatcherDefaultKeywords(t *testing.T) {
+	matcher := NewIntentMatcher(nil)
+
+	tests := []struct {
+		intent   string
+		query    string
+		expected bool
+	}{
+		{IntentContact, "contact", true},
+		{IntentContact, "email", true},
+		{IntentContact, "reach", true},
+		{IntentContact, "hire", true},
+		{IntentContact, "available", true},
+		{IntentContact, "linkedin", true},
+		{IntentContact, "github", true},
+		{IntentContact, "phone", false},
+		{IntentContact, "get in touch", false},
+		{IntentContact, "hello", false},
+		{IntentContact, "skills", false},
+		{IntentContact, "", false},
+
+		{IntentSkills, "skill", true},
+		{IntentSkills, "technology", true},
+		{IntentSkills, "tech", true},
+		{IntentSkills, "stack", true},
+		{IntentSkills, "tools", true},
+		{IntentSkills, "languages", true},
+		{IntentSkills, "kubernetes", true},
+		{IntentSkills, "aws", true},
+		{IntentSkills, "go", true},
+		{IntentSkills, "python", true},
+		{IntentSkills, "devops", true},
+		{IntentSkills, "sre", true},
+		{IntentSkills, "good", false},
+		{IntentSkills, "ago", false},
+		{IntentSkills, "programming", false},
+		{IntentSkills, "capabilities", false},
+		{IntentSkills, "contact", false},
+		{IntentSkills, "hello", false},
+		{IntentSkills, "", false},
+
+		{IntentExperience, "experience", true},
+		{IntentExperience, "work", true},
+		{IntentExperience, "job", true},
+		{IntentExperience, "career", true},
+		{IntentExperience, "company", true},
+		{IntentExperience, "role", true},
+		{IntentExperience, "position", true},
+		{IntentExperience, "background", true},
+		{IntentExperience, "employment", false},
+		{IntentExperience, "mobimeo", false}, // company names come from the experience table
+		{IntentExperience, "contact", false},
+		{IntentExperience, "skills", false},
+		{IntentExperience, "", false},
+
+		{IntentProjects, "project", true},
+		{IntentProjects, "projects", true},
+		{IntentProjects, "site", true},
+		{IntentProjects, "github", true},
+		{IntentProjects, "build", true},
+		{IntentProjects, "created", true},
+		{IntentProjects, "developed", true},
+		{IntentProjects, "bruno site", true},
+		{IntentProjects, "knative", true},
+		{IntentProjects, "portfolio", false},
+		{IntentProjects, "work", false},
+		{IntentProjects, "applications", false},
+		{IntentProjects, "apps", false},
+		{IntentProjects, "contact", false},
+		{IntentProjects, "skills", false},
+		{IntentProjects, "", false},
+	}
+
+	for _, tt := range tests {
+		if got := matcher.Matches(tt.intent, tt.query); got != tt.expected {
+			t.Errorf("Matches(%s, %q) = %v, want %v", tt.intent, tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestIntentMatcherAugmentsFromData(t *testing.T) {
+	matcher := NewIntentMatcher(nil)
+	matcher.terms = buildIntentTerms(
+		map[string][]string{
+			IntentExperience: {"experience", "career"},
+			IntentSkills:     {"stack"},
+			IntentProjects:   {"project"},
+		},
+		intentData{
+			Companies:     []string{"Mobimeo", "Namecheap, Inc"},
+			Technologies:  []string{"Terraform", "Go"},
+			ProjectTitles: []string{"Knative Lambda"},
+		},
+	)
+
+	tests := []struct {
+		intent   string
+		query    string
+		expected bool
+	}{
+		{IntentExperience, "What did he do at Mobimeo?", true},
+		{IntentExperience, "Tell me about Namecheap", true},
+		{IntentExperience, "Any career highlights?", true},
+		{IntentExperience, "Where is he based?", false},
+		{IntentSkills, "Does he use Terraform?", true},
+		{IntentSkills, "Does he know Go?", true},
+		{IntentSkills, "Is he good at teamwork?", false}, // terms match whole words
+		{IntentProjects, "Explain the knative lambda", true},
+		{IntentProjects, "Show me some projects", true},
+		{IntentContact, "How can I email him?", false}, // inactive or missing intents never match
+	}
+
+	for _, tt := range tests {
+		if got := matcher.Matches(tt.intent, tt.query); got != tt.expected {
+			t.Errorf("Matches(%s, %q) = %v, want %v", tt.intent, tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestIntentMatcherTerms(t *testing.T) {
+	matcher := NewIntentMatcher(nil)
+	matcher.terms = buildIntentTerms(
+		map[string][]string{IntentExperience: {"Experience", "experience", "job"}},
+		intentData{Companies: []string{"Crealytics GmbH"}},
+	)
+
+	terms := matcher.Terms()[IntentExperience]
+	if len(terms.Keywords) != 2 || terms.Keywords[0] != "experience" || terms.Keywords[1] != "job" {
+		t.Errorf("Expected deduplicated lowercase keywords, got %v", terms.Keywords)
+	}
+	if len(terms.Derived) != 1 || terms.Derived[0] != "crealytics" {
+		t.Errorf("Expected company name without legal suffix, got %v", terms.Derived)
+	}
+}
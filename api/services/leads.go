@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Lead statuses, in the order a lead moves through them
+const (
+	LeadStatusNew       = "new"
+	LeadStatusContacted = "contacted"
+	LeadStatusClosed    = "closed"
+)
+
+// LeadStatuses lists the valid lead statuses
+var LeadStatuses = []string{LeadStatusNew, LeadStatusContacted, LeadStatusClosed}
+
+// leadOfferMessage is shown next to the answer when a visitor talks about hiring
+const leadOfferMessage = "Hiring? Leave your name, email and company and Bruno will get back to you personally."
+
+// hiringClassifierPrompt asks the classify model for a yes or no answer
+const hiringClassifierPrompt = "You classify messages sent to Bruno's portfolio chatbot. Answer \"yes\" if the sender wants to hire, recruit or interview Bruno or offers him a job, otherwise answer \"no\". Answer with one word."
+
+// LeadOffer asks a visitor who shows hiring intent for their contact details
+type LeadOffer struct {
+	Message string   `json:"message"`
+	Fields  []string `json:"fields"`
+}
+
+// ValidLeadStatus reports whether status is a lead status
+func ValidLeadStatus(status string) bool {
+	for _, s := range LeadStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// leadOffer returns the lead capture offer for a message with hiring intent,
+// once per session. Stateless and probe requests never get an offer.
+func (llm *LLMService) leadOffer(ctx context.Context, request ChatRequest, sessionID string, stateless bool) *LeadOffer {
+	if stateless || request.Probe {
+		return nil
+	}
+	// Skip the classification once the offer has been made
+	if offered, err := llm.sessions.LeadOffered(ctx, sessionID); err == nil && offered {
+		return nil
+	}
+	if !llm.hiringIntent(ctx, request.Message) {
+		return nil
+	}
+
+	first, err := llm.sessions.MarkLeadOffered(ctx, sessionID)
+	if err != nil {
+		log.Printf("⚠️ Failed to record lead offer for session %s: %v", sessionID, err)
+	} else if !first {
+		return nil
+	}
+
+	log.Printf("🤝 Hiring intent in session %s, offering lead capture", sessionID)
+	return &LeadOffer{Message: leadOfferMessage, Fields: []string{"name", "email", "company"}}
+}
+
+// hiringIntent asks the classify model whether a message is about hiring
+// Bruno. The hiring keywords decide when the model fails or the daily budget
+// is spent.
+func (llm *LLMService) hiringIntent(ctx context.Context, message string) bool {
+	keywords := llm.contextBuilder.Intents().Matches(IntentHiring, message)
+	if llm.usage.Status(ctx).Exceeded {
+		return keywords
+	}
+
+	requestID := fmt.Sprintf("classify_%d", time.Now().UnixNano())
+	answer, err := llm.callOllama(ctx, ModelRoleClassify, []ChatMessage{
+		{Role: "system", Content: hiringClassifierPrompt},
+		{Role: "user", Content: message},
+	}, &OllamaOptions{NumPredict: 3}, requestID)
+	if err != nil {
+		log.Printf("⚠️ [%s] Hiring classification failed, using keywords: %v", requestID, err)
+		return keywords
+	}
+
+	switch answer = strings.ToLower(strings.TrimSpace(answer)); {
+	case strings.HasPrefix(answer, "yes"):
+		return true
+	case strings.HasPrefix(answer, "no"):
+		return false
+	default:
+		log.Printf("⚠️ [%s] Unexpected hiring classification %q, using keywords", requestID, truncateString(answer, 20))
+		return keywords
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidLeadStatus(t *testing.T) {
+	for _, status := range []string{LeadStatusNew, LeadStatusContacted, LeadStatusClosed} {
+		if !ValidLeadStatus(status) {
+			t.Errorf("Expected %q to be a valid lead status", status)
+		}
+	}
+	if ValidLeadStatus("won") {
+		t.Error("Expected unknown statuses to be rejected")
+	}
+}
+
+func TestLeadOfferOnHiringIntent(t *testing.T) {
+	var classified []string
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request OllamaRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		classified = append(classified, request.Model)
+		answer := "no"
+		if strings.Contains(request.Messages[len(request.Messages)-1].Content, "team") {
+			answer = "Yes."
+		}
+		json.NewEncoder(w).Encode(OllamaResponse{Message: OllamaMessage{Role: "assistant", Content: answer}, Done: true})
+	}))
+	defer ollama.Close()
+
+	llm := &LLMService{
+		model:          "gemma3n:e4b",
+		routes:         ModelRoutes{ModelRoleClassify: {Role: ModelRoleClassify, Model: "gemma3:270m", Backend: ollama.URL}},
+		contextBuilder: NewContextBuilder(nil),
+		sessions:       NewSessionStore(nil, nil),
+		httpClient:     ollama.Client(),
+	}
+	ctx := context.Background()
+
+	offer := llm.leadOffer(ctx, ChatRequest{Message: "Would Bruno like to join the platform team at our company?"}, "sess_1", false)
+	if offer == nil || len(offer.Fields) != 3 {
+		t.Fatalf("Expected a lead capture offer when the classifier finds hiring intent, got %+v", offer)
+	}
+	if len(classified) != 1 || classified[0] != "gemma3:270m" {
+		t.Errorf("Expected one request to the classify model, got %v", classified)
+	}
+
+	if offer := llm.leadOffer(ctx, ChatRequest{Message: "Is he open to an interview about Kubernetes talks?"}, "sess_1", false); offer != nil {
+		t.Errorf("Expected the classifier to override the keywords, got %+v", offer)
+	}
+	if offer := llm.leadOffer(ctx, ChatRequest{Message: "We are hiring"}, "sess_1", true); offer != nil {
+		t.Errorf("Expected no offer for stateless requests, got %+v", offer)
+	}
+	if offer := llm.leadOffer(ctx, ChatRequest{Message: "We are hiring", Probe: true}, "sess_1", false); offer != nil {
+		t.Errorf("Expected no offer for probes, got %+v", offer)
+	}
+	if len(classified) != 2 {
+		t.Errorf("Expected stateless and probe requests to skip the classifier, got %d requests", len(classified))
+	}
+}
+
+// TestHiringIntentFallsBackToKeywords tests the keyword match when the
+// classify model is unavailable
+func TestHiringIntentFallsBackToKeywords(t *testing.T) {
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer ollama.Close()
+
+	llm := &LLMService{model: "gemma3n:e4b", ollamaURL: ollama.URL, contextBuilder: NewContextBuilder(nil), httpClient: ollama.Client()}
+	ctx := context.Background()
+
+	if !llm.hiringIntent(ctx, "We are hiring a platform engineer, is he open to an interview?") {
+		t.Error("Expected the hiring keywords to decide when the classifier fails")
+	}
+	if llm.hiringIntent(ctx, "What does Bruno know about Kubernetes?") {
+		t.Error("Expected no hiring intent without hiring keywords")
+	}
+}
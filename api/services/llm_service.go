@@ -1,45 +1,94 @@
 package services
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // LLMService handles communication with Ollama
 type LLMService struct {
 	ollamaURL      string
 	model          string
+	historySize    int
 	contextBuilder *ContextBuilder
+	sessions       *SessionStore
+	faqs           *FAQMatcher
+	routes         ModelRoutes
+	usage          *UsageTracker
+	redactor       *Redactor
+	grounding      *GroundingChecker
+	shadow         *ShadowEvaluator
 	httpClient     *http.Client
 }
 
+// PromptTemplateVersion identifies the system prompt and the context prompt
+// layout; bump it whenever either changes
+const PromptTemplateVersion = "3"
+
+const chatSystemPrompt = "You are a fact-based assistant. NEVER use greetings, introductions, or pleasantries. Answer questions immediately with facts only. Maximum 2 sentences. Start directly with the answer."
+
 // ChatRequest represents an incoming chat request
 type ChatRequest struct {
-	Message string `json:"message" binding:"required"`
-	Context string `json:"context,omitempty"`
+	Message   string `json:"message" binding:"required"`
+	Context   string `json:"context,omitempty"` // chat mode, see ParseChatMode
+	SessionID string `json:"session_id,omitempty"`
+
+	// History, when non-nil, replaces the stored session history and the
+	// exchange is not persisted (used by stateless clients such as the
+	// OpenAI-compatible API)
+	History []ChatMessage `json:"-"`
+
+	// Probe marks synthetic requests such as canaries, which are not
+	// sampled for shadow evaluation
+	Probe bool `json:"-"`
 }
 
 // ChatResponse represents the response from the chatbot
 type ChatResponse struct {
-	Response  string   `json:"response"`
-	Sources   []string `json:"sources,omitempty"`
-	Model     string   `json:"model"`
-	Timestamp string   `json:"timestamp"`
+	Response     string           `json:"response"`
+	Sources      []string         `json:"sources,omitempty"`
+	Source       string           `json:"source"`
+	Mode         string           `json:"mode"`
+	Grounding    *GroundingReport `json:"grounding,omitempty"`
+	Degraded     bool             `json:"degraded,omitempty"` // daily budget spent
+	Lead         *LeadOffer       `json:"lead_capture,omitempty"`
+	Model        string           `json:"model"`
+	SessionID    string           `json:"session_id,omitempty"`
+	SessionToken string           `json:"session_token,omitempty"` // first answer of a new session only; grants transcript export and lead submission
+	OwnerReplies []SessionMessage `json:"owner_replies,omitempty"` // HTTP only: owner replies of a live takeover not delivered yet
+	Timestamp    string           `json:"timestamp"`
 }
 
+// TokenHandler receives streamed response tokens; returning an error aborts the stream
+type TokenHandler func(token string) error
+
 // OllamaRequest represents request format for Ollama Chat API
 type OllamaRequest struct {
-	Model    string        `json:"model"`
-	Messages []ChatMessage `json:"messages"`
-	Stream   bool          `json:"stream"`
+	Model    string         `json:"model"`
+	Messages []ChatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Options  *OllamaOptions `json:"options,omitempty"`
+}
+
+// OllamaOptions holds the generation parameters of an Ollama request
+type OllamaOptions struct {
+	NumPredict int `json:"num_predict,omitempty"`
 }
 
 type ChatMessage struct {
@@ -51,6 +100,13 @@ type ChatMessage struct {
 type OllamaResponse struct {
 	Message OllamaMessage `json:"message"`
 	Done    bool          `json:"done"`
+
+	// Usage, reported with the final response; durations are in nanoseconds
+	PromptEvalCount    int   `json:"prompt_eval_count,omitempty"`
+	EvalCount          int   `json:"eval_count,omitempty"`
+	TotalDuration      int64 `json:"total_duration,omitempty"`
+	PromptEvalDuration int64 `json:"prompt_eval_duration,omitempty"`
+	EvalDuration       int64 `json:"eval_duration,omitempty"`
 }
 
 type OllamaMessage struct {
@@ -59,20 +115,41 @@ type OllamaMessage struct {
 }
 
 // NewLLMService creates a new LLM service
-func NewLLMService(db *sql.DB) *LLMService {
+func NewLLMService(db *sql.DB, rdb *redis.Client) *LLMService {
+	historySize, err := strconv.Atoi(getEnv("CHAT_HISTORY_MESSAGES", "6"))
+	if err != nil || historySize < 0 {
+		historySize = 6
+	}
+
+	contextBuilder := NewContextBuilder(db)
+	redactor := NewRedactor(contextBuilder)
+	routes := LoadModelRoutes()
+	answer := routes.Endpoint(ModelRoleAnswer)
+
 	service := &LLMService{
-		ollamaURL:      getEnv("OLLAMA_URL", "http://192.168.0.3:11434"),
-		model:          getEnv("GEMMA_MODEL", "gemma3n:e4b"),
-		contextBuilder: NewContextBuilder(db),
+		ollamaURL:      answer.Backend,
+		model:          answer.Model,
+		routes:         routes,
+		usage:          NewUsageTracker(db),
+		historySize:    historySize,
+		contextBuilder: contextBuilder,
+		sessions:       NewSessionStore(rdb, redactor),
+		faqs:           NewFAQMatcher(db),
+		redactor:       redactor,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
 	}
 
+	service.grounding = NewGroundingChecker(service.contextBuilder)
+	service.shadow = NewShadowEvaluator(db, service.ollamaURL, service.grounding, redactor, service.usage)
+
 	log.Printf("🤖 LLM Service initialized")
 	log.Printf("   📍 Ollama URL: %s", service.ollamaURL)
 	log.Printf("   🎯 Model: %s", service.model)
 	log.Printf("   ⏱️  Timeout: %v", service.httpClient.Timeout)
+	log.Printf("   💬 History messages: %d", service.historySize)
+	routes.logRoutes()
 
 	// Test connection on startup
 	go service.testConnectionOnStartup()
@@ -99,28 +176,167 @@ func (llm *LLMService) testConnectionOnStartup() {
 	}
 }
 
+// Sessions returns the session store shared by all chat transports
+func (llm *LLMService) Sessions() *SessionStore {
+	return llm.sessions
+}
+
+// Intents returns the intent matcher used to build chat context
+func (llm *LLMService) Intents() *IntentMatcher {
+	return llm.contextBuilder.Intents()
+}
+
+// FAQs returns the matcher that answers chat messages from approved FAQ entries
+func (llm *LLMService) FAQs() *FAQMatcher {
+	return llm.faqs
+}
+
+// Shadow returns the candidate model evaluator, or nil when shadow mode is disabled
+func (llm *LLMService) Shadow() *ShadowEvaluator {
+	return llm.shadow
+}
+
+// Redactor returns the PII redactor for logs and stored conversations
+func (llm *LLMService) Redactor() *Redactor {
+	return llm.redactor
+}
+
+// Usage returns the token and compute usage tracker
+func (llm *LLMService) Usage() *UsageTracker {
+	return llm.usage
+}
+
+// ModelRoutes returns the endpoint of every model role
+func (llm *LLMService) ModelRoutes() []ModelEndpoint {
+	routes := llm.routes
+	if routes == nil {
+		routes = ModelRoutes{}
+	}
+	routes = maps.Clone(routes)
+	routes[ModelRoleAnswer] = llm.endpoint(ModelRoleAnswer)
+	return routes.Endpoints()
+}
+
+// endpoint returns the model serving a role. The answer role always uses the
+// service's own model, which shadow candidates override.
+func (llm *LLMService) endpoint(role string) ModelEndpoint {
+	if role == ModelRoleAnswer || llm.routes == nil {
+		return ModelEndpoint{Role: role, Model: llm.model, Backend: llm.ollamaURL}
+	}
+	return llm.routes.Endpoint(role)
+}
+
+// Grounding returns the checker that verifies answers against their context
+func (llm *LLMService) Grounding() *GroundingChecker {
+	return llm.grounding
+}
+
+// Model returns the name of the model used to answer chat requests
+func (llm *LLMService) Model() string {
+	return llm.model
+}
+
 // ProcessChat handles a chat request and returns an AI response
 func (llm *LLMService) ProcessChat(request ChatRequest) (*ChatResponse, error) {
+	return llm.ProcessChatStream(context.Background(), request, nil)
+}
+
+// ProcessChatStream runs the chat pipeline, streaming tokens to onToken when
+// it is set. Cancelling ctx aborts the generation.
+func (llm *LLMService) ProcessChatStream(ctx context.Context, request ChatRequest, onToken TokenHandler) (*ChatResponse, error) {
 	startTime := time.Now()
 	requestID := fmt.Sprintf("chat_%d", startTime.UnixNano())
 
 	log.Printf("🚀 [%s] Starting chat processing", requestID)
-	log.Printf("   📝 Message: %s", truncateString(request.Message, 100))
+	log.Printf("   📝 Message: %s", llm.redactor.Log(truncateString(request.Message, 100)))
 	log.Printf("   🎯 Model: %s", llm.model)
 	log.Printf("   🌐 Ollama URL: %s", llm.ollamaURL)
+	log.Printf("   📡 Streaming: %v", onToken != nil)
 	log.Printf("   🔧 Environment: OLLAMA_URL=%s", os.Getenv("OLLAMA_URL"))
 	log.Printf("   🔧 Environment: GEMMA_MODEL=%s", os.Getenv("GEMMA_MODEL"))
 
+	mode, err := ParseChatMode(request.Context)
+	if err != nil {
+		log.Printf("❌ [%s] %v", requestID, err)
+		return nil, err
+	}
+	log.Printf("   🎭 Mode: %s", mode)
+
+	// Reject messages that violate guardrails before doing any work
+	if violation := CheckGuardrails(request.Message); violation != nil {
+		log.Printf("🛡️ [%s] Guardrail triggered: %s", requestID, violation.Rule)
+		return nil, violation
+	}
+
+	stateless := request.History != nil
+	history := trimHistory(request.History, llm.historySize)
+	session := &ChatSession{ID: request.SessionID}
+
+	// Load the conversation so every transport shares the same history
+	if !stateless {
+		stored, err := llm.sessions.GetOrCreate(ctx, request.SessionID)
+		if err != nil {
+			log.Printf("⚠️ [%s] Session store unavailable, continuing without history: %v", requestID, err)
+			if session.ID == "" {
+				session.ID, _ = newSessionID()
+			}
+		} else {
+			session = stored
+		}
+		history = session.RecentMessages(llm.historySize)
+	}
+	log.Printf("   💬 Session: %s (%d history messages, stateless=%v)", session.ID, len(history), stateless)
+	ctx = withUsageSession(ctx, session.ID)
+
+	// Approved FAQ answers are returned as-is without calling the model
+	if match := llm.faqs.Match(request.Message); match != nil {
+		log.Printf("❓ [%s] FAQ #%d matched %q (score %.2f)", requestID, match.Entry.ID, match.MatchedOn, match.Score)
+		chatResponse, err := llm.answerFromFAQ(ctx, request, mode, session.ID, stateless, match, startTime, onToken)
+		if err != nil {
+			return nil, err
+		}
+		chatResponse.Lead = llm.leadOffer(ctx, request, session.ID, stateless)
+		chatResponse.SessionToken = session.Token
+		return chatResponse, nil
+	}
+
+	// Past the daily budget, answer FAQs only or generate shorter answers
+	degraded := false
+	if budget := llm.usage.Status(ctx); budget.Exceeded {
+		if budget.Budget.Mode == BudgetModeFAQOnly {
+			log.Printf("💰 [%s] Daily budget spent, answering in FAQ-only mode", requestID)
+			chatResponse, err := llm.answerOverBudget(ctx, request, mode, session.ID, stateless, startTime, onToken)
+			if err != nil {
+				return nil, err
+			}
+			chatResponse.SessionToken = session.Token
+			return chatResponse, nil
+		}
+		log.Printf("💰 [%s] Daily budget spent, generating a degraded answer", requestID)
+		degraded = true
+		history = nil
+	}
+
 	// Build context from PostgreSQL data
 	log.Printf("🔧 [%s] Building context from database...", requestID)
-	context, err := llm.contextBuilder.BuildContext(request.Message)
+	retrieved, err := llm.contextBuilder.RetrieveContextForMode(request.Message, mode)
+	var modeErr *ChatModeError
+	if errors.As(err, &modeErr) {
+		return nil, err
+	}
 	if err != nil {
 		log.Printf("❌ [%s] Context building failed: %v", requestID, err)
 		log.Printf("   🔍 Database connection status: %v", llm.contextBuilder.db != nil)
 		return nil, fmt.Errorf("failed to build context: %v", err)
 	}
-	log.Printf("✅ [%s] Context built successfully (%d chars)", requestID, len(context))
-	log.Printf("   📄 Context preview: %s", truncateString(context, 200))
+	log.Printf("✅ [%s] Context built successfully (%d chars, %d snippets)", requestID, len(retrieved.Prompt), len(retrieved.Snippets))
+	log.Printf("   📄 Context preview: %s", llm.redactor.Log(truncateString(retrieved.Prompt, 200)))
+
+	messages := llm.buildMessages(mode.SystemPrompt, history, retrieved.Prompt)
+	options := mode.ollamaOptions()
+	if degraded {
+		options = llm.usage.degradedOptions(options)
+	}
 
 	// Generate response using Ollama
 	log.Printf("🦙 [%s] Calling Ollama API...", requestID)
@@ -130,7 +346,14 @@ func (llm *LLMService) ProcessChat(request ChatRequest) (*ChatResponse, error) {
 		log.Printf("   💡 This might indicate network connectivity issues")
 	}
 
-	response, err := llm.callOllama(context, requestID)
+	var response string
+	generationStart := time.Now()
+	if onToken != nil {
+		response, err = llm.callOllamaStream(ctx, ModelRoleAnswer, messages, options, requestID, onToken)
+	} else {
+		response, err = llm.callOllama(ctx, ModelRoleAnswer, messages, options, requestID)
+	}
+	generationLatency := time.Since(generationStart)
 
 	if err != nil {
 		log.Printf("❌ [%s] Ollama API call failed: %v", requestID, err)
@@ -139,12 +362,55 @@ func (llm *LLMService) ProcessChat(request ChatRequest) (*ChatResponse, error) {
 		return nil, fmt.Errorf("LLM request failed: %v", err)
 	}
 
+	var grounding *GroundingReport
+	if llm.grounding != nil && llm.grounding.Action() != GroundingActionOff {
+		response, grounding = llm.checkGrounding(ctx, request.Message, messages, options, response, retrieved.Snippets, requestID, onToken != nil)
+	}
+
+	// Replay a sample of the prompts against the candidate model
+	if llm.shadow != nil && !request.Probe && !degraded {
+		primaryGrounding := grounding
+		if primaryGrounding == nil {
+			primaryGrounding = llm.grounding.Check(response, request.Message, retrieved.Snippets)
+		}
+		llm.shadow.Submit(ShadowJob{
+			RequestID:        requestID,
+			SessionID:        session.ID,
+			Question:         request.Message,
+			Mode:             mode.String(),
+			PrimaryModel:     llm.model,
+			Messages:         messages,
+			Options:          options,
+			Snippets:         retrieved.Snippets,
+			PrimaryAnswer:    response,
+			PrimaryLatency:   generationLatency,
+			PrimaryGrounding: primaryGrounding,
+		})
+	}
+
 	// Create response
 	chatResponse := &ChatResponse{
 		Response:  response,
 		Model:     llm.model,
+		SessionID: session.ID,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Sources:   []string{"PostgreSQL Database"},
+		Sources:   retrieved.References(),
+		Source:    ResponseSourceLLM,
+		Mode:      mode.String(),
+		Grounding: grounding,
+		Degraded:  degraded,
+		Lead:      llm.leadOffer(ctx, request, session.ID, stateless),
+	}
+	chatResponse.SessionToken = session.Token
+
+	if !stateless {
+		now := time.Now().UTC()
+		if err := llm.sessions.AppendMessages(ctx, session.ID,
+			SessionMessage{Role: "user", Content: request.Message, Timestamp: startTime.UTC()},
+			SessionMessage{Role: "assistant", Content: response, Sources: chatResponse.Sources, Timestamp: now},
+		); err != nil {
+			log.Printf("⚠️ [%s] Failed to store session messages: %v", requestID, err)
+		}
 	}
 
 	duration := time.Since(startTime)
@@ -155,28 +421,227 @@ func (llm *LLMService) ProcessChat(request ChatRequest) (*ChatResponse, error) {
 	return chatResponse, nil
 }
 
+// checkGrounding verifies the claims of an answer against its context. Answers
+// below the minimum score are regenerated once when configured and not yet
+// streamed; otherwise they are flagged. The better of the two answers is kept.
+func (llm *LLMService) checkGrounding(ctx context.Context, question string, messages []ChatMessage, options *OllamaOptions, response string, snippets []ContextSnippet, requestID string, streamed bool) (string, *GroundingReport) {
+	report := llm.grounding.Check(response, question, snippets)
+
+	if report.Flagged && llm.grounding.Action() == GroundingActionRegenerate && !streamed {
+		log.Printf("🔁 [%s] Regenerating answer with ungrounded claims: %s", requestID, llm.redactor.Log(strings.Join(report.Ungrounded, ", ")))
+		groundingRegenerations.Inc()
+
+		retry := append(append([]ChatMessage{}, messages...),
+			ChatMessage{Role: "assistant", Content: response},
+			ChatMessage{Role: "user", Content: regenerationPrompt(report)},
+		)
+		regenerated, err := llm.callOllama(ctx, ModelRoleAnswer, retry, options, requestID)
+		if err != nil {
+			log.Printf("⚠️ [%s] Regeneration failed, keeping the first answer: %v", requestID, err)
+		} else if retried := llm.grounding.Check(regenerated, question, snippets); retried.Score >= report.Score {
+			response, report = regenerated, retried
+		}
+	}
+
+	llm.grounding.Record(report)
+	if report.Flagged {
+		log.Printf("⚠️ [%s] Answer flagged as ungrounded (score %.2f): %s", requestID, report.Score, llm.redactor.Log(strings.Join(report.Ungrounded, ", ")))
+	}
+	return response, report
+}
+
+// answerFromFAQ returns the canonical answer of a matched FAQ entry and stores
+// the exchange like a model answer
+func (llm *LLMService) answerFromFAQ(ctx context.Context, request ChatRequest, mode ChatMode, sessionID string, stateless bool, match *FAQMatch, startTime time.Time, onToken TokenHandler) (*ChatResponse, error) {
+	answer := match.Entry.Answer
+	if onToken != nil {
+		if err := onToken(answer); err != nil {
+			return nil, err
+		}
+	}
+
+	chatResponse := &ChatResponse{
+		Response:  answer,
+		Model:     llm.model,
+		SessionID: sessionID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Sources:   []string{fmt.Sprintf("faq#%d", match.Entry.ID)},
+		Source:    ResponseSourceFAQ,
+		Mode:      mode.String(),
+	}
+
+	if !stateless {
+		if err := llm.sessions.AppendMessages(ctx, sessionID,
+			SessionMessage{Role: "user", Content: request.Message, Timestamp: startTime.UTC()},
+			SessionMessage{Role: "assistant", Content: answer, Sources: chatResponse.Sources, Timestamp: time.Now().UTC()},
+		); err != nil {
+			log.Printf("⚠️ Failed to store FAQ answer in session %s: %v", sessionID, err)
+		}
+	}
+
+	return chatResponse, nil
+}
+
+// answerOverBudget tells visitors whose message has no FAQ answer that the
+// daily budget is spent, and stores the exchange like an FAQ answer
+func (llm *LLMService) answerOverBudget(ctx context.Context, request ChatRequest, mode ChatMode, sessionID string, stateless bool, startTime time.Time, onToken TokenHandler) (*ChatResponse, error) {
+	if onToken != nil {
+		if err := onToken(budgetExhaustedMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	chatResponse := &ChatResponse{
+		Response:  budgetExhaustedMessage,
+		SessionID: sessionID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Sources:   []string{},
+		Source:    ResponseSourceBudget,
+		Mode:      mode.String(),
+		Degraded:  true,
+	}
+
+	if !stateless {
+		if err := llm.sessions.AppendMessages(ctx, sessionID,
+			SessionMessage{Role: "user", Content: request.Message, Timestamp: startTime.UTC()},
+			SessionMessage{Role: "assistant", Content: budgetExhaustedMessage, Timestamp: time.Now().UTC()},
+		); err != nil {
+			log.Printf("⚠️ Failed to store budget notice in session %s: %v", sessionID, err)
+		}
+	}
+
+	return chatResponse, nil
+}
+
+// ChatPreview is what the chat pipeline would send to the model for a message
+type ChatPreview struct {
+	Message               string              `json:"message"`
+	SessionID             string              `json:"session_id,omitempty"`
+	Model                 string              `json:"model"`
+	PromptTemplateVersion string              `json:"prompt_template_version"`
+	Mode                  ChatMode            `json:"mode"`
+	Guardrail             *GuardrailViolation `json:"guardrail,omitempty"`
+	FAQ                   *FAQMatch           `json:"faq,omitempty"`
+	Intents               IntentScores        `json:"intents"`
+	Snippets              []ContextSnippet    `json:"snippets"`
+	SystemPrompt          string              `json:"system_prompt"`
+	History               []ChatMessage       `json:"history"`
+	Prompt                string              `json:"prompt"`
+	EstimatedTokens       int                 `json:"estimated_tokens"`
+}
+
+// PreviewChat builds the context and messages for a chat request without
+// calling the model or storing anything. Guardrail violations are reported
+// instead of aborting the preview.
+func (llm *LLMService) PreviewChat(ctx context.Context, request ChatRequest) (*ChatPreview, error) {
+	mode, err := ParseChatMode(request.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ChatPreview{
+		Message:               request.Message,
+		SessionID:             request.SessionID,
+		Model:                 llm.model,
+		PromptTemplateVersion: PromptTemplateVersion,
+		Mode:                  mode,
+		Guardrail:             CheckGuardrails(request.Message),
+		FAQ:                   llm.faqs.Match(request.Message),
+		SystemPrompt:          mode.SystemPrompt,
+		History:               []ChatMessage{},
+	}
+
+	// Existing sessions contribute their history; unknown ones are not created
+	if request.SessionID != "" {
+		session, err := llm.sessions.Get(ctx, request.SessionID)
+		if err == redis.Nil {
+			return nil, ErrSessionNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load session: %v", err)
+		}
+		preview.History = session.RecentMessages(llm.historySize)
+	}
+
+	retrieved, err := llm.contextBuilder.RetrieveContextForMode(request.Message, mode)
+	var modeErr *ChatModeError
+	if errors.As(err, &modeErr) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to build context: %v", err)
+	}
+	preview.Intents = retrieved.Intents
+	preview.Snippets = retrieved.Snippets
+	preview.Prompt = retrieved.Prompt
+
+	for _, message := range llm.buildMessages(preview.SystemPrompt, preview.History, preview.Prompt) {
+		preview.EstimatedTokens += EstimateTokens(message.Content)
+	}
+
+	return preview, nil
+}
+
+// EstimateTokens approximates the token count of text for budgeting, using
+// the common ratio of about four characters per token
+func EstimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// Complete sends a single prompt to the summarize model, bypassing context
+// building and session history
+func (llm *LLMService) Complete(ctx context.Context, systemPrompt, prompt string) (string, error) {
+	requestID := fmt.Sprintf("complete_%d", time.Now().UnixNano())
+
+	// Fit summaries and resume rewrites fall back to their deterministic
+	// versions once the budget is spent
+	if llm.usage.Status(ctx).Exceeded {
+		return "", ErrBudgetExceeded
+	}
+
+	return llm.callOllama(ctx, ModelRoleSummarize, []ChatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: prompt},
+	}, nil, requestID)
+}
+
+// buildMessages assembles the system prompt, conversation history and the
+// context-enriched user prompt
+func (llm *LLMService) buildMessages(systemPrompt string, history []ChatMessage, prompt string) []ChatMessage {
+	messages := []ChatMessage{
+		{
+			Role:    "system",
+			Content: systemPrompt,
+		},
+	}
+	messages = append(messages, history...)
+	messages = append(messages, ChatMessage{
+		Role:    "user",
+		Content: prompt,
+	})
+	return messages
+}
+
 // callOllama sends request to Ollama API with enhanced logging
-func (llm *LLMService) callOllama(prompt string, requestID string) (string, error) {
-	log.Printf("🦙 [%s] Preparing Ollama request", requestID)
-	log.Printf("   📍 URL: %s/api/chat", llm.ollamaURL)
-	log.Printf("   🎯 Model: %s", llm.model)
-	log.Printf("   📝 Prompt length: %d chars", len(prompt))
+func (llm *LLMService) callOllama(ctx context.Context, role string, messages []ChatMessage, options *OllamaOptions, requestID string) (response string, err error) {
+	endpoint := llm.endpoint(role)
+	defer func(startTime time.Time) { observeModelRequest(endpoint, startTime, err) }(time.Now())
+
+	log.Printf("🦙 [%s] Preparing Ollama request (role %s)", requestID, role)
+	log.Printf("   📍 URL: %s/api/chat", endpoint.Backend)
+	log.Printf("   🎯 Model: %s", endpoint.Model)
+	log.Printf("   📝 Messages: %d", len(messages))
 	log.Printf("   🔧 HTTP Client timeout: %v", llm.httpClient.Timeout)
 	log.Printf("   🔧 HTTP Client transport: %T", llm.httpClient.Transport)
 
 	requestBody := OllamaRequest{
-		Model: llm.model,
-		Messages: []ChatMessage{
-			{
-				Role:    "system",
-				Content: "You are a fact-based assistant. NEVER use greetings, introductions, or pleasantries. Answer questions immediately with facts only. Maximum 2 sentences. Start directly with the answer.",
-			},
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-		Stream: false,
+		Model:    endpoint.Model,
+		Messages: messages,
+		Stream:   false,
+		Options:  options,
 	}
 
 	jsonData, err := json.Marshal(requestBody)
@@ -188,28 +653,24 @@ func (llm *LLMService) callOllama(prompt string, requestID string) (string, erro
 
 	// Log request details (without sensitive data)
 	log.Printf("📤 [%s] Sending HTTP POST request", requestID)
-	log.Printf("   🔗 URL: %s/api/chat", llm.ollamaURL)
+	log.Printf("   🔗 URL: %s/api/chat", endpoint.Backend)
 	log.Printf("   📋 Headers: Content-Type=application/json")
 	log.Printf("   ⏱️  Timeout: %v", llm.httpClient.Timeout)
 
 	startTime := time.Now()
-	resp, err := llm.httpClient.Post(
-		fmt.Sprintf("%s/api/chat", llm.ollamaURL),
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
+	resp, err := llm.postOllama(ctx, endpoint.Backend, jsonData)
 	requestDuration := time.Since(startTime)
 
 	if err != nil {
 		log.Printf("❌ [%s] HTTP request failed after %v: %v", requestID, requestDuration, err)
 		log.Printf("💡 [%s] Connection troubleshooting:", requestID)
-		log.Printf("   - Check if Ollama is running on %s", llm.ollamaURL)
+		log.Printf("   - Check if Ollama is running on %s", endpoint.Backend)
 		log.Printf("   - Verify network connectivity")
 		log.Printf("   - Check firewall settings")
-		log.Printf("   - Test with: curl -X POST %s/api/chat", llm.ollamaURL)
+		log.Printf("   - Test with: curl -X POST %s/api/chat", endpoint.Backend)
 		log.Printf("   🔍 Error type: %T", err)
 		log.Printf("   🔍 Network error details: %+v", err)
-		log.Printf("   🔍 DNS resolution test: nslookup %s", strings.TrimPrefix(strings.TrimPrefix(llm.ollamaURL, "http://"), "https://"))
+		log.Printf("   🔍 DNS resolution test: nslookup %s", strings.TrimPrefix(strings.TrimPrefix(endpoint.Backend, "http://"), "https://"))
 		return "", fmt.Errorf("HTTP request failed: %v", err)
 	}
 	defer resp.Body.Close()
@@ -224,7 +685,7 @@ func (llm *LLMService) callOllama(prompt string, requestID string) (string, erro
 		log.Printf("   📊 Status: %d", resp.StatusCode)
 		log.Printf("   📝 Body: %s", string(body))
 		log.Printf("💡 [%s] Error troubleshooting:", requestID)
-		log.Printf("   - Check if model '%s' is available", llm.model)
+		log.Printf("   - Check if model '%s' is available", endpoint.Model)
 		log.Printf("   - Verify Ollama service status")
 		log.Printf("   - Check Ollama logs for errors")
 		return "", fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
@@ -245,17 +706,106 @@ func (llm *LLMService) callOllama(prompt string, requestID string) (string, erro
 		return "", fmt.Errorf("failed to decode response: %v", err)
 	}
 
-	response := strings.TrimSpace(ollamaResp.Message.Content)
+	llm.usage.recordRequest(ctx, ollamaResp.usage(endpoint))
+
+	response = strings.TrimSpace(ollamaResp.Message.Content)
 	response = strings.TrimSpace(response)
 
 	log.Printf("✅ [%s] Ollama response processed successfully", requestID)
 	log.Printf("   📝 Response length: %d chars", len(response))
-	log.Printf("   🎯 Model: %s", llm.model)
+	log.Printf("   🎯 Model: %s", endpoint.Model)
 	log.Printf("   ⏱️  Total time: %v", requestDuration)
 
 	return response, nil
 }
 
+// callOllamaStream sends a streaming request to Ollama and forwards each token to onToken
+func (llm *LLMService) callOllamaStream(ctx context.Context, role string, messages []ChatMessage, options *OllamaOptions, requestID string, onToken TokenHandler) (response string, err error) {
+	endpoint := llm.endpoint(role)
+	defer func(startTime time.Time) { observeModelRequest(endpoint, startTime, err) }(time.Now())
+
+	log.Printf("🦙 [%s] Preparing streaming Ollama request (role %s)", requestID, role)
+	log.Printf("   📍 URL: %s/api/chat", endpoint.Backend)
+	log.Printf("   🎯 Model: %s", endpoint.Model)
+	log.Printf("   📝 Messages: %d", len(messages))
+
+	jsonData, err := json.Marshal(OllamaRequest{
+		Model:    endpoint.Model,
+		Messages: messages,
+		Stream:   true,
+		Options:  options,
+	})
+	if err != nil {
+		log.Printf("❌ [%s] Failed to marshal request: %v", requestID, err)
+		return "", fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	startTime := time.Now()
+	resp, err := llm.postOllama(ctx, endpoint.Backend, jsonData)
+	if err != nil {
+		log.Printf("❌ [%s] HTTP streaming request failed after %v: %v", requestID, time.Since(startTime), err)
+		return "", fmt.Errorf("HTTP request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Printf("❌ [%s] Ollama API error response (status %d): %s", requestID, resp.StatusCode, string(body))
+		return "", fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	// Ollama streams newline-delimited JSON chunks
+	var builder strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var chunk OllamaResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			log.Printf("❌ [%s] Failed to decode stream chunk: %v", requestID, err)
+			return "", fmt.Errorf("failed to decode stream chunk: %v", err)
+		}
+
+		if chunk.Message.Content != "" {
+			builder.WriteString(chunk.Message.Content)
+			if err := onToken(chunk.Message.Content); err != nil {
+				return "", err
+			}
+		}
+
+		if chunk.Done {
+			llm.usage.recordRequest(ctx, chunk.usage(endpoint))
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("❌ [%s] Stream interrupted after %v: %v", requestID, time.Since(startTime), err)
+		return "", fmt.Errorf("stream interrupted: %v", err)
+	}
+
+	response = strings.TrimSpace(builder.String())
+
+	log.Printf("✅ [%s] Ollama stream completed", requestID)
+	log.Printf("   📝 Response length: %d chars", len(response))
+	log.Printf("   ⏱️  Total time: %v", time.Since(startTime))
+
+	return response, nil
+}
+
+// postOllama sends a chat payload to an Ollama backend bound to the request context
+func (llm *LLMService) postOllama(ctx context.Context, backend string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/chat", backend), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return llm.httpClient.Do(req)
+}
+
 // HealthCheck checks if Ollama service is available with enhanced logging
 func (llm *LLMService) HealthCheck() error {
 	log.Printf("🏥 Starting Ollama health check...")
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Model roles; each pipeline step asks for the model of its role
+const (
+	ModelRoleClassify  = "classify"
+	ModelRoleAnswer    = "answer"
+	ModelRoleSummarize = "summarize"
+	ModelRoleEmbed     = "embed"
+)
+
+// ModelRoles lists the model roles
+var ModelRoles = []string{ModelRoleClassify, ModelRoleAnswer, ModelRoleSummarize, ModelRoleEmbed}
+
+var (
+	modelRoleRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "llm_role_requests_total",
+		Help: "Model requests by role, model and result",
+	}, []string{"role", "model", "result"})
+	modelRoleDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "llm_role_request_duration_seconds",
+		Help:    "Duration of model requests by role and model",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"role", "model"})
+)
+
+// ModelEndpoint is the model and Ollama backend serving a role
+type ModelEndpoint struct {
+	Role    string `json:"role"`
+	Model   string `json:"model"`
+	Backend string `json:"backend"`
+}
+
+// ModelRoutes maps model roles to their endpoints
+type ModelRoutes map[string]ModelEndpoint
+
+// LoadModelRoutes reads MODEL_<ROLE> and MODEL_<ROLE>_BACKEND for every role.
+// The answer role defaults to GEMMA_MODEL on OLLAMA_URL, classify and
+// summarize default to the answer endpoint and embed defaults to
+// SEARCH_EMBEDDING_MODEL on the answer backend.
+func LoadModelRoutes() ModelRoutes {
+	answer := ModelEndpoint{
+		Role:    ModelRoleAnswer,
+		Model:   getEnv("MODEL_ANSWER", getEnv("GEMMA_MODEL", "gemma3n:e4b")),
+		Backend: getEnv("MODEL_ANSWER_BACKEND", getEnv("OLLAMA_URL", "http://192.168.0.3:11434")),
+	}
+
+	routes := ModelRoutes{ModelRoleAnswer: answer}
+	for _, role := range ModelRoles {
+		if role == ModelRoleAnswer {
+			continue
+		}
+		defaultModel := answer.Model
+		if role == ModelRoleEmbed {
+			defaultModel = getEnv("SEARCH_EMBEDDING_MODEL", "nomic-embed-text")
+		}
+		prefix := "MODEL_" + strings.ToUpper(role)
+		routes[role] = ModelEndpoint{
+			Role:    role,
+			Model:   getEnv(prefix, defaultModel),
+			Backend: getEnv(prefix+"_BACKEND", answer.Backend),
+		}
+	}
+	return routes
+}
+
+// Endpoint returns the endpoint of a role; unknown roles get the answer endpoint
+func (r ModelRoutes) Endpoint(role string) ModelEndpoint {
+	if endpoint, ok := r[role]; ok {
+		return endpoint
+	}
+	endpoint := r[ModelRoleAnswer]
+	endpoint.Role = role
+	return endpoint
+}
+
+// Endpoints returns the endpoints in role order
+func (r ModelRoutes) Endpoints() []ModelEndpoint {
+	endpoints := make([]ModelEndpoint, 0, len(r))
+	for _, endpoint := range r {
+		endpoints = append(endpoints, endpoint)
+	}
+	order := make(map[string]int, len(ModelRoles))
+	for i, role := range ModelRoles {
+		order[role] = i
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return order[endpoints[i].Role] < order[endpoints[j].Role] })
+	return endpoints
+}
+
+// logRoutes prints the endpoint of every role
+func (r ModelRoutes) logRoutes() {
+	for _, endpoint := range r.Endpoints() {
+		log.Printf("   🧭 Role %s: %s @ %s", endpoint.Role, endpoint.Model, endpoint.Backend)
+	}
+}
+
+// observeModelRequest records the result and duration of a model request
+func observeModelRequest(endpoint ModelEndpoint, startTime time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	modelRoleRequests.WithLabelValues(endpoint.Role, endpoint.Model, result).Inc()
+	modelRoleDuration.WithLabelValues(endpoint.Role, endpoint.Model).Observe(time.Since(startTime).Seconds())
+}
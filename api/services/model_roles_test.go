@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+)
+
+// TestLoadModelRoutesDefaults tests that every role falls back to the answer endpoint
+func TestLoadModelRoutesDefaults(t *testing.T) {
+	t.Setenv("GEMMA_MODEL", "gemma3n:e4b")
+	t.Setenv("OLLAMA_URL", "http://ollama:11434")
+	routes := LoadModelRoutes()
+
+	for _, role := range []string{ModelRoleClassify, ModelRoleAnswer, ModelRoleSummarize} {
+		endpoint := routes.Endpoint(role)
+		if endpoint.Role != role || endpoint.Model != "gemma3n:e4b" || endpoint.Backend != "http://ollama:11434" {
+			t.Errorf("Unexpected default endpoint for %s: %+v", role, endpoint)
+		}
+	}
+	if embed := routes.Endpoint(ModelRoleEmbed); embed.Model != "nomic-embed-text" || embed.Backend != "http://ollama:11434" {
+		t.Errorf("Expected the embedding model on the answer backend, got %+v", embed)
+	}
+	if len(routes.Endpoints()) != len(ModelRoles) || routes.Endpoints()[0].Role != ModelRoleClassify {
+		t.Errorf("Expected endpoints in role order, got %+v", routes.Endpoints())
+	}
+}
+
+// TestLoadModelRoutesOverrides tests per-role models and backends
+func TestLoadModelRoutesOverrides(t *testing.T) {
+	t.Setenv("GEMMA_MODEL", "gemma3n:e4b")
+	t.Setenv("MODEL_CLASSIFY", "gemma3:270m")
+	t.Setenv("MODEL_SUMMARIZE_BACKEND", "http://gpu:11434")
+	routes := LoadModelRoutes()
+
+	if classify := routes.Endpoint(ModelRoleClassify); classify.Model != "gemma3:270m" {
+		t.Errorf("Expected the classify override, got %+v", classify)
+	}
+	if summarize := routes.Endpoint(ModelRoleSummarize); summarize.Model != "gemma3n:e4b" || summarize.Backend != "http://gpu:11434" {
+		t.Errorf("Expected the summarize backend override, got %+v", summarize)
+	}
+	if unknown := routes.Endpoint("rerank"); unknown.Role != "rerank" || unknown.Model != "gemma3n:e4b" {
+		t.Errorf("Expected unknown roles to use the answer model, got %+v", unknown)
+	}
+}
+
+// TestLLMServiceEndpoint tests that the answer role follows the service model
+func TestLLMServiceEndpoint(t *testing.T) {
+	t.Setenv("MODEL_SUMMARIZE", "qwen2.5:1.5b")
+	service := NewLLMService(nil, nil)
+	service.model = "candidate"
+
+	if answer := service.endpoint(ModelRoleAnswer); answer.Model != "candidate" {
+		t.Errorf("Expected the answer role to use the service model, got %+v", answer)
+	}
+	if summarize := service.endpoint(ModelRoleSummarize); summarize.Model != "qwen2.5:1.5b" {
+		t.Errorf("Expected the summarize route, got %+v", summarize)
+	}
+
+	bare := &LLMService{model: "test-model", ollamaURL: "http://127.0.0.1:0"}
+	if classify := bare.endpoint(ModelRoleClassify); classify.Model != "test-model" || classify.Role != ModelRoleClassify {
+		t.Errorf("Expected services without routes to use their own model, got %+v", classify)
+	}
+
+	for _, endpoint := range service.ModelRoutes() {
+		if endpoint.Role == ModelRoleAnswer && endpoint.Model != "candidate" {
+			t.Errorf("Expected ModelRoutes to report the service model, got %+v", endpoint)
+		}
+	}
+}
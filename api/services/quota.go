@@ -0,0 +1,402 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Quota names reported in headers and errors
+const (
+	QuotaMessages = "messages"
+	QuotaTokens   = "tokens"
+	QuotaBlocked  = "blocked"
+)
+
+// Quota scopes; every limit applies per session and per client IP
+const (
+	quotaScopeSession = "session"
+	quotaScopeIP      = "ip"
+)
+
+// slidingWindowAddScript drops entries older than the window from every key,
+// then adds the new entry to all of them only when none is at its limit
+// (ARGV[3 + i] for KEYS[i]), so a rejected entry is never counted anywhere.
+// It returns the index of the first full key (0 when the entry was added),
+// followed by the entries in the window and the score of the oldest entry of
+// each key.
+var slidingWindowAddScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local member = ARGV[3]
+local counts = {}
+local oldest = {}
+local rejected = 0
+for i, key in ipairs(KEYS) do
+  redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+  counts[i] = redis.call('ZCARD', key)
+  local first = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+  oldest[i] = now
+  if first[2] then oldest[i] = tonumber(first[2]) end
+  if rejected == 0 and counts[i] >= tonumber(ARGV[3 + i]) then rejected = i end
+end
+local result = {rejected}
+for i, key in ipairs(KEYS) do
+  if rejected == 0 then
+    redis.call('ZADD', key, now, member)
+    redis.call('PEXPIRE', key, window)
+    counts[i] = counts[i] + 1
+  end
+  table.insert(result, counts[i])
+  table.insert(result, oldest[i])
+end
+return result
+`)
+
+// slidingWindowSumScript drops entries older than the window and sums the
+// amounts stored after the last ':' of each member. It returns the sum and the
+// score of the oldest entry.
+var slidingWindowSumScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local entries = redis.call('ZRANGE', key, 0, -1, 'WITHSCORES')
+local sum = 0
+local oldestScore = now
+for i = 1, #entries, 2 do
+  sum = sum + tonumber(string.match(entries[i], ':(%d+)$') or '0')
+  if i == 1 then oldestScore = tonumber(entries[i + 1]) end
+end
+return {sum, oldestScore}
+`)
+
+// QuotaLimits holds the chat quotas; a zero limit disables that quota
+type QuotaLimits struct {
+	MessagesPerHourSession int
+	MessagesPerHourIP      int
+	TokensPerDaySession    int
+	TokensPerDayIP         int
+	GuardrailStrikes       int
+	StrikeWindow           time.Duration
+	BlockDuration          time.Duration
+}
+
+// QuotaSubject identifies who a chat message is counted against
+type QuotaSubject struct {
+	SessionID string
+	IP        string
+}
+
+// QuotaUsage is the state of one quota, for the most constrained scope
+type QuotaUsage struct {
+	Limit     int           `json:"limit"`
+	Remaining int           `json:"remaining"`
+	Reset     time.Duration `json:"-"`
+	// ResetSeconds is Reset rounded up, when the window frees up again
+	ResetSeconds int `json:"reset_seconds"`
+}
+
+// QuotaStatus is the quota state after a chat message was admitted or rejected
+type QuotaStatus struct {
+	Messages *QuotaUsage `json:"messages,omitempty"`
+	Tokens   *QuotaUsage `json:"tokens,omitempty"`
+}
+
+// QuotaExceededError rejects a chat message over quota or from a blocked client
+type QuotaExceededError struct {
+	Quota      string
+	Scope      string
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota %s exceeded for %s: %s", e.Quota, e.Scope, e.Message)
+}
+
+// QuotaTracker enforces per-session and per-IP chat quotas with sliding
+// windows in Redis, and blocks clients that keep tripping guardrails
+type QuotaTracker struct {
+	redis    *redis.Client
+	redactor *Redactor
+	limits   QuotaLimits
+	now      func() time.Time
+}
+
+// NewQuotaTracker creates a tracker with limits from the environment
+func NewQuotaTracker(rdb *redis.Client, redactor *Redactor) *QuotaTracker {
+	limits := QuotaLimits{
+		MessagesPerHourSession: envInt("CHAT_QUOTA_MESSAGES_PER_HOUR_SESSION", 30),
+		MessagesPerHourIP:      envInt("CHAT_QUOTA_MESSAGES_PER_HOUR_IP", 60),
+		TokensPerDaySession:    envInt("CHAT_QUOTA_TOKENS_PER_DAY_SESSION", 100000),
+		TokensPerDayIP:         envInt("CHAT_QUOTA_TOKENS_PER_DAY_IP", 250000),
+		GuardrailStrikes:       envInt("CHAT_GUARDRAIL_STRIKES", 3),
+		StrikeWindow:           time.Duration(envInt("CHAT_GUARDRAIL_STRIKE_WINDOW_MINUTES", 10)) * time.Minute,
+		BlockDuration:          time.Duration(envInt("CHAT_BLOCK_MINUTES", 30)) * time.Minute,
+	}
+
+	log.Printf("🚦 Chat quotas: %d/%d messages per hour, %d/%d tokens per day (session/IP), block after %d guardrail trips",
+		limits.MessagesPerHourSession, limits.MessagesPerHourIP, limits.TokensPerDaySession, limits.TokensPerDayIP, limits.GuardrailStrikes)
+
+	return &QuotaTracker{redis: rdb, redactor: redactor, limits: limits, now: time.Now}
+}
+
+// Limits returns the configured quotas
+func (q *QuotaTracker) Limits() QuotaLimits {
+	return q.limits
+}
+
+// Admit checks blocks and the daily token budgets, then counts the message
+// against the hourly message quotas. A *QuotaExceededError means the message
+// must be rejected; the status is returned either way.
+func (q *QuotaTracker) Admit(ctx context.Context, subject QuotaSubject) (*QuotaStatus, error) {
+	if q.redis == nil {
+		return nil, fmt.Errorf("redis connection not available")
+	}
+
+	now := q.now()
+	status := &QuotaStatus{}
+
+	for _, scope := range subject.scopes() {
+		ttl, err := q.redis.PTTL(ctx, blockKey(scope.name, scope.id)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ttl > 0 {
+			return status, &QuotaExceededError{
+				Quota:      QuotaBlocked,
+				Scope:      scope.name,
+				RetryAfter: ttl,
+				Message:    "Too many rejected messages, please try again later",
+			}
+		}
+	}
+
+	for _, scope := range subject.scopes() {
+		limit := q.limits.tokensPerDay(scope.name)
+		if limit <= 0 {
+			continue
+		}
+		used, oldest, err := q.windowSum(ctx, tokensKey(scope.name, scope.id), now, 24*time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		usage := newQuotaUsage(limit, limit-used, oldest.Add(24*time.Hour).Sub(now))
+		status.Tokens = tighterUsage(status.Tokens, usage)
+		if used >= limit {
+			return status, &QuotaExceededError{
+				Quota:      QuotaTokens,
+				Scope:      scope.name,
+				RetryAfter: usage.Reset,
+				Message:    "Daily chat limit reached, please come back tomorrow",
+			}
+		}
+	}
+
+	// Every message window is checked and counted in one script, so a message
+	// rejected by one scope does not use up the quota of another
+	var windows []slidingWindow
+	for _, scope := range subject.scopes() {
+		if limit := q.limits.messagesPerHour(scope.name); limit > 0 {
+			windows = append(windows, slidingWindow{scope: scope, key: messagesKey(scope.name, scope.id), limit: limit})
+		}
+	}
+	if len(windows) == 0 {
+		return status, nil
+	}
+
+	rejected, err := q.windowAdd(ctx, windows, now, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	for _, window := range windows {
+		status.Messages = tighterUsage(status.Messages, window.usage)
+	}
+	if rejected != nil {
+		return status, &QuotaExceededError{
+			Quota:      QuotaMessages,
+			Scope:      rejected.scope.name,
+			RetryAfter: rejected.usage.Reset,
+			Message:    "Too many messages, please slow down",
+		}
+	}
+
+	return status, nil
+}
+
+// RecordTokens counts model tokens against the daily budgets
+func (q *QuotaTracker) RecordTokens(ctx context.Context, subject QuotaSubject, tokens int) error {
+	if q.redis == nil {
+		return fmt.Errorf("redis connection not available")
+	}
+	if tokens <= 0 {
+		return nil
+	}
+
+	now := q.now()
+	pipe := q.redis.TxPipeline()
+	for _, scope := range subject.scopes() {
+		if q.limits.tokensPerDay(scope.name) <= 0 {
+			continue
+		}
+		key := tokensKey(scope.name, scope.id)
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: uniqueMember(now) + ":" + strconv.Itoa(tokens)})
+		pipe.Expire(ctx, key, 24*time.Hour)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RecordGuardrailTrip counts a rejected message and blocks the session or IP
+// once it reaches the strike limit within the strike window. It reports
+// whether a block was started.
+func (q *QuotaTracker) RecordGuardrailTrip(ctx context.Context, subject QuotaSubject) (bool, error) {
+	if q.redis == nil {
+		return false, fmt.Errorf("redis connection not available")
+	}
+	if q.limits.GuardrailStrikes <= 0 {
+		return false, nil
+	}
+
+	now := q.now()
+	blocked := false
+	for _, scope := range subject.scopes() {
+		key := strikesKey(scope.name, scope.id)
+		pipe := q.redis.TxPipeline()
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-q.limits.StrikeWindow).UnixMilli(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: uniqueMember(now)})
+		strikes := pipe.ZCard(ctx, key)
+		pipe.Expire(ctx, key, q.limits.StrikeWindow)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return blocked, err
+		}
+		if count := int(strikes.Val()); count >= q.limits.GuardrailStrikes {
+			if err := q.redis.Set(ctx, blockKey(scope.name, scope.id), now.UTC().Format(time.RFC3339), q.limits.BlockDuration).Err(); err != nil {
+				return blocked, err
+			}
+			q.redis.Del(ctx, key)
+			log.Printf("🚫 Chat %s %s blocked for %v after %d guardrail trips", scope.name, q.redactor.Log(scope.id), q.limits.BlockDuration, count)
+			blocked = true
+		}
+	}
+	return blocked, nil
+}
+
+// slidingWindow is one scope's window checked by windowAdd
+type slidingWindow struct {
+	scope quotaScope
+	key   string
+	limit int
+	usage *QuotaUsage
+}
+
+// windowAdd adds an entry to every window unless one of them is full, which
+// it returns. The usage of each window is filled in either way.
+func (q *QuotaTracker) windowAdd(ctx context.Context, windows []slidingWindow, now time.Time, window time.Duration) (*slidingWindow, error) {
+	keys := make([]string, len(windows))
+	args := []interface{}{now.UnixMilli(), window.Milliseconds(), uniqueMember(now)}
+	for i, w := range windows {
+		keys[i] = w.key
+		args = append(args, w.limit)
+	}
+
+	result, err := slidingWindowAddScript.Run(ctx, q.redis, keys, args...).Int64Slice()
+	if err != nil || len(result) != 1+2*len(windows) {
+		return nil, fmt.Errorf("sliding window %v: %v", keys, err)
+	}
+
+	for i := range windows {
+		count, oldest := int(result[1+2*i]), time.UnixMilli(result[2+2*i])
+		windows[i].usage = newQuotaUsage(windows[i].limit, windows[i].limit-count, oldest.Add(window).Sub(now))
+	}
+	if rejected := int(result[0]); rejected > 0 {
+		return &windows[rejected-1], nil
+	}
+	return nil, nil
+}
+
+// windowSum sums the amounts in a sliding window
+func (q *QuotaTracker) windowSum(ctx context.Context, key string, now time.Time, window time.Duration) (int, time.Time, error) {
+	result, err := slidingWindowSumScript.Run(ctx, q.redis, []string{key}, now.UnixMilli(), window.Milliseconds()).Int64Slice()
+	if err != nil || len(result) != 2 {
+		return 0, now, fmt.Errorf("sliding window %s: %v", key, err)
+	}
+	return int(result[0]), time.UnixMilli(result[1]), nil
+}
+
+type quotaScope struct{ name, id string }
+
+// scopes returns the scopes a subject is counted in; messages without a
+// session yet only count per IP
+func (s QuotaSubject) scopes() []quotaScope {
+	var scopes []quotaScope
+	if s.SessionID != "" {
+		scopes = append(scopes, quotaScope{quotaScopeSession, s.SessionID})
+	}
+	if s.IP != "" {
+		scopes = append(scopes, quotaScope{quotaScopeIP, s.IP})
+	}
+	return scopes
+}
+
+func (l QuotaLimits) messagesPerHour(scope string) int {
+	if scope == quotaScopeSession {
+		return l.MessagesPerHourSession
+	}
+	return l.MessagesPerHourIP
+}
+
+func (l QuotaLimits) tokensPerDay(scope string) int {
+	if scope == quotaScopeSession {
+		return l.TokensPerDaySession
+	}
+	return l.TokensPerDayIP
+}
+
+// newQuotaUsage clamps the remaining amount and reset time at zero
+func newQuotaUsage(limit, remaining int, reset time.Duration) *QuotaUsage {
+	reset = max(reset, 0)
+	return &QuotaUsage{
+		Limit:        limit,
+		Remaining:    max(remaining, 0),
+		Reset:        reset,
+		ResetSeconds: int(math.Ceil(reset.Seconds())),
+	}
+}
+
+// tighterUsage returns the usage with less remaining
+func tighterUsage(current, candidate *QuotaUsage) *QuotaUsage {
+	if current == nil || candidate.Remaining < current.Remaining {
+		return candidate
+	}
+	return current
+}
+
+// uniqueMember makes sorted set members unique for entries in the same millisecond
+func uniqueMember(now time.Time) string {
+	bytes := make([]byte, 4)
+	_, _ = rand.Read(bytes)
+	return strconv.FormatInt(now.UnixNano(), 10) + "-" + hex.EncodeToString(bytes)
+}
+
+// envInt reads a non-negative integer setting
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func messagesKey(scope, id string) string { return "chat:quota:" + scope + ":" + id + ":messages" }
+func tokensKey(scope, id string) string   { return "chat:quota:" + scope + ":" + id + ":tokens" }
+func strikesKey(scope, id string) string  { return "chat:quota:" + scope + ":" + id + ":strikes" }
+func blockKey(scope, id string) string    { return "chat:quota:" + scope + ":" + id + ":blocked" }
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestQuotaTrackerWithoutRedis(t *testing.T) {
+	tracker := NewQuotaTracker(nil, nil)
+	subject := QuotaSubject{SessionID: "session_1", IP: "203.0.113.7"}
+
+	if _, err := tracker.Admit(context.Background(), subject); err == nil {
+		t.Error("Expected error when redis is not available")
+	}
+	if err := tracker.RecordTokens(context.Background(), subject, 10); err == nil {
+		t.Error("Expected error when redis is not available")
+	}
+	if _, err := tracker.RecordGuardrailTrip(context.Background(), subject); err == nil {
+		t.Error("Expected error when redis is not available")
+	}
+}
+
+func TestQuotaLimitsFromEnv(t *testing.T) {
+	t.Setenv("CHAT_QUOTA_MESSAGES_PER_HOUR_SESSION", "5")
+	t.Setenv("CHAT_QUOTA_TOKENS_PER_DAY_IP", "0")
+	t.Setenv("CHAT_BLOCK_MINUTES", "-1")
+
+	limits := NewQuotaTracker(nil, nil).Limits()
+	if limits.MessagesPerHourSession != 5 {
+		t.Errorf("MessagesPerHourSession = %d, want 5", limits.MessagesPerHourSession)
+	}
+	if limits.TokensPerDayIP != 0 {
+		t.Errorf("TokensPerDayIP = %d, want 0 (disabled)", limits.TokensPerDayIP)
+	}
+	if limits.BlockDuration != 30*time.Minute {
+		t.Errorf("BlockDuration = %v, want the 30m default for invalid values", limits.BlockDuration)
+	}
+}
+
+func TestQuotaSubjectScopes(t *testing.T) {
+	if scopes := (QuotaSubject{IP: "203.0.113.7"}).scopes(); len(scopes) != 1 || scopes[0].name != quotaScopeIP {
+		t.Errorf("Expected a message without a session to count per IP only, got %v", scopes)
+	}
+	if scopes := (QuotaSubject{SessionID: "session_1", IP: "203.0.113.7"}).scopes(); len(scopes) != 2 {
+		t.Errorf("Expected session and IP scopes, got %v", scopes)
+	}
+}
+
+func TestTighterQuotaUsage(t *testing.T) {
+	session := newQuotaUsage(30, 12, 1500*time.Millisecond)
+	if session.ResetSeconds != 2 {
+		t.Errorf("ResetSeconds = %d, want 2 (rounded up)", session.ResetSeconds)
+	}
+
+	ip := newQuotaUsage(60, -3, -time.Second)
+	if ip.Remaining != 0 || ip.Reset != 0 {
+		t.Errorf("Expected remaining and reset to be clamped at 0, got %+v", ip)
+	}
+
+	if got := tighterUsage(tighterUsage(nil, session), ip); got != ip {
+		t.Errorf("Expected the usage with less remaining, got %+v", got)
+	}
+}
+
+// newTestQuotaTracker returns a tracker on miniredis with a clock the test moves
+func newTestQuotaTracker(t *testing.T, limits QuotaLimits) (*QuotaTracker, *miniredis.Miniredis, *time.Time) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	clock := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tracker := &QuotaTracker{redis: rdb, limits: limits, now: func() time.Time { return clock }}
+	return tracker, server, &clock
+}
+
+func admitExceeded(t *testing.T, tracker *QuotaTracker, subject QuotaSubject) (*QuotaStatus, *QuotaExceededError) {
+	t.Helper()
+	status, err := tracker.Admit(context.Background(), subject)
+	var exceeded *QuotaExceededError
+	if err != nil && !errors.As(err, &exceeded) {
+		t.Fatalf("Admit failed: %v", err)
+	}
+	return status, exceeded
+}
+
+func TestQuotaAdmitMessageWindow(t *testing.T) {
+	tracker, server, clock := newTestQuotaTracker(t, QuotaLimits{MessagesPerHourSession: 2, MessagesPerHourIP: 10})
+	subject := QuotaSubject{SessionID: "sess_1", IP: "203.0.113.7"}
+	start := *clock
+
+	status, exceeded := admitExceeded(t, tracker, subject)
+	if exceeded != nil {
+		t.Fatalf("Expected the first message to be admitted, got %v", exceeded)
+	}
+	if status.Messages.Limit != 2 || status.Messages.Remaining != 1 {
+		t.Errorf("Expected the session quota to be reported with 1 remaining, got %+v", status.Messages)
+	}
+
+	*clock = start.Add(10 * time.Minute)
+	if _, exceeded := admitExceeded(t, tracker, subject); exceeded != nil {
+		t.Fatalf("Expected the second message to be admitted, got %v", exceeded)
+	}
+
+	*clock = start.Add(20 * time.Minute)
+	status, exceeded = admitExceeded(t, tracker, subject)
+	if exceeded == nil || exceeded.Quota != QuotaMessages || exceeded.Scope != quotaScopeSession {
+		t.Fatalf("Expected the session message quota to reject the third message, got %v", exceeded)
+	}
+	if exceeded.RetryAfter != 40*time.Minute {
+		t.Errorf("RetryAfter = %v, want 40m until the first message leaves the window", exceeded.RetryAfter)
+	}
+	if status.Messages.Remaining != 0 {
+		t.Errorf("Expected no messages remaining, got %+v", status.Messages)
+	}
+	if members, _ := server.ZMembers(messagesKey(quotaScopeIP, subject.IP)); len(members) != 2 {
+		t.Errorf("Expected the rejected message not to count per IP, got %d entries", len(members))
+	}
+
+	*clock = start.Add(time.Hour + time.Millisecond)
+	if _, exceeded := admitExceeded(t, tracker, subject); exceeded != nil {
+		t.Errorf("Expected a message once the window reset, got %v", exceeded)
+	}
+}
+
+func TestQuotaAdmitRejectedByIPDoesNotCountSession(t *testing.T) {
+	tracker, server, _ := newTestQuotaTracker(t, QuotaLimits{MessagesPerHourSession: 5, MessagesPerHourIP: 1})
+
+	if _, exceeded := admitExceeded(t, tracker, QuotaSubject{SessionID: "sess_1", IP: "203.0.113.7"}); exceeded != nil {
+		t.Fatalf("Expected the first message to be admitted, got %v", exceeded)
+	}
+
+	_, exceeded := admitExceeded(t, tracker, QuotaSubject{SessionID: "sess_2", IP: "203.0.113.7"})
+	if exceeded == nil || exceeded.Scope != quotaScopeIP {
+		t.Fatalf("Expected the IP quota to reject the message, got %v", exceeded)
+	}
+	if server.Exists(messagesKey(quotaScopeSession, "sess_2")) {
+		t.Error("Expected a message rejected per IP not to count against its session")
+	}
+}
+
+func TestQuotaTokenBudget(t *testing.T) {
+	tracker, _, clock := newTestQuotaTracker(t, QuotaLimits{TokensPerDaySession: 100})
+	subject := QuotaSubject{SessionID: "sess_1", IP: "203.0.113.7"}
+	start := *clock
+	ctx := context.Background()
+
+	if err := tracker.RecordTokens(ctx, subject, 60); err != nil {
+		t.Fatalf("RecordTokens failed: %v", err)
+	}
+	status, exceeded := admitExceeded(t, tracker, subject)
+	if exceeded != nil {
+		t.Fatalf("Expected a message under the token budget, got %v", exceeded)
+	}
+	if status.Tokens.Remaining != 40 {
+		t.Errorf("Expected 40 tokens remaining, got %+v", status.Tokens)
+	}
+
+	*clock = start.Add(time.Hour)
+	if err := tracker.RecordTokens(ctx, subject, 50); err != nil {
+		t.Fatalf("RecordTokens failed: %v", err)
+	}
+	_, exceeded = admitExceeded(t, tracker, subject)
+	if exceeded == nil || exceeded.Quota != QuotaTokens {
+		t.Fatalf("Expected the token budget to reject the message, got %v", exceeded)
+	}
+	if exceeded.RetryAfter != 23*time.Hour {
+		t.Errorf("RetryAfter = %v, want 23h until the first tokens leave the window", exceeded.RetryAfter)
+	}
+
+	*clock = start.Add(24*time.Hour + time.Millisecond)
+	status, exceeded = admitExceeded(t, tracker, subject)
+	if exceeded != nil {
+		t.Fatalf("Expected a message once the first tokens left the window, got %v", exceeded)
+	}
+	if status.Tokens.Remaining != 50 {
+		t.Errorf("Expected 50 tokens remaining, got %+v", status.Tokens)
+	}
+}
+
+func TestQuotaGuardrailBlock(t *testing.T) {
+	tracker, server, clock := newTestQuotaTracker(t, QuotaLimits{
+		GuardrailStrikes: 3,
+		StrikeWindow:     10 * time.Minute,
+		BlockDuration:    30 * time.Minute,
+	})
+	subject := QuotaSubject{IP: "203.0.113.7"}
+	ctx := context.Background()
+
+	// A strike older than the window does not count
+	if blocked, err := tracker.RecordGuardrailTrip(ctx, subject); err != nil || blocked {
+		t.Fatalf("Expected the first trip not to block, got %v, %v", blocked, err)
+	}
+	*clock = clock.Add(11 * time.Minute)
+	for i := 0; i < 2; i++ {
+		if blocked, err := tracker.RecordGuardrailTrip(ctx, subject); err != nil || blocked {
+			t.Fatalf("Expected trip %d within the window not to block, got %v, %v", i+1, blocked, err)
+		}
+	}
+
+	blocked, err := tracker.RecordGuardrailTrip(ctx, subject)
+	if err != nil || !blocked {
+		t.Fatalf("Expected the third trip within the window to block, got %v, %v", blocked, err)
+	}
+
+	_, exceeded := admitExceeded(t, tracker, subject)
+	if exceeded == nil || exceeded.Quota != QuotaBlocked {
+		t.Fatalf("Expected a blocked client to be rejected, got %v", exceeded)
+	}
+	if exceeded.RetryAfter != 30*time.Minute {
+		t.Errorf("RetryAfter = %v, want the 30m block", exceeded.RetryAfter)
+	}
+
+	server.FastForward(30*time.Minute + time.Second)
+	if _, exceeded := admitExceeded(t, tracker, subject); exceeded != nil {
+		t.Errorf("Expected the block to expire, got %v", exceeded)
+	}
+}
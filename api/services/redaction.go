@@ -0,0 +1,286 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redaction sinks; each is configured with REDACT_<SINK>
+const (
+	RedactSinkLogs        = "logs"
+	RedactSinkTranscripts = "transcripts"
+	RedactSinkShadow      = "shadow"
+)
+
+// Built-in PII categories
+const (
+	PIIEmail = "email"
+	PIIPhone = "phone"
+	PIIIP    = "ip"
+)
+
+var redactionSinks = []string{RedactSinkLogs, RedactSinkTranscripts, RedactSinkShadow}
+
+// builtinRedactionRules run in this order, so IP addresses are masked before
+// their digits can be taken for a phone number
+var builtinRedactionRules = []redactionRule{
+	{category: PIIEmail, pattern: regexp.MustCompile(`(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`)},
+	{category: PIIIP, pattern: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), validate: isIPAddress},
+	{category: PIIIP, pattern: regexp.MustCompile(`(?i)\b[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}\b`), validate: isIPAddress},
+	{category: PIIPhone, pattern: regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{1,4}\)[\s.-]?)?\d[\d\s.-]{6,}\d`), validate: isPhoneNumber},
+}
+
+// redactionRule masks the matches of a pattern as [category]
+type redactionRule struct {
+	category string
+	pattern  *regexp.Regexp
+	validate func(match string) bool // nil accepts every match
+}
+
+// Redactor masks emails, phone numbers, IP addresses and configured patterns
+// before text reaches a sink. The owner's public contact details are never
+// masked.
+type Redactor struct {
+	cb              *ContextBuilder
+	rules           []redactionRule
+	sinks           map[string]map[string]bool
+	staticAllowlist []string
+	refreshInterval time.Duration
+
+	mu        sync.RWMutex
+	allowlist map[string]bool
+	loadedAt  time.Time
+}
+
+// NewRedactor creates a redactor configured from the environment:
+//   - REDACT_PATTERNS: JSON object of extra categories, e.g. {"iban": "\\bDE\\d{20}\\b"}
+//   - REDACT_LOGS, REDACT_TRANSCRIPTS, REDACT_SHADOW: "all" (default), "none" or a
+//     comma-separated list of categories
+//   - REDACT_ALLOWLIST: comma-separated values never masked, in addition to the
+//     contact email of the portfolio
+func NewRedactor(cb *ContextBuilder) *Redactor {
+	r := &Redactor{
+		cb:              cb,
+		rules:           append([]redactionRule{}, builtinRedactionRules...),
+		sinks:           make(map[string]map[string]bool),
+		refreshInterval: 10 * time.Minute,
+	}
+
+	if raw := getEnv("REDACT_PATTERNS", ""); raw != "" {
+		var patterns map[string]string
+		if err := json.Unmarshal([]byte(raw), &patterns); err != nil {
+			log.Printf("⚠️ Ignoring REDACT_PATTERNS, expected a JSON object of name to regex: %v", err)
+		}
+		names := make([]string, 0, len(patterns))
+		for name := range patterns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			category := strings.ToLower(strings.TrimSpace(name))
+			if r.hasCategory(category) || category == "all" || category == "none" {
+				log.Printf("⚠️ Ignoring redaction pattern %q, the name is reserved", name)
+				continue
+			}
+			pattern, err := regexp.Compile(patterns[name])
+			if err != nil {
+				log.Printf("⚠️ Ignoring redaction pattern %q: %v", name, err)
+				continue
+			}
+			r.rules = append(r.rules, redactionRule{category: category, pattern: pattern})
+		}
+	}
+
+	for _, sink := range redactionSinks {
+		r.sinks[sink] = r.parseCategories(sink, getEnv("REDACT_"+strings.ToUpper(sink), "all"))
+	}
+
+	for _, value := range strings.Split(getEnv("REDACT_ALLOWLIST", ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			r.staticAllowlist = append(r.staticAllowlist, value)
+		}
+	}
+	r.setAllowlist(nil)
+
+	return r
+}
+
+// Redact masks the PII categories enabled for a sink. A nil redactor returns
+// the text unchanged.
+func (r *Redactor) Redact(sink, text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	categories := r.sinks[sink]
+	if len(categories) == 0 {
+		return text
+	}
+
+	r.refreshIfStale()
+	r.mu.RLock()
+	allowlist := r.allowlist
+	r.mu.RUnlock()
+
+	for _, rule := range r.rules {
+		if !categories[rule.category] {
+			continue
+		}
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if (rule.validate != nil && !rule.validate(match)) || allowlisted(allowlist, match) {
+				return match
+			}
+			return "[" + rule.category + "]"
+		})
+	}
+	return text
+}
+
+// Log masks text before it is written to the logs
+func (r *Redactor) Log(text string) string {
+	return r.Redact(RedactSinkLogs, text)
+}
+
+// RedactMessages returns copies of session messages with their content masked
+func (r *Redactor) RedactMessages(sink string, messages []SessionMessage) []SessionMessage {
+	if r == nil || len(r.sinks[sink]) == 0 {
+		return messages
+	}
+	redacted := make([]SessionMessage, len(messages))
+	for i, message := range messages {
+		message.Content = r.Redact(sink, message.Content)
+		redacted[i] = message
+	}
+	return redacted
+}
+
+// Categories returns the categories masked in a sink
+func (r *Redactor) Categories(sink string) []string {
+	if r == nil {
+		return nil
+	}
+	return sortedKeys(r.sinks[sink])
+}
+
+// parseCategories reads the categories configured for a sink
+func (r *Redactor) parseCategories(sink, value string) map[string]bool {
+	categories := make(map[string]bool)
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "none", "off", "":
+		return categories
+	case "all":
+		for _, rule := range r.rules {
+			categories[rule.category] = true
+		}
+		return categories
+	}
+
+	for _, category := range strings.Split(value, ",") {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if !r.hasCategory(category) {
+			log.Printf("⚠️ Unknown redaction category %q for sink %s", category, sink)
+			continue
+		}
+		categories[category] = true
+	}
+	return categories
+}
+
+func (r *Redactor) hasCategory(category string) bool {
+	for _, rule := range r.rules {
+		if rule.category == category {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshIfStale reloads the portfolio contact email when it is older than the refresh interval
+func (r *Redactor) refreshIfStale() {
+	if r.cb == nil || r.cb.db == nil {
+		return
+	}
+
+	r.mu.RLock()
+	stale := time.Since(r.loadedAt) >= r.refreshInterval
+	r.mu.RUnlock()
+	if !stale {
+		return
+	}
+
+	contact, err := r.cb.GetContact()
+	if err != nil {
+		log.Printf("⚠️ Redaction allowlist refresh failed, keeping current entries: %v", err)
+		r.mu.Lock()
+		r.loadedAt = time.Now()
+		r.mu.Unlock()
+		return
+	}
+	r.setAllowlist([]string{contact.Email})
+
+	r.mu.Lock()
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+}
+
+// setAllowlist combines the configured allowlist with the public contact details
+func (r *Redactor) setAllowlist(contact []string) {
+	allowlist := make(map[string]bool)
+	for _, value := range append(append([]string{}, r.staticAllowlist...), contact...) {
+		for _, key := range allowlistKeys(value) {
+			allowlist[key] = true
+		}
+	}
+
+	r.mu.Lock()
+	r.allowlist = allowlist
+	r.mu.Unlock()
+}
+
+// allowlisted reports whether a match is one of the allowlisted values
+func allowlisted(allowlist map[string]bool, match string) bool {
+	for _, key := range allowlistKeys(match) {
+		if allowlist[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// allowlistKeys normalizes a value for comparison: lowercased, and for
+// phone-like values also as bare digits, so "+55 11 9999-0000" matches "5511999990000"
+func allowlistKeys(value string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return nil
+	}
+	keys := []string{value}
+	if digits := onlyDigits(value); len(digits) >= 7 && !strings.ContainsAny(value, "@") {
+		keys = append(keys, "tel:"+digits)
+	}
+	return keys
+}
+
+func onlyDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+func isIPAddress(match string) bool {
+	return net.ParseIP(match) != nil
+}
+
+// isPhoneNumber rejects short numbers such as dates and year ranges
+func isPhoneNumber(match string) bool {
+	digits := len(onlyDigits(match))
+	return digits >= 9 && digits <= 15
+}
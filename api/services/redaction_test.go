@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+)
+
+// TestRedactBuiltinCategories tests masking of emails, phone numbers and IP addresses
+func TestRedactBuiltinCategories(t *testing.T) {
+	redactor := NewRedactor(nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"email", "Write to jane.doe+cv@example.com please", "Write to [email] please"},
+		{"international phone", "Call me at +55 11 98765-4321", "Call me at [phone]"},
+		{"local phone", "My number is (415) 555-0134.", "My number is [phone]."},
+		{"ipv4", "Connected from 203.0.113.42", "Connected from [ip]"},
+		{"ipv6", "Connected from 2001:db8::8a2e:370:7334", "Connected from [ip]"},
+		{"year range", "Worked there from 2019-2023", "Worked there from 2019-2023"},
+		{"version", "Running Go 1.22.4 on k8s v1.30", "Running Go 1.22.4 on k8s v1.30"},
+		{"time", "Meet at 10:30", "Meet at 10:30"},
+		{"short number", "Order 12345 shipped", "Order 12345 shipped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactor.Redact(RedactSinkLogs, tt.input); got != tt.expected {
+				t.Errorf("Redact(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestRedactAllowlist tests that allowlisted contact details are kept
+func TestRedactAllowlist(t *testing.T) {
+	t.Setenv("REDACT_ALLOWLIST", "bruno@example.com, +55 11 4000-1234")
+	redactor := NewRedactor(nil)
+
+	got := redactor.Redact(RedactSinkTranscripts, "Email Bruno@example.com or me@example.org, call 5511 40001234 or 5511 40009999")
+	expected := "Email Bruno@example.com or [email], call 5511 40001234 or [phone]"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+// TestRedactPerSink tests per-sink category configuration
+func TestRedactPerSink(t *testing.T) {
+	t.Setenv("REDACT_LOGS", "ip")
+	t.Setenv("REDACT_TRANSCRIPTS", "none")
+	t.Setenv("REDACT_SHADOW", "email, unknown")
+	redactor := NewRedactor(nil)
+
+	input := "me@example.org from 203.0.113.42"
+	if got := redactor.Log(input); got != "me@example.org from [ip]" {
+		t.Errorf("Expected only the IP masked in logs, got %q", got)
+	}
+	if got := redactor.Redact(RedactSinkTranscripts, input); got != input {
+		t.Errorf("Expected transcripts unmasked, got %q", got)
+	}
+	if got := redactor.Redact(RedactSinkShadow, input); got != "[email] from 203.0.113.42" {
+		t.Errorf("Expected only the email masked in shadow comparisons, got %q", got)
+	}
+	if categories := redactor.Categories(RedactSinkShadow); len(categories) != 1 || categories[0] != PIIEmail {
+		t.Errorf("Expected unknown categories to be ignored, got %v", categories)
+	}
+
+	messages := []SessionMessage{{Role: "user", Content: input}}
+	if redacted := redactor.RedactMessages(RedactSinkLogs, messages); redacted[0].Content != "me@example.org from [ip]" || messages[0].Content != input {
+		t.Errorf("Expected masked copies of messages, got %+v (original %+v)", redacted, messages)
+	}
+}
+
+// TestRedactCustomPatterns tests patterns configured with REDACT_PATTERNS
+func TestRedactCustomPatterns(t *testing.T) {
+	t.Setenv("REDACT_PATTERNS", `{"iban": "\\bDE\\d{20}\\b", "email": "ignored", "broken": "("}`)
+	redactor := NewRedactor(nil)
+
+	got := redactor.Log("Pay to DE89370400440532013000, ask me@example.org")
+	if got != "Pay to [iban], ask [email]" {
+		t.Errorf("Unexpected redaction: %q", got)
+	}
+	if redactor.hasCategory("broken") {
+		t.Error("Expected invalid patterns to be skipped")
+	}
+}
+
+// TestNilRedactor tests that a nil redactor leaves text unchanged
+func TestNilRedactor(t *testing.T) {
+	var redactor *Redactor
+	if got := redactor.Log("me@example.org"); got != "me@example.org" {
+		t.Errorf("Expected unchanged text, got %q", got)
+	}
+	messages := []SessionMessage{{Content: "me@example.org"}}
+	if got := redactor.RedactMessages(RedactSinkTranscripts, messages); got[0].Content != "me@example.org" {
+		t.Errorf("Expected unchanged messages, got %+v", got)
+	}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Relevance weights for scoring portfolio records against a question
+const (
+	relevanceNameMatch     = 10.0 // the record's name, title or company is mentioned
+	relevanceTechMatch     = 4.0  // a technology of the record is mentioned
+	relevanceTitleToken    = 2.0  // a word of the title or category is mentioned
+	relevanceDescToken     = 1.0  // a word of the description is mentioned
+	relevanceDescTokenCap  = 5    // description words counted at most
+	relevanceMatchedScore  = 1.0  // records below this score are considered unrelated
+	relevanceTieBreakScale = 0.1  // proficiency, recency and featured flags only break ties
+)
+
+// relevanceStopwords are words that carry no meaning for ranking
+var relevanceStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "bruno": true,
+	"can": true, "did": true, "do": true, "does": true, "for": true, "from": true, "has": true, "have": true,
+	"he": true, "her": true, "his": true, "how": true, "i": true, "in": true, "is": true, "it": true,
+	"know": true, "me": true, "of": true, "on": true, "or": true, "she": true, "tell": true, "that": true,
+	"the": true, "their": true, "they": true, "this": true, "to": true, "use": true, "used": true, "was": true,
+	"what": true, "when": true, "where": true, "which": true, "who": true, "with": true, "you": true, "your": true,
+	"about": true, "any": true, "some": true, "there": true, "them": true,
+	// Section words select what to include, not which records to rank
+	"skill": true, "experience": true, "project": true, "work": true, "worked": true, "job": true,
+	"role": true, "technology": true, "tech": true, "tool": true, "built": true, "company": true,
+}
+
+// relevanceQuery is a question prepared for scoring records
+type relevanceQuery struct {
+	text   string
+	tokens map[string]bool
+	skills map[string]bool // canonical skill names mentioned in the question
+}
+
+// newRelevanceQuery tokenizes a question and extracts the skills it mentions
+func newRelevanceQuery(query string, vocabulary *skillVocabulary) relevanceQuery {
+	q := relevanceQuery{
+		text:   query,
+		tokens: make(map[string]bool),
+		skills: make(map[string]bool),
+	}
+
+	for _, token := range relevanceTokens(query) {
+		q.tokens[token] = true
+	}
+	if vocabulary != nil {
+		for _, skill := range vocabulary.extract(query) {
+			q.skills[canonicalSkill(skill.Name)] = true
+		}
+	}
+	return q
+}
+
+// relevanceTokens splits text into lowercase, singularized words without stopwords
+func relevanceTokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+
+	var tokens []string
+	for _, field := range fields {
+		if relevanceStopwords[field] {
+			continue
+		}
+		if len(field) > 3 && strings.HasSuffix(field, "s") && !strings.HasSuffix(field, "ss") {
+			field = strings.TrimSuffix(field, "s")
+		}
+		if relevanceStopwords[field] {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// mentionsTechnology reports whether the question names a technology, either
+// through the vocabulary (aliases) or as a plain word ("go", "rag")
+func (q relevanceQuery) mentionsTechnology(tech string) bool {
+	canonical := canonicalSkill(tech)
+	if q.skills[canonical] {
+		return true
+	}
+	tokens := relevanceTokens(canonical)
+	return len(tokens) == 1 && q.tokens[tokens[0]]
+}
+
+// mentionsPhrase reports whether the question contains every word of a phrase
+func (q relevanceQuery) mentionsPhrase(phrase string) bool {
+	tokens := relevanceTokens(phrase)
+	if len(tokens) == 0 {
+		return false
+	}
+	for _, token := range tokens {
+		if !q.tokens[token] {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenOverlap counts the distinct question words found in text
+func (q relevanceQuery) tokenOverlap(text string) int {
+	seen := make(map[string]bool)
+	for _, token := range relevanceTokens(text) {
+		if q.tokens[token] {
+			seen[token] = true
+		}
+	}
+	return len(seen)
+}
+
+// scoreSkill scores a skill by name and category
+func scoreSkill(q relevanceQuery, skill SkillInfo) float64 {
+	score := 0.0
+	if q.mentionsTechnology(skill.Name) || q.mentionsPhrase(skill.Name) {
+		score += relevanceNameMatch
+	} else {
+		score += relevanceTitleToken * float64(q.tokenOverlap(skill.Name))
+	}
+	score += relevanceTitleToken * float64(q.tokenOverlap(skill.Category))
+	return score + relevanceTieBreakScale*float64(skill.Proficiency)/5
+}
+
+// scoreExperience scores an experience entry by company, title, technology
+// overlap and description text
+func scoreExperience(q relevanceQuery, exp ExpInfo) float64 {
+	score := 0.0
+	if q.mentionsPhrase(exp.Company) {
+		score += relevanceNameMatch
+	}
+	score += relevanceTitleToken * float64(q.tokenOverlap(exp.Title))
+	for _, tech := range exp.Technologies {
+		if q.mentionsTechnology(tech) {
+			score += relevanceTechMatch
+		}
+	}
+	score += relevanceDescToken * float64(min(q.tokenOverlap(exp.Description), relevanceDescTokenCap))
+	if exp.Current {
+		score += relevanceTieBreakScale
+	}
+	return score
+}
+
+// scoreProject scores a project by title, type, technology overlap and description text
+func scoreProject(q relevanceQuery, project ProjectInfo) float64 {
+	score := 0.0
+	if q.mentionsPhrase(project.Title) {
+		score += relevanceNameMatch
+	} else {
+		score += relevanceTitleToken * float64(q.tokenOverlap(project.Title))
+	}
+	score += relevanceTitleToken * float64(q.tokenOverlap(project.Type))
+	for _, tech := range project.Technologies {
+		if q.mentionsTechnology(tech) {
+			score += relevanceTechMatch
+		}
+	}
+	score += relevanceDescToken * float64(min(q.tokenOverlap(project.Description), relevanceDescTokenCap))
+	if project.Featured {
+		score += relevanceTieBreakScale
+	}
+	return score
+}
+
+// scoreKnowledge scores a free-text chunk by the words of its title and body
+func scoreKnowledge(q relevanceQuery, title, text string) float64 {
+	score := relevanceTitleToken * float64(q.tokenOverlap(title))
+	return score + relevanceDescToken*float64(min(q.tokenOverlap(text), relevanceDescTokenCap))
+}
+
+// rankByScore returns the indices of the best items. When some items match
+// the question only those are kept; otherwise the default order is used.
+func rankByScore(scores []float64, limit int) []int {
+	indices := make([]int, 0, len(scores))
+	for i, score := range scores {
+		if score >= relevanceMatchedScore {
+			indices = append(indices, i)
+		}
+	}
+
+	if len(indices) == 0 {
+		for i := range scores {
+			indices = append(indices, i)
+		}
+	} else {
+		sort.SliceStable(indices, func(a, b int) bool {
+			return scores[indices[a]] > scores[indices[b]]
+		})
+	}
+
+	if limit > 0 && len(indices) > limit {
+		indices = indices[:limit]
+	}
+	return indices
+}
+
+// contextLimit reads the maximum number of records of a section included in the prompt
+func contextLimit(key string, defaultValue int) int {
+	limit, err := strconv.Atoi(getEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil || limit <= 0 {
+		return defaultValue
+	}
+	return limit
+}
+
+// logRelevance logs the selected records with their scores
+func logRelevance(section string, labels []string, scores []float64) {
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = fmt.Sprintf("%s=%.1f", label, scores[i])
+	}
+	log.Printf("📊 %s relevance: %s", section, strings.Join(parts, ", "))
+}
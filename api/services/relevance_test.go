@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestRankExperience(t *testing.T) {
+	experiences := []ExpInfo{
+		{Title: "SRE/DevOps", Company: "Notifi", Current: true, Technologies: []string{"Kubernetes", "AWS", "RAG"}},
+		{Title: "Senior Infrastructure Engineer", Company: "Mobimeo", Technologies: []string{"AWS", "EKS", "Terraform"}},
+		{Title: "Operations Engineer", Company: "Crealytics", Technologies: []string{"Kafka", "Consul"}},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"company name", "What did Bruno do at Mobimeo?", []string{"Mobimeo"}},
+		{"technology overlap", "Where has he used AWS and Terraform?", []string{"Mobimeo", "Notifi"}},
+		{"alias", "Experience with k8s", []string{"Notifi"}},
+		{"lowercase ambiguous skill", "any rag work?", []string{"Notifi"}},
+		{"no match keeps default order", "Tell me about his career", []string{"Notifi", "Mobimeo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := rankExperience(tt.query, experiences, 2)
+			if len(ranked) != len(tt.want) {
+				t.Fatalf("rankExperience(%q) returned %d entries, want %d", tt.query, len(ranked), len(tt.want))
+			}
+			for i, company := range tt.want {
+				if ranked[i].Company != company {
+					t.Errorf("rankExperience(%q)[%d] = %s, want %s", tt.query, i, ranked[i].Company, company)
+				}
+			}
+		})
+	}
+}
+
+func TestRankSkills(t *testing.T) {
+	skills := []SkillInfo{
+		{Name: "Kubernetes", Category: "Cloud", Proficiency: 5},
+		{Name: "Go", Category: "Programming", Proficiency: 5},
+		{Name: "Python", Category: "Programming", Proficiency: 4},
+		{Name: "Prometheus", Category: "Observability", Proficiency: 5},
+	}
+
+	ranked := rankSkills("Which programming languages does he know? Especially golang", skills, 10)
+	if len(ranked) != 2 || ranked[0].Name != "Go" || ranked[1].Name != "Python" {
+		t.Fatalf("Expected Go then Python, got %+v", ranked)
+	}
+	if ranked[0].Score <= ranked[1].Score {
+		t.Errorf("Expected Go to score higher than Python, got %.1f and %.1f", ranked[0].Score, ranked[1].Score)
+	}
+
+	if ranked := rankSkills("What are his skills?", skills, 3); len(ranked) != 3 || ranked[0].Name != "Kubernetes" {
+		t.Errorf("Expected the first 3 skills in default order, got %+v", ranked)
+	}
+}
+
+func TestRankProjects(t *testing.T) {
+	projects := []ProjectInfo{
+		{Title: "Bruno Site", Type: "Portfolio Website", Featured: true, Technologies: []string{"React", "Go"}},
+		{Title: "Knative Lambda", Type: "Serverless", Featured: true, Technologies: []string{"Knative", "Kubernetes"}},
+		{Title: "Home Infrastructure", Type: "Infrastructure", Description: "Homelab managed with Flux and Pulumi", Technologies: []string{"Flux", "Pulumi"}},
+	}
+
+	ranked := rankProjects("Tell me about the homelab built with Pulumi", projects, 2)
+	if len(ranked) != 1 || ranked[0].Title != "Home Infrastructure" {
+		t.Errorf("Expected Home Infrastructure, got %+v", ranked)
+	}
+
+	ranked = rankProjects("Show me the knative lambda project", projects, 2)
+	if len(ranked) != 1 || ranked[0].Title != "Knative Lambda" {
+		t.Errorf("Expected Knative Lambda, got %+v", ranked)
+	}
+}
+
+func TestRelevanceTokens(t *testing.T) {
+	tokens := relevanceTokens("What does Bruno know about Projects and C++?")
+	want := []string{"c++"}
+	if len(tokens) != len(want) || tokens[0] != want[0] {
+		t.Errorf("relevanceTokens() = %v, want %v", tokens, want)
+	}
+}
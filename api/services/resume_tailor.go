@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Resume size limits
+const (
+	resumeMaxExperience = 5
+	resumeMinExperience = 3
+	resumeMaxProjects   = 4
+	resumeMaxSkills     = 15
+	resumeMaxHighlights = 4
+)
+
+// ResumeTailor builds resume variants targeted at a job description
+type ResumeTailor struct {
+	contextBuilder *ContextBuilder
+	completer      Completer
+}
+
+// TailoredResume is a resume with records selected and ordered by relevance
+type TailoredResume struct {
+	About        string               `json:"about"`
+	Contact      ContactInfo          `json:"contact"`
+	TargetSkills []ExtractedSkill     `json:"target_skills"`
+	Skills       []TailoredSkill      `json:"skills"`
+	Experience   []TailoredExperience `json:"experience"`
+	Projects     []TailoredProject    `json:"projects"`
+	Rewritten    bool                 `json:"rewritten"`
+	Markdown     string               `json:"markdown"`
+	GeneratedAt  string               `json:"generated_at"`
+}
+
+// TailoredSkill is a skill with its relevance to the job description
+type TailoredSkill struct {
+	SkillInfo
+	Relevant bool `json:"relevant"`
+}
+
+// TailoredExperience is an experience entry with its most relevant highlights
+type TailoredExperience struct {
+	ID            int      `json:"id"`
+	Title         string   `json:"title"`
+	Company       string   `json:"company"`
+	Period        string   `json:"period"`
+	Current       bool     `json:"current"`
+	Highlights    []string `json:"highlights"`
+	Technologies  []string `json:"technologies"`
+	MatchedSkills []string `json:"matched_skills"`
+	Relevance     int      `json:"relevance"`
+}
+
+// TailoredProject is a project with its relevance to the job description
+type TailoredProject struct {
+	ID            int      `json:"id"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Type          string   `json:"type"`
+	Technologies  []string `json:"technologies"`
+	GithubURL     string   `json:"github_url,omitempty"`
+	LiveURL       string   `json:"live_url,omitempty"`
+	MatchedSkills []string `json:"matched_skills"`
+	Relevance     int      `json:"relevance"`
+}
+
+// weightedSkill is a job description skill with its importance
+type weightedSkill struct {
+	term   *skillTerm
+	weight int
+}
+
+// NewResumeTailor creates a new resume tailor; completer may be nil to skip LLM rewrites
+func NewResumeTailor(db *sql.DB, completer Completer) *ResumeTailor {
+	return &ResumeTailor{
+		contextBuilder: NewContextBuilder(db),
+		completer:      completer,
+	}
+}
+
+// Tailor builds a resume for a job description, optionally rewriting the
+// experience highlights with the LLM
+func (rt *ResumeTailor) Tailor(ctx context.Context, jobDescription string, rewrite bool) (*TailoredResume, error) {
+	portfolio, err := rt.contextBuilder.LoadPersonalContext()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio: %v", err)
+	}
+
+	resume := TailorResume(jobDescription, portfolio)
+
+	if rewrite && rt.completer != nil {
+		rt.rewriteHighlights(ctx, resume)
+		resume.Markdown = RenderResumeMarkdown(resume)
+	}
+
+	log.Printf("📄 Tailored resume generated: %d target skills, %d experiences, %d projects (rewritten=%v)",
+		len(resume.TargetSkills), len(resume.Experience), len(resume.Projects), resume.Rewritten)
+
+	return resume, nil
+}
+
+// TailorResume selects and orders portfolio records by relevance to a job
+// description without calling the LLM
+func TailorResume(jobDescription string, portfolio *PersonalContext) *TailoredResume {
+	vocabulary := newSkillVocabulary(portfolio)
+	targetSkills := vocabulary.extract(jobDescription)
+
+	var weighted []weightedSkill
+	for _, skill := range targetSkills {
+		weight := 1
+		if skill.Required {
+			weight = 2
+		}
+		weighted = append(weighted, weightedSkill{term: vocabulary.byCanonical[canonicalSkill(skill.Name)], weight: weight})
+	}
+
+	resume := &TailoredResume{
+		About:        portfolio.About.Description,
+		Contact:      portfolio.Contact,
+		TargetSkills: targetSkills,
+		Skills:       tailorSkills(portfolio.Skills, weighted),
+		Experience:   tailorExperience(portfolio.Experience, weighted),
+		Projects:     tailorProjects(portfolio.Projects, weighted),
+		GeneratedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+	resume.Markdown = RenderResumeMarkdown(resume)
+
+	return resume
+}
+
+// scoreRecord weighs job skills found in a record: listed technologies count
+// twice as much as mentions in the description
+func scoreRecord(skills []weightedSkill, technologies []string, description string) (int, []string) {
+	score := 0
+	matched := []string{}
+	for _, skill := range skills {
+		if containsCanonicalSkill(technologies, skill.term.canonical) {
+			score += 2 * skill.weight
+		} else if skill.term.mentions(description) {
+			score += skill.weight
+		} else {
+			continue
+		}
+		matched = append(matched, skill.term.display)
+	}
+	return score, matched
+}
+
+// tailorSkills lists skills requested by the job description first, in the
+// order they appear there, followed by the strongest remaining skills
+func tailorSkills(skills []SkillInfo, jobSkills []weightedSkill) []TailoredSkill {
+	rank := make(map[string]int)
+	for i, skill := range jobSkills {
+		rank[skill.term.canonical] = i
+	}
+
+	tailored := make([]TailoredSkill, 0, len(skills))
+	for _, skill := range skills {
+		_, relevant := rank[canonicalSkill(skill.Name)]
+		tailored = append(tailored, TailoredSkill{SkillInfo: skill, Relevant: relevant})
+	}
+
+	// Skills are already ordered by proficiency, so a stable sort keeps that order for the rest
+	sort.SliceStable(tailored, func(i, j int) bool {
+		if tailored[i].Relevant != tailored[j].Relevant {
+			return tailored[i].Relevant
+		}
+		if tailored[i].Relevant {
+			return rank[canonicalSkill(tailored[i].Name)] < rank[canonicalSkill(tailored[j].Name)]
+		}
+		return false
+	})
+
+	if len(tailored) > resumeMaxSkills {
+		tailored = tailored[:resumeMaxSkills]
+	}
+	return tailored
+}
+
+// tailorExperience keeps the most relevant experience, topping up with the
+// most recent entries so the resume never looks empty
+func tailorExperience(experience []ExpInfo, jobSkills []weightedSkill) []TailoredExperience {
+	tailored := make([]TailoredExperience, 0, len(experience))
+	for _, exp := range experience {
+		relevance, matched := scoreRecord(jobSkills, exp.Technologies, exp.Description)
+		tailored = append(tailored, TailoredExperience{
+			ID:            exp.ID,
+			Title:         exp.Title,
+			Company:       exp.Company,
+			Period:        exp.Period,
+			Current:       exp.Current,
+			Highlights:    selectHighlights(exp.Description, jobSkills),
+			Technologies:  exp.Technologies,
+			MatchedSkills: matched,
+			Relevance:     relevance,
+		})
+	}
+
+	// Experience is ordered most recent first; keep that order between equally relevant entries
+	sort.SliceStable(tailored, func(i, j int) bool {
+		return tailored[i].Relevance > tailored[j].Relevance
+	})
+
+	limit := 0
+	for limit < len(tailored) && limit < resumeMaxExperience && tailored[limit].Relevance > 0 {
+		limit++
+	}
+	if limit < resumeMinExperience {
+		limit = resumeMinExperience
+	}
+	if limit > len(tailored) {
+		limit = len(tailored)
+	}
+	return tailored[:limit]
+}
+
+// tailorProjects keeps the most relevant projects, falling back to featured ones
+func tailorProjects(projects []ProjectInfo, jobSkills []weightedSkill) []TailoredProject {
+	tailored := make([]TailoredProject, 0, len(projects))
+	for _, project := range projects {
+		relevance, matched := scoreRecord(jobSkills, project.Technologies, project.Description)
+		tailored = append(tailored, TailoredProject{
+			ID:            project.ID,
+			Title:         project.Title,
+			Description:   project.Description,
+			Type:          project.Type,
+			Technologies:  project.Technologies,
+			GithubURL:     project.GithubURL,
+			LiveURL:       project.LiveURL,
+			MatchedSkills: matched,
+			Relevance:     relevance,
+		})
+	}
+
+	// Projects are ordered featured first, which is the fallback order
+	sort.SliceStable(tailored, func(i, j int) bool {
+		return tailored[i].Relevance > tailored[j].Relevance
+	})
+
+	if len(tailored) > resumeMaxProjects {
+		tailored = tailored[:resumeMaxProjects]
+	}
+	return tailored
+}
+
+// selectHighlights splits a description into bullet points, putting the ones
+// that mention job skills first
+func selectHighlights(description string, jobSkills []weightedSkill) []string {
+	var bullets []string
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "•") {
+			continue
+		}
+		bullet := strings.TrimSpace(strings.TrimLeft(line, "-• "))
+		if bullet != "" {
+			bullets = append(bullets, bullet)
+		}
+	}
+
+	// Descriptions without bullets are used as a single highlight
+	if len(bullets) == 0 {
+		if trimmed := strings.TrimSpace(description); trimmed != "" {
+			bullets = append(bullets, trimmed)
+		}
+	}
+
+	scores := make(map[string]int, len(bullets))
+	for _, bullet := range bullets {
+		for _, skill := range jobSkills {
+			if skill.term.mentions(bullet) {
+				scores[bullet] += skill.weight
+			}
+		}
+	}
+	sort.SliceStable(bullets, func(i, j int) bool {
+		return scores[bullets[i]] > scores[bullets[j]]
+	})
+
+	if len(bullets) > resumeMaxHighlights {
+		bullets = bullets[:resumeMaxHighlights]
+	}
+	return bullets
+}
+
+const resumeRewriteSystemPrompt = "You are a resume writer. Rewrite the given resume bullet points so they emphasize the target skills. Use ONLY the facts provided, do not invent metrics, companies or technologies. Reply with 2-4 lines, each starting with \"- \"."
+
+// rewriteHighlights asks the LLM to rewrite each experience's highlights,
+// keeping the originals when a rewrite fails
+func (rt *ResumeTailor) rewriteHighlights(ctx context.Context, resume *TailoredResume) {
+	var targets []string
+	for _, skill := range resume.TargetSkills {
+		targets = append(targets, skill.Name)
+	}
+
+	rewrittenAny := false
+	for i := range resume.Experience {
+		exp := &resume.Experience[i]
+		if len(exp.Highlights) == 0 {
+			continue
+		}
+
+		prompt := fmt.Sprintf("TARGET SKILLS: %s\n\nROLE: %s at %s\n\nBULLET POINTS:\n- %s\n",
+			strings.Join(targets, ", "), exp.Title, exp.Company, strings.Join(exp.Highlights, "\n- "))
+
+		response, err := rt.completer.Complete(ctx, resumeRewriteSystemPrompt, prompt)
+		if err != nil {
+			log.Printf("⚠️ Resume rewrite failed for %s at %s, keeping original highlights: %v", exp.Title, exp.Company, err)
+			if ctx.Err() != nil || errors.Is(err, ErrBudgetExceeded) {
+				break
+			}
+			continue
+		}
+
+		if bullets := parseBullets(response); len(bullets) > 0 {
+			exp.Highlights = bullets
+			rewrittenAny = true
+		}
+	}
+
+	resume.Rewritten = rewrittenAny
+}
+
+// parseBullets extracts "- " prefixed lines from an LLM response
+func parseBullets(text string) []string {
+	var bullets []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "- ") && !strings.HasPrefix(line, "* ") {
+			continue
+		}
+		if bullet := strings.TrimSpace(line[2:]); bullet != "" {
+			bullets = append(bullets, bullet)
+		}
+	}
+	return bullets
+}
+
+// RenderResumeMarkdown formats a tailored resume as Markdown
+func RenderResumeMarkdown(resume *TailoredResume) string {
+	var builder strings.Builder
+
+	builder.WriteString("# Bruno Lucena\n\n")
+	if resume.About != "" {
+		builder.WriteString(resume.About + "\n\n")
+	}
+
+	var contact []string
+	for _, value := range []string{resume.Contact.Email, resume.Contact.Location, resume.Contact.LinkedIn, resume.Contact.GitHub} {
+		if value != "" {
+			contact = append(contact, value)
+		}
+	}
+	if len(contact) > 0 {
+		builder.WriteString(strings.Join(contact, " · ") + "\n\n")
+	}
+
+	if len(resume.Skills) > 0 {
+		builder.WriteString("## Skills\n\n")
+		var names []string
+		for _, skill := range resume.Skills {
+			names = append(names, skill.Name)
+		}
+		builder.WriteString(strings.Join(names, ", ") + "\n\n")
+	}
+
+	if len(resume.Experience) > 0 {
+		builder.WriteString("## Experience\n\n")
+		for _, exp := range resume.Experience {
+			builder.WriteString(fmt.Sprintf("### %s — %s\n", exp.Title, exp.Company))
+			if exp.Period != "" {
+				builder.WriteString(fmt.Sprintf("*%s*\n", exp.Period))
+			}
+			builder.WriteString("\n")
+			for _, highlight := range exp.Highlights {
+				builder.WriteString(fmt.Sprintf("- %s\n", highlight))
+			}
+			if len(exp.Technologies) > 0 {
+				builder.WriteString(fmt.Sprintf("\n**Tech:** %s\n", strings.Join(exp.Technologies, ", ")))
+			}
+			builder.WriteString("\n")
+		}
+	}
+
+	if len(resume.Projects) > 0 {
+		builder.WriteString("## Projects\n\n")
+		for _, project := range resume.Projects {
+			builder.WriteString(fmt.Sprintf("### %s\n\n", project.Title))
+			if project.Description != "" {
+				builder.WriteString(project.Description + "\n\n")
+			}
+			if len(project.Technologies) > 0 {
+				builder.WriteString(fmt.Sprintf("**Tech:** %s\n", strings.Join(project.Technologies, ", ")))
+			}
+			if project.GithubURL != "" {
+				builder.WriteString(fmt.Sprintf("**Code:** %s\n", project.GithubURL))
+			}
+			builder.WriteString("\n")
+		}
+	}
+
+	return strings.TrimRight(builder.String(), "\n") + "\n"
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTailorResume(t *testing.T) {
+	portfolio := testPortfolio()
+	portfolio.Experience = append(portfolio.Experience, ExpInfo{
+		ID:           12,
+		Title:        "IT Security Analyst",
+		Company:      "Tempest",
+		Description:  "Key Responsibilities:\n\n- Vulnerability research\n- Automation in Bash",
+		Technologies: []string{"Nessus", "Bash"},
+	})
+	portfolio.About.Description = "Cloud native engineer."
+
+	resume := TailorResume("Requirements:\n- Bash and Nessus\n\nNice to have:\n- Golang", portfolio)
+
+	if len(resume.TargetSkills) != 3 {
+		t.Fatalf("Expected 3 target skills, got %v", resume.TargetSkills)
+	}
+
+	// Every experience is kept as the portfolio has fewer than the minimum
+	if len(resume.Experience) != 3 {
+		t.Fatalf("Expected 3 experiences, got %d", len(resume.Experience))
+	}
+	if resume.Experience[0].Company != "Tempest" || resume.Experience[1].Company != "Namecheap, Inc" {
+		t.Errorf("Expected experience ordered by relevance, got %s, %s", resume.Experience[0].Company, resume.Experience[1].Company)
+	}
+	if resume.Experience[0].Highlights[0] != "Automation in Bash" {
+		t.Errorf("Expected relevant highlight first, got %v", resume.Experience[0].Highlights)
+	}
+	if resume.Experience[2].Relevance != 0 {
+		t.Errorf("Expected unrelated experience last, got relevance %d", resume.Experience[2].Relevance)
+	}
+
+	if resume.Skills[0].Name != "Go" || !resume.Skills[0].Relevant {
+		t.Errorf("Expected Go as first relevant skill, got %+v", resume.Skills[0])
+	}
+	if resume.Projects[0].Title != "Bruno Site" {
+		t.Errorf("Expected Bruno Site project, got %s", resume.Projects[0].Title)
+	}
+
+	for _, section := range []string{"# Bruno Lucena", "Cloud native engineer.", "## Skills", "### IT Security Analyst — Tempest", "- Automation in Bash", "## Projects"} {
+		if !strings.Contains(resume.Markdown, section) {
+			t.Errorf("Expected markdown to contain %q", section)
+		}
+	}
+}
+
+type scriptedCompleter struct {
+	responses []string
+	errs      []error
+	calls     int
+}
+
+func (s *scriptedCompleter) Complete(ctx context.Context, systemPrompt, prompt string) (string, error) {
+	i := s.calls
+	s.calls++
+	return s.responses[i], s.errs[i]
+}
+
+func TestRewriteHighlights(t *testing.T) {
+	resume := TailorResume("Kubernetes", testPortfolio())
+	original := resume.Experience[1].Highlights
+
+	completer := &scriptedCompleter{
+		responses: []string{"Here you go:\n- Ran Kubernetes on AWS\n- Managed Terraform", ""},
+		errs:      []error{nil, errors.New("unavailable")},
+	}
+	tailor := &ResumeTailor{completer: completer}
+	tailor.rewriteHighlights(context.Background(), resume)
+
+	if !resume.Rewritten {
+		t.Error("Expected resume to be marked as rewritten")
+	}
+	if got := resume.Experience[0].Highlights; len(got) != 2 || got[0] != "Ran Kubernetes on AWS" {
+		t.Errorf("Expected rewritten highlights, got %v", got)
+	}
+	if got := resume.Experience[1].Highlights; len(got) != len(original) {
+		t.Errorf("Expected original highlights to be kept on failure, got %v", got)
+	}
+}
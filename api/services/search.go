@@ -0,0 +1,498 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+// Search result types
+const (
+	SearchTypeProject    = "project"
+	SearchTypeExperience = "experience"
+	SearchTypeSkill      = "skill"
+	SearchTypeContent    = "content"
+)
+
+// Search modes
+const (
+	SearchModeFullText = "fulltext"
+	SearchModeHybrid   = "hybrid"
+)
+
+// SearchTypes lists every searchable type in display order
+var SearchTypes = []string{SearchTypeProject, SearchTypeExperience, SearchTypeSkill, SearchTypeContent}
+
+// maxSearchCandidates caps how many full-text matches are ranked per query
+const maxSearchCandidates = 200
+
+// searchHeadlineOptions highlights matched terms in snippets
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=12, MaxFragments=2"
+
+// searchSnippetLength is the length of the plain snippet stored with embeddings
+const searchSnippetLength = 200
+
+// SearchService ranks portfolio content with Postgres full-text search and,
+// when enabled, embedding similarity
+type SearchService struct {
+	db              *sql.DB
+	embeddings      *EmbeddingClient
+	embeddingWeight float64
+	minSimilarity   float64
+	refreshInterval time.Duration
+}
+
+// SearchOptions describes a search query
+type SearchOptions struct {
+	Query  string
+	Types  []string
+	Limit  int
+	Offset int
+}
+
+// SearchResult is a single ranked search hit
+type SearchResult struct {
+	Type          string  `json:"type"`
+	ID            int     `json:"id"`
+	Title         string  `json:"title"`
+	Snippet       string  `json:"snippet"`
+	Score         float64 `json:"score"`
+	TextScore     float64 `json:"text_score"`
+	SemanticScore float64 `json:"semantic_score,omitempty"`
+}
+
+// SearchResponse is a page of search results
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Mode    string         `json:"mode"`
+	Types   []string       `json:"types"`
+	Results []SearchResult `json:"results"`
+	Total   int            `json:"total"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+}
+
+// searchDocument is a searchable record used to build embeddings
+type searchDocument struct {
+	Type  string
+	ID    int
+	Title string
+	Text  string
+}
+
+// Per-type full-text queries; $1 is the user query and $2 the headline options
+var searchTypeQueries = map[string]string{
+	SearchTypeProject: `
+		SELECT 'project', p.id, p.title,
+			ts_headline('english', coalesce(p.description, '') || ' ' || search_text_array(p.technologies), q, $2),
+			ts_rank(p.search_vector, q, 32)
+		FROM projects p, websearch_to_tsquery('english', $1) q
+		WHERE p.active = true AND p.search_vector @@ q`,
+	SearchTypeExperience: `
+		SELECT 'experience', e.id, e.title || ' at ' || e.company,
+			ts_headline('english', coalesce(e.description, '') || ' ' || search_text_array(e.technologies), q, $2),
+			ts_rank(e.search_vector, q, 32)
+		FROM experience e, websearch_to_tsquery('english', $1) q
+		WHERE e.active = true AND e.search_vector @@ q`,
+	SearchTypeSkill: `
+		SELECT 'skill', s.id, s.name,
+			ts_headline('english', s.name || ' (' || s.category || ')', q, $2),
+			ts_rank(s.search_vector, q, 32)
+		FROM skills s, websearch_to_tsquery('english', $1) q
+		WHERE s.active = true AND s.search_vector @@ q`,
+	SearchTypeContent: `
+		SELECT 'content', c.id, c.key,
+			ts_headline('english', coalesce((SELECT string_agg(fields.field_value, ' ') FROM jsonb_each_text(c.value) AS fields(field_key, field_value)), ''), q, $2),
+			ts_rank(c.search_vector, q, 32)
+		FROM content c, websearch_to_tsquery('english', $1) q
+		WHERE c.search_vector @@ q`,
+}
+
+// Per-type document queries used to (re)build embeddings
+var searchDocumentQueries = map[string]string{
+	SearchTypeProject: `
+		SELECT 'project', id, title, title || '. ' || coalesce(description, '') || ' ' || search_text_array(technologies)
+		FROM projects WHERE active = true`,
+	SearchTypeExperience: `
+		SELECT 'experience', id, title || ' at ' || company,
+			title || ' at ' || company || '. ' || coalesce(description, '') || ' ' || search_text_array(technologies)
+		FROM experience WHERE active = true`,
+	SearchTypeSkill: `
+		SELECT 'skill', id, name, name || ' (' || category || ')'
+		FROM skills WHERE active = true`,
+	SearchTypeContent: `
+		SELECT 'content', c.id, c.key,
+			coalesce((SELECT string_agg(fields.field_value, ' ') FROM jsonb_each_text(c.value) AS fields(field_key, field_value)), '')
+		FROM content c`,
+}
+
+// NewSearchService creates a new search service; embedding similarity is
+// enabled with SEARCH_EMBEDDINGS_ENABLED=true, uses the embed model role and
+// counts towards the usage budget of the tracker
+func NewSearchService(db *sql.DB, usage *UsageTracker) *SearchService {
+	service := &SearchService{
+		db:              db,
+		embeddingWeight: parseEnvFloat("SEARCH_EMBEDDING_WEIGHT", 0.4),
+		minSimilarity:   parseEnvFloat("SEARCH_MIN_SIMILARITY", 0.5),
+		refreshInterval: 30 * time.Minute,
+	}
+
+	if minutes, err := strconv.Atoi(getEnv("SEARCH_EMBEDDING_REFRESH_MINUTES", "30")); err == nil && minutes > 0 {
+		service.refreshInterval = time.Duration(minutes) * time.Minute
+	}
+
+	if strings.ToLower(getEnv("SEARCH_EMBEDDINGS_ENABLED", "false")) == "true" {
+		endpoint := LoadModelRoutes().Endpoint(ModelRoleEmbed)
+		service.embeddings = NewEmbeddingClient(endpoint.Backend, endpoint.Model, usage)
+		log.Printf("🔎 Search initialized with embeddings (model %s, weight %.2f)", service.embeddings.Model(), service.embeddingWeight)
+	} else {
+		log.Printf("🔎 Search initialized (full-text only)")
+	}
+
+	return service
+}
+
+// SemanticEnabled reports whether embedding similarity is used
+func (s *SearchService) SemanticEnabled() bool {
+	return s.embeddings != nil
+}
+
+// ParseSearchTypes parses a comma-separated type filter; empty means all types
+func ParseSearchTypes(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return SearchTypes, nil
+	}
+
+	requested := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		searchType := strings.ToLower(strings.TrimSpace(part))
+		if searchType == "" {
+			continue
+		}
+		if _, ok := searchTypeQueries[searchType]; !ok {
+			return nil, fmt.Errorf("unknown search type '%s' (expected one of: %s)", searchType, strings.Join(SearchTypes, ", "))
+		}
+		requested[searchType] = true
+	}
+
+	// Keep the canonical order so queries are stable
+	var types []string
+	for _, searchType := range SearchTypes {
+		if requested[searchType] {
+			types = append(types, searchType)
+		}
+	}
+	if len(types) == 0 {
+		return SearchTypes, nil
+	}
+	return types, nil
+}
+
+// Search ranks portfolio content for a query
+func (s *SearchService) Search(ctx context.Context, options SearchOptions) (*SearchResponse, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not available")
+	}
+	if len(options.Types) == 0 {
+		options.Types = SearchTypes
+	}
+
+	textResults, err := s.fullTextSearch(ctx, options.Query, options.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := SearchModeFullText
+	results := textResults
+	if s.SemanticEnabled() {
+		semanticResults, err := s.semanticSearch(ctx, options.Query, options.Types)
+		if err != nil {
+			log.Printf("⚠️ Semantic search unavailable, using full-text results only: %v", err)
+		} else {
+			mode = SearchModeHybrid
+			results = mergeSearchResults(textResults, semanticResults, s.embeddingWeight, s.minSimilarity)
+		}
+	}
+
+	response := &SearchResponse{
+		Query:   options.Query,
+		Mode:    mode,
+		Types:   options.Types,
+		Results: paginateSearchResults(results, options.Limit, options.Offset),
+		Total:   len(results),
+		Limit:   options.Limit,
+		Offset:  options.Offset,
+	}
+
+	// The query is visitor text that may hold PII, so only its length is logged
+	log.Printf("🔎 Search of %d characters (%s): %d results", utf8.RuneCountInString(options.Query), mode, response.Total)
+	return response, nil
+}
+
+// fullTextSearch runs the per-type full-text queries as a single ranked union
+func (s *SearchService) fullTextSearch(ctx context.Context, query string, types []string) ([]SearchResult, error) {
+	var parts []string
+	for _, searchType := range types {
+		parts = append(parts, searchTypeQueries[searchType])
+	}
+
+	statement := "SELECT type, id, title, snippet, rank FROM (" +
+		strings.Join(parts, "\nUNION ALL\n") +
+		fmt.Sprintf(") AS results(type, id, title, snippet, rank) ORDER BY rank DESC, type, id LIMIT %d", maxSearchCandidates)
+
+	rows, err := s.db.QueryContext(ctx, statement, query, searchHeadlineOptions)
+	if err != nil {
+		return nil, fmt.Errorf("full-text search failed: %v", err)
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var result SearchResult
+		if err := rows.Scan(&result.Type, &result.ID, &result.Title, &result.Snippet, &result.TextScore); err != nil {
+			continue
+		}
+		result.Score = result.TextScore
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// semanticSearch scores every stored document embedding against the query
+func (s *SearchService) semanticSearch(ctx context.Context, query string, types []string) ([]SearchResult, error) {
+	queryEmbedding, err := s.embeddings.Embed(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT entity_type, entity_id, title, coalesce(snippet, ''), embedding
+		FROM search_embeddings
+		WHERE model = $1 AND entity_type = ANY($2)
+	`, s.embeddings.Model(), pq.Array(types))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embeddings: %v", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		var embedding pq.Float64Array
+		if err := rows.Scan(&result.Type, &result.ID, &result.Title, &result.Snippet, &embedding); err != nil {
+			continue
+		}
+		result.SemanticScore = cosineSimilarity(queryEmbedding, embedding)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// mergeSearchResults combines full-text and semantic scores. Documents only
+// found semantically are kept when their similarity reaches minSimilarity.
+func mergeSearchResults(textResults, semanticResults []SearchResult, weight, minSimilarity float64) []SearchResult {
+	key := func(result SearchResult) string {
+		return result.Type + ":" + strconv.Itoa(result.ID)
+	}
+
+	semanticByKey := make(map[string]SearchResult, len(semanticResults))
+	for _, result := range semanticResults {
+		semanticByKey[key(result)] = result
+	}
+
+	merged := make([]SearchResult, 0, len(textResults)+len(semanticResults))
+	seen := make(map[string]bool, len(textResults))
+	for _, result := range textResults {
+		if semantic, ok := semanticByKey[key(result)]; ok {
+			result.SemanticScore = semantic.SemanticScore
+		}
+		result.Score = (1-weight)*result.TextScore + weight*result.SemanticScore
+		merged = append(merged, result)
+		seen[key(result)] = true
+	}
+
+	for _, result := range semanticResults {
+		if seen[key(result)] || result.SemanticScore < minSimilarity {
+			continue
+		}
+		result.Score = weight * result.SemanticScore
+		merged = append(merged, result)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+	return merged
+}
+
+// paginateSearchResults returns the requested page, never nil
+func paginateSearchResults(results []SearchResult, limit, offset int) []SearchResult {
+	if offset >= len(results) || limit <= 0 {
+		return []SearchResult{}
+	}
+	end := offset + limit
+	if end > len(results) {
+		end = len(results)
+	}
+	return results[offset:end]
+}
+
+// IndexEmbeddings embeds new or changed documents and removes stale ones,
+// returning how many documents were embedded
+func (s *SearchService) IndexEmbeddings(ctx context.Context) (int, error) {
+	if !s.SemanticEnabled() {
+		return 0, nil
+	}
+	if s.db == nil {
+		return 0, fmt.Errorf("database connection not available")
+	}
+
+	documents, err := s.loadSearchDocuments(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := make(map[string]string)
+	rows, err := s.db.QueryContext(ctx, `SELECT entity_type, entity_id, content_hash FROM search_embeddings WHERE model = $1`, s.embeddings.Model())
+	if err != nil {
+		return 0, fmt.Errorf("failed to load embedding hashes: %v", err)
+	}
+	for rows.Next() {
+		var entityType, hash string
+		var entityID int
+		if err := rows.Scan(&entityType, &entityID, &hash); err == nil {
+			stored[entityType+":"+strconv.Itoa(entityID)] = hash
+		}
+	}
+	rows.Close()
+
+	embedded := 0
+	current := make(map[string]bool, len(documents))
+	for _, document := range documents {
+		key := document.Type + ":" + strconv.Itoa(document.ID)
+		current[key] = true
+
+		hash := sha256.Sum256([]byte(document.Text))
+		contentHash := hex.EncodeToString(hash[:])
+		if stored[key] == contentHash {
+			continue
+		}
+
+		embedding, err := s.embeddings.Embed(ctx, document.Text)
+		if err != nil {
+			return embedded, fmt.Errorf("failed to embed %s: %v", key, err)
+		}
+
+		_, err = s.db.ExecContext(ctx, `
+			INSERT INTO search_embeddings (entity_type, entity_id, title, snippet, content_hash, model, embedding, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP)
+			ON CONFLICT (entity_type, entity_id) DO UPDATE SET
+				title = EXCLUDED.title,
+				snippet = EXCLUDED.snippet,
+				content_hash = EXCLUDED.content_hash,
+				model = EXCLUDED.model,
+				embedding = EXCLUDED.embedding,
+				updated_at = CURRENT_TIMESTAMP
+		`, document.Type, document.ID, document.Title, plainSnippet(document.Text), contentHash, s.embeddings.Model(), pq.Array(embedding))
+		if err != nil {
+			return embedded, fmt.Errorf("failed to store embedding for %s: %v", key, err)
+		}
+		embedded++
+	}
+
+	// Drop embeddings of deleted or deactivated records
+	for key := range stored {
+		if current[key] {
+			continue
+		}
+		parts := strings.SplitN(key, ":", 2)
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM search_embeddings WHERE entity_type = $1 AND entity_id = $2`, parts[0], parts[1]); err != nil {
+			log.Printf("⚠️ Failed to delete stale embedding %s: %v", key, err)
+		}
+	}
+
+	return embedded, nil
+}
+
+// RunEmbeddingIndexer keeps embeddings up to date until ctx is cancelled
+func (s *SearchService) RunEmbeddingIndexer(ctx context.Context) {
+	if !s.SemanticEnabled() {
+		return
+	}
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		embedded, err := s.IndexEmbeddings(ctx)
+		if err != nil {
+			log.Printf("⚠️ Search embedding indexing failed: %v", err)
+		} else if embedded > 0 {
+			log.Printf("🔎 Search embeddings updated for %d documents", embedded)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadSearchDocuments loads every searchable record as plain text
+func (s *SearchService) loadSearchDocuments(ctx context.Context) ([]searchDocument, error) {
+	var parts []string
+	for _, searchType := range SearchTypes {
+		parts = append(parts, searchDocumentQueries[searchType])
+	}
+
+	rows, err := s.db.QueryContext(ctx, strings.Join(parts, "\nUNION ALL\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load search documents: %v", err)
+	}
+	defer rows.Close()
+
+	var documents []searchDocument
+	for rows.Next() {
+		var document searchDocument
+		if err := rows.Scan(&document.Type, &document.ID, &document.Title, &document.Text); err != nil {
+			continue
+		}
+		documents = append(documents, document)
+	}
+	return documents, rows.Err()
+}
+
+// plainSnippet shortens text to a snippet on a word boundary
+func plainSnippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= searchSnippetLength {
+		return text
+	}
+
+	runes := []rune(text)[:searchSnippetLength]
+	snippet := string(runes)
+	if i := strings.LastIndex(snippet, " "); i > 0 {
+		snippet = snippet[:i]
+	}
+	return snippet + "…"
+}
+
+// parseEnvFloat reads a float environment variable between 0 and 1
+func parseEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil || value < 0 || value > 1 {
+		return defaultValue
+	}
+	return value
+}
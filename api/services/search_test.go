@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseSearchTypes(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: "", want: "project,experience,skill,content"},
+		{raw: "skill, Project", want: "project,skill"},
+		{raw: "content,,content", want: "content"},
+		{raw: "blog", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		types, err := ParseSearchTypes(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSearchTypes(%q) expected error", tt.raw)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSearchTypes(%q) unexpected error: %v", tt.raw, err)
+			continue
+		}
+		if got := strings.Join(types, ","); got != tt.want {
+			t.Errorf("ParseSearchTypes(%q) = %s, want %s", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	if got := cosineSimilarity([]float64{1, 0}, []float64{1, 0}); math.Abs(got-1) > 1e-9 {
+		t.Errorf("Expected identical vectors to have similarity 1, got %f", got)
+	}
+	if got := cosineSimilarity([]float64{1, 0}, []float64{0, 1}); got != 0 {
+		t.Errorf("Expected orthogonal vectors to have similarity 0, got %f", got)
+	}
+	if got := cosineSimilarity([]float64{1, 0}, []float64{1, 0, 0}); got != 0 {
+		t.Errorf("Expected mismatched vectors to have similarity 0, got %f", got)
+	}
+}
+
+func TestMergeSearchResults(t *testing.T) {
+	textResults := []SearchResult{
+		{Type: SearchTypeProject, ID: 1, TextScore: 0.6},
+		{Type: SearchTypeSkill, ID: 2, TextScore: 0.5},
+	}
+	semanticResults := []SearchResult{
+		{Type: SearchTypeProject, ID: 1, SemanticScore: 0.2},
+		{Type: SearchTypeSkill, ID: 2, SemanticScore: 0.9},
+		{Type: SearchTypeExperience, ID: 3, SemanticScore: 0.8},
+		{Type: SearchTypeContent, ID: 4, SemanticScore: 0.3},
+	}
+
+	merged := mergeSearchResults(textResults, semanticResults, 0.5, 0.5)
+
+	var order []string
+	for _, result := range merged {
+		order = append(order, result.Type)
+	}
+	if got := strings.Join(order, ","); got != "skill,project,experience" {
+		t.Errorf("Expected ranking skill,project,experience, got %s", got)
+	}
+	if math.Abs(merged[0].Score-0.7) > 1e-9 {
+		t.Errorf("Expected combined score 0.7, got %f", merged[0].Score)
+	}
+}
+
+func TestPaginateSearchResults(t *testing.T) {
+	results := make([]SearchResult, 5)
+
+	if got := paginateSearchResults(results, 2, 4); len(got) != 1 {
+		t.Errorf("Expected 1 result on the last page, got %d", len(got))
+	}
+	if got := paginateSearchResults(results, 2, 10); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty, non-nil page past the end, got %v", got)
+	}
+}
+
+func TestPlainSnippet(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	snippet := plainSnippet(long)
+
+	if !strings.HasSuffix(snippet, "…") || len([]rune(snippet)) > searchSnippetLength+1 {
+		t.Errorf("Expected truncated snippet, got %q", snippet)
+	}
+	if got := plainSnippet("  short   text "); got != "short text" {
+		t.Errorf("Expected whitespace to be collapsed, got %q", got)
+	}
+}
+
+func TestSearchWithoutDatabase(t *testing.T) {
+	service := NewSearchService(nil, nil)
+
+	if _, err := service.Search(context.Background(), SearchOptions{Query: "go", Limit: 10}); err == nil {
+		t.Error("Expected error when database is not available")
+	}
+}
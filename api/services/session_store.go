@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSessionNotFound is returned when a session ID does not exist or has expired
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionAuthorOwner marks assistant messages written by the site owner
+// during a live takeover instead of the model
+const SessionAuthorOwner = "owner"
+
+var sessionIDPattern = regexp.MustCompile(`^sess_[0-9a-f]{32}$`)
+
+// SessionStore persists chat sessions in Redis so that every chat transport
+// (HTTP, WebSocket) shares the same conversation history
+type SessionStore struct {
+	redis       *redis.Client
+	redactor    *Redactor
+	ttl         time.Duration
+	maxMessages int64
+}
+
+// ChatSession represents a visitor conversation
+type ChatSession struct {
+	ID        string           `json:"id"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Messages  []SessionMessage `json:"messages"`
+
+	// Token grants the visitor access to the transcript. It is only set on the
+	// session returned when it was created; the store keeps a hash.
+	Token string `json:"-"`
+}
+
+// SessionMessage represents a single message stored in a chat session
+type SessionMessage struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	Sources   []string  `json:"sources,omitempty"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewSessionStore creates a new session store. Messages are masked with the
+// redactor's transcript rules before they are stored.
+func NewSessionStore(rdb *redis.Client, redactor *Redactor) *SessionStore {
+	ttlHours, err := strconv.Atoi(getEnv("CHAT_SESSION_TTL_HOURS", "24"))
+	if err != nil || ttlHours <= 0 {
+		ttlHours = 24
+	}
+
+	maxMessages, err := strconv.ParseInt(getEnv("CHAT_SESSION_MAX_MESSAGES", "100"), 10, 64)
+	if err != nil || maxMessages <= 0 {
+		maxMessages = 100
+	}
+
+	return &SessionStore{
+		redis:       rdb,
+		redactor:    redactor,
+		ttl:         time.Duration(ttlHours) * time.Hour,
+		maxMessages: maxMessages,
+	}
+}
+
+// GetOrCreate loads an existing session or creates a new one when the ID is
+// empty or unknown
+func (s *SessionStore) GetOrCreate(ctx context.Context, sessionID string) (*ChatSession, error) {
+	if s.redis == nil {
+		return nil, fmt.Errorf("redis connection not available")
+	}
+
+	if sessionID != "" {
+		session, err := s.Get(ctx, sessionID)
+		if err == nil {
+			return session, nil
+		}
+		if err != redis.Nil {
+			return nil, err
+		}
+	}
+
+	return s.create(ctx)
+}
+
+// Get loads a session with its messages, returning redis.Nil when it does not exist
+func (s *SessionStore) Get(ctx context.Context, sessionID string) (*ChatSession, error) {
+	if s.redis == nil {
+		return nil, fmt.Errorf("redis connection not available")
+	}
+
+	meta, err := s.redis.HGetAll(ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(meta) == 0 {
+		return nil, redis.Nil
+	}
+
+	session := &ChatSession{ID: sessionID}
+	session.CreatedAt, _ = time.Parse(time.RFC3339Nano, meta["created_at"])
+	session.UpdatedAt, _ = time.Parse(time.RFC3339Nano, meta["updated_at"])
+
+	rawMessages, err := s.redis.LRange(ctx, sessionMessagesKey(sessionID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, raw := range rawMessages {
+		var message SessionMessage
+		if err := json.Unmarshal([]byte(raw), &message); err != nil {
+			continue
+		}
+		session.Messages = append(session.Messages, message)
+	}
+
+	return session, nil
+}
+
+// Exists reports whether a session is stored and has not expired
+func (s *SessionStore) Exists(ctx context.Context, sessionID string) (bool, error) {
+	if s.redis == nil {
+		return false, fmt.Errorf("redis connection not available")
+	}
+	count, err := s.redis.Exists(ctx, sessionKey(sessionID)).Result()
+	return count == 1, err
+}
+
+// AppendMessages adds messages to a session and refreshes its expiry
+func (s *SessionStore) AppendMessages(ctx context.Context, sessionID string, messages ...SessionMessage) error {
+	if s.redis == nil {
+		return fmt.Errorf("redis connection not available")
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(messages))
+	for _, message := range s.redactor.RedactMessages(RedactSinkTranscripts, messages) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to encode session message: %v", err)
+		}
+		values = append(values, data)
+	}
+
+	pipe := s.redis.TxPipeline()
+	pipe.RPush(ctx, sessionMessagesKey(sessionID), values...)
+	pipe.LTrim(ctx, sessionMessagesKey(sessionID), -s.maxMessages, -1)
+	pipe.HSet(ctx, sessionKey(sessionID), "updated_at", time.Now().UTC().Format(time.RFC3339Nano))
+	pipe.Expire(ctx, sessionMessagesKey(sessionID), s.ttl)
+	pipe.Expire(ctx, sessionKey(sessionID), s.ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// MarkLeadOffered records that the lead capture offer was made in a session,
+// reporting false when it had already been made
+func (s *SessionStore) MarkLeadOffered(ctx context.Context, sessionID string) (bool, error) {
+	if s.redis == nil {
+		return false, fmt.Errorf("redis connection not available")
+	}
+	return s.redis.HSetNX(ctx, sessionKey(sessionID), "lead_offered", time.Now().UTC().Format(time.RFC3339Nano)).Result()
+}
+
+// LeadOffered reports whether the lead capture offer was made in a session
+func (s *SessionStore) LeadOffered(ctx context.Context, sessionID string) (bool, error) {
+	if s.redis == nil {
+		return false, fmt.Errorf("redis connection not available")
+	}
+	return s.redis.HExists(ctx, sessionKey(sessionID), "lead_offered").Result()
+}
+
+// VerifyToken reports whether token is the transcript token of a session,
+// returning redis.Nil when the session does not exist
+func (s *SessionStore) VerifyToken(ctx context.Context, sessionID, token string) (bool, error) {
+	if s.redis == nil {
+		return false, fmt.Errorf("redis connection not available")
+	}
+
+	stored, err := s.redis.HGet(ctx, sessionKey(sessionID), "token_hash").Result()
+	if err == redis.Nil {
+		// Sessions created before tokens were issued cannot be exported
+		if exists, err := s.redis.Exists(ctx, sessionKey(sessionID)).Result(); err == nil && exists == 1 {
+			return false, nil
+		}
+		return false, redis.Nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(hashSessionToken(token)), []byte(stored)) == 1, nil
+}
+
+// create stores a new empty session with a fresh transcript token
+func (s *SessionStore) create(ctx context.Context) (*ChatSession, error) {
+	sessionID, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+	token, err := newSessionToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	session := &ChatSession{
+		ID:        sessionID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Token:     token,
+	}
+
+	pipe := s.redis.TxPipeline()
+	pipe.HSet(ctx, sessionKey(sessionID),
+		"created_at", now.Format(time.RFC3339Nano),
+		"updated_at", now.Format(time.RFC3339Nano),
+		"token_hash", hashSessionToken(token),
+	)
+	pipe.Expire(ctx, sessionKey(sessionID), s.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// RecentMessages returns the last n messages of a session as LLM chat messages
+func (session *ChatSession) RecentMessages(n int) []ChatMessage {
+	if session == nil {
+		return nil
+	}
+
+	var history []ChatMessage
+	for _, message := range session.Messages {
+		history = append(history, ChatMessage{Role: message.Role, Content: message.Content})
+	}
+	return trimHistory(history, n)
+}
+
+// trimHistory keeps the last n messages of a conversation
+func trimHistory(history []ChatMessage, n int) []ChatMessage {
+	if history == nil {
+		return nil
+	}
+	if n <= 0 {
+		return []ChatMessage{}
+	}
+	if len(history) > n {
+		return history[len(history)-n:]
+	}
+	return history
+}
+
+// newSessionID generates a random, URL-safe session identifier
+func newSessionID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "sess_" + hex.EncodeToString(bytes), nil
+}
+
+// newSessionToken generates the secret that grants access to a transcript
+func newSessionToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// hashSessionToken is how tokens are stored, so a Redis dump does not leak them
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidSessionID reports whether id has the format of generated session IDs
+func ValidSessionID(id string) bool {
+	return sessionIDPattern.MatchString(id)
+}
+
+func sessionKey(sessionID string) string {
+	return "chat:session:" + sessionID
+}
+
+func sessionMessagesKey(sessionID string) string {
+	return "chat:session:" + sessionID + ":messages"
+}
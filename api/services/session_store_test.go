@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestSessionStoreWithoutRedis tests that the store fails gracefully without Redis
+func TestSessionStoreWithoutRedis(t *testing.T) {
+	store := NewSessionStore(nil, nil)
+
+	if _, err := store.GetOrCreate(context.Background(), ""); err == nil {
+		t.Error("Expected error when redis is not available")
+	}
+
+	if err := store.AppendMessages(context.Background(), "sess_test", SessionMessage{Role: "user", Content: "hi"}); err == nil {
+		t.Error("Expected error when redis is not available")
+	}
+}
+
+// TestRecentMessages tests the history window used for prompts
+func TestRecentMessages(t *testing.T) {
+	session := &ChatSession{ID: "sess_test"}
+	for _, content := range []string{"one", "two", "three"} {
+		session.Messages = append(session.Messages, SessionMessage{Role: "user", Content: content, Timestamp: time.Now()})
+	}
+
+	history := session.RecentMessages(2)
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(history))
+	}
+	if history[0].Content != "two" || history[1].Content != "three" {
+		t.Errorf("Expected last two messages, got %+v", history)
+	}
+
+	if len(session.RecentMessages(10)) != 3 {
+		t.Error("Expected all messages when window exceeds history")
+	}
+
+	var empty *ChatSession
+	if empty.RecentMessages(5) != nil {
+		t.Error("Expected nil history for nil session")
+	}
+}
+
+// TestNewSessionID tests session ID generation
+func TestNewSessionID(t *testing.T) {
+	first, err := newSessionID()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := newSessionID()
+
+	if first == second {
+		t.Error("Expected unique session IDs")
+	}
+	if len(first) != len("sess_")+32 {
+		t.Errorf("Unexpected session ID length: %q", first)
+	}
+	if !ValidSessionID(first) || ValidSessionID("sess_../../etc") {
+		t.Error("Expected ValidSessionID to accept generated IDs only")
+	}
+}
+
+// TestSessionTokenHash tests that stored token hashes are stable and do not contain the token
+func TestSessionTokenHash(t *testing.T) {
+	token, err := newSessionToken()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(token) != 64 {
+		t.Errorf("Unexpected token length: %q", token)
+	}
+	if hashSessionToken(token) != hashSessionToken(token) || hashSessionToken(token) == token {
+		t.Error("Expected a stable hash that differs from the token")
+	}
+
+	if _, err := NewSessionStore(nil, nil).VerifyToken(context.Background(), "sess_test", token); err == nil {
+		t.Error("Expected error when redis is not available")
+	}
+}
+
+// TestSessionStoreLeadOffer tests the checks lead submissions rely on
+func TestSessionStoreLeadOffer(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer rdb.Close()
+	store := NewSessionStore(rdb, nil)
+	ctx := context.Background()
+
+	session, err := store.GetOrCreate(ctx, "")
+	if err != nil {
+		t.Fatalf("GetOrCreate failed: %v", err)
+	}
+	if exists, err := store.Exists(ctx, session.ID); err != nil || !exists {
+		t.Errorf("Expected the new session to exist, got %v, %v", exists, err)
+	}
+	if exists, _ := store.Exists(ctx, "sess_00000000000000000000000000000000"); exists {
+		t.Error("Expected an unknown session not to exist")
+	}
+
+	if valid, err := store.VerifyToken(ctx, session.ID, session.Token); err != nil || !valid {
+		t.Errorf("Expected the session token to verify, got %v, %v", valid, err)
+	}
+	if valid, _ := store.VerifyToken(ctx, session.ID, "wrong"); valid {
+		t.Error("Expected a wrong token to be rejected")
+	}
+
+	if offered, err := store.LeadOffered(ctx, session.ID); err != nil || offered {
+		t.Errorf("Expected no lead offer yet, got %v, %v", offered, err)
+	}
+	if _, err := store.MarkLeadOffered(ctx, session.ID); err != nil {
+		t.Fatalf("MarkLeadOffered failed: %v", err)
+	}
+	if offered, err := store.LeadOffered(ctx, session.ID); err != nil || !offered {
+		t.Errorf("Expected the lead offer to be recorded, got %v, %v", offered, err)
+	}
+}
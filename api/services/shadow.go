@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ShadowEvaluator sends a sample of the chat prompts to a candidate model in
+// the background and stores its answers next to the primary model's answers.
+// Visitors always get the primary answer.
+type ShadowEvaluator struct {
+	db        *sql.DB
+	candidate *LLMService
+	grounding *GroundingChecker
+	redactor  *Redactor
+	percent   float64
+	timeout   time.Duration
+	slots     chan struct{}
+}
+
+// ShadowJob is a primary answer to replay against the candidate model
+type ShadowJob struct {
+	RequestID        string
+	SessionID        string
+	Question         string
+	Mode             string
+	PrimaryModel     string
+	Messages         []ChatMessage
+	Options          *OllamaOptions
+	Snippets         []ContextSnippet
+	PrimaryAnswer    string
+	PrimaryLatency   time.Duration
+	PrimaryGrounding *GroundingReport
+}
+
+// ShadowAnswer is the answer of one model in a comparison
+type ShadowAnswer struct {
+	Model          string   `json:"model"`
+	Answer         string   `json:"answer,omitempty"`
+	LatencyMs      int      `json:"latency_ms"`
+	GroundingScore float64  `json:"grounding_score"`
+	Ungrounded     []string `json:"ungrounded"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// ShadowComparison is a question answered by both models
+type ShadowComparison struct {
+	ID        int          `json:"id"`
+	RequestID string       `json:"request_id"`
+	Question  string       `json:"question"`
+	Mode      string       `json:"mode"`
+	Primary   ShadowAnswer `json:"primary"`
+	Candidate ShadowAnswer `json:"candidate"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// ShadowModelStats summarizes the answers of one model
+type ShadowModelStats struct {
+	Model             string  `json:"model"`
+	AvgLatencyMs      float64 `json:"avg_latency_ms"`
+	P95LatencyMs      float64 `json:"p95_latency_ms"`
+	AvgGroundingScore float64 `json:"avg_grounding_score"`
+	FlaggedAnswers    int     `json:"flagged_answers"`
+}
+
+// ShadowReport compares a candidate model with the primary model
+type ShadowReport struct {
+	Samples         int              `json:"samples"`
+	CandidateErrors int              `json:"candidate_errors"`
+	Primary         ShadowModelStats `json:"primary"`
+	Candidate       ShadowModelStats `json:"candidate"`
+	CandidateBetter int              `json:"candidate_better"` // higher grounding score
+	PrimaryBetter   int              `json:"primary_better"`
+	Ties            int              `json:"ties"`
+}
+
+// NewShadowEvaluator returns an evaluator for SHADOW_MODEL, or nil when shadow
+// mode is disabled. Shadow traffic uses the same GPUs, so the candidate
+// records its usage with the primary model's tracker.
+func NewShadowEvaluator(db *sql.DB, ollamaURL string, grounding *GroundingChecker, redactor *Redactor, usage *UsageTracker) *ShadowEvaluator {
+	model := getEnv("SHADOW_MODEL", "")
+	if model == "" {
+		return nil
+	}
+
+	percent, err := strconv.ParseFloat(getEnv("SHADOW_SAMPLE_PERCENT", "10"), 64)
+	if err != nil {
+		percent = 10
+	}
+	percent = min(max(percent, 0), 100)
+
+	concurrency, err := strconv.Atoi(getEnv("SHADOW_MAX_CONCURRENT", "2"))
+	if err != nil || concurrency <= 0 {
+		concurrency = 2
+	}
+
+	timeout := 120 * time.Second
+	evaluator := &ShadowEvaluator{
+		db: db,
+		candidate: &LLMService{
+			ollamaURL:  getEnv("SHADOW_OLLAMA_URL", ollamaURL),
+			model:      model,
+			usage:      usage,
+			httpClient: &http.Client{Timeout: timeout},
+		},
+		grounding: grounding,
+		redactor:  redactor,
+		percent:   percent,
+		timeout:   timeout,
+		slots:     make(chan struct{}, concurrency),
+	}
+
+	log.Printf("👥 Shadow mode enabled: %.1f%% of prompts to %s (max %d concurrent)", percent, model, concurrency)
+	return evaluator
+}
+
+// Model returns the candidate model
+func (s *ShadowEvaluator) Model() string {
+	return s.candidate.model
+}
+
+// Submit replays a sampled job against the candidate model in the background.
+// Jobs are dropped when all slots are busy so shadow traffic never queues up.
+func (s *ShadowEvaluator) Submit(job ShadowJob) bool {
+	if s == nil || rand.Float64()*100 >= s.percent {
+		return false
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		log.Printf("👥 [%s] Shadow evaluation skipped, all slots busy", job.RequestID)
+		return false
+	}
+
+	go func() {
+		defer func() { <-s.slots }()
+		s.evaluate(job)
+	}()
+	return true
+}
+
+// evaluate asks the candidate model and stores the comparison
+func (s *ShadowEvaluator) evaluate(job ShadowJob) {
+	ctx, cancel := context.WithTimeout(withUsageSession(context.Background(), job.SessionID), s.timeout)
+	defer cancel()
+
+	if s.candidate.usage.Status(ctx).Exceeded {
+		log.Printf("👥 [%s] Shadow evaluation skipped, daily budget spent", job.RequestID)
+		return
+	}
+
+	comparison := ShadowComparison{
+		RequestID: job.RequestID,
+		Question:  job.Question,
+		Mode:      job.Mode,
+		Primary: ShadowAnswer{
+			Model:          job.PrimaryModel,
+			Answer:         job.PrimaryAnswer,
+			LatencyMs:      int(job.PrimaryLatency.Milliseconds()),
+			GroundingScore: job.PrimaryGrounding.Score,
+			Ungrounded:     job.PrimaryGrounding.Ungrounded,
+		},
+		Candidate: ShadowAnswer{Model: s.candidate.model},
+	}
+
+	startTime := time.Now()
+	answer, err := s.candidate.callOllama(ctx, ModelRoleAnswer, job.Messages, job.Options, "shadow_"+job.RequestID)
+	comparison.Candidate.LatencyMs = int(time.Since(startTime).Milliseconds())
+	if err != nil {
+		comparison.Candidate.Error = err.Error()
+	} else {
+		report := s.grounding.Check(answer, job.Question, job.Snippets)
+		comparison.Candidate.Answer = answer
+		comparison.Candidate.GroundingScore = report.Score
+		comparison.Candidate.Ungrounded = report.Ungrounded
+	}
+
+	if err := s.store(ctx, comparison); err != nil {
+		log.Printf("⚠️ [%s] Failed to store shadow comparison: %v", job.RequestID, err)
+		return
+	}
+	log.Printf("👥 [%s] Shadow comparison stored: %s %dms (%.2f) vs %s %dms (%.2f)", job.RequestID,
+		comparison.Primary.Model, comparison.Primary.LatencyMs, comparison.Primary.GroundingScore,
+		comparison.Candidate.Model, comparison.Candidate.LatencyMs, comparison.Candidate.GroundingScore)
+}
+
+// store inserts a comparison
+func (s *ShadowEvaluator) store(ctx context.Context, c ShadowComparison) error {
+	if s.db == nil {
+		return fmt.Errorf("database connection not available")
+	}
+
+	c.Question = s.redactor.Redact(RedactSinkShadow, c.Question)
+	c.Primary.Answer = s.redactor.Redact(RedactSinkShadow, c.Primary.Answer)
+
+	var candidateAnswer, candidateError sql.NullString
+	var candidateScore sql.NullFloat64
+	if c.Candidate.Error != "" {
+		candidateError = sql.NullString{String: c.Candidate.Error, Valid: true}
+	} else {
+		candidateAnswer = sql.NullString{String: s.redactor.Redact(RedactSinkShadow, c.Candidate.Answer), Valid: true}
+		candidateScore = sql.NullFloat64{Float64: c.Candidate.GroundingScore, Valid: true}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO shadow_comparisons (
+			request_id, question, mode,
+			primary_model, primary_answer, primary_latency_ms, primary_grounding_score, primary_ungrounded,
+			candidate_model, candidate_answer, candidate_latency_ms, candidate_grounding_score, candidate_ungrounded, candidate_error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, c.RequestID, c.Question, c.Mode,
+		c.Primary.Model, c.Primary.Answer, c.Primary.LatencyMs, c.Primary.GroundingScore, pq.Array(c.Primary.Ungrounded),
+		c.Candidate.Model, candidateAnswer, c.Candidate.LatencyMs, candidateScore, pq.Array(c.Candidate.Ungrounded), candidateError)
+	return err
+}
+
+// ListShadowComparisons returns the latest comparisons, optionally for one candidate model
+func ListShadowComparisons(ctx context.Context, db *sql.DB, model string, limit int) ([]ShadowComparison, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection not available")
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, request_id, question, mode,
+			primary_model, primary_answer, primary_latency_ms, primary_grounding_score, COALESCE(primary_ungrounded, '{}'),
+			candidate_model, COALESCE(candidate_answer, ''), COALESCE(candidate_latency_ms, 0),
+			COALESCE(candidate_grounding_score, 0), COALESCE(candidate_ungrounded, '{}'), COALESCE(candidate_error, ''),
+			created_at
+		FROM shadow_comparisons
+		WHERE $1 = '' OR candidate_model = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, model, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comparisons := []ShadowComparison{}
+	for rows.Next() {
+		var c ShadowComparison
+		var primaryUngrounded, candidateUngrounded pq.StringArray
+		if err := rows.Scan(&c.ID, &c.RequestID, &c.Question, &c.Mode,
+			&c.Primary.Model, &c.Primary.Answer, &c.Primary.LatencyMs, &c.Primary.GroundingScore, &primaryUngrounded,
+			&c.Candidate.Model, &c.Candidate.Answer, &c.Candidate.LatencyMs,
+			&c.Candidate.GroundingScore, &candidateUngrounded, &c.Candidate.Error,
+			&c.CreatedAt); err != nil {
+			continue
+		}
+		c.Primary.Ungrounded = []string(primaryUngrounded)
+		c.Candidate.Ungrounded = []string(candidateUngrounded)
+		comparisons = append(comparisons, c)
+	}
+	return comparisons, nil
+}
+
+// ShadowComparisonReport aggregates the comparisons since a point in time,
+// one report per primary and candidate model pair. Failed candidate answers
+// only count as errors.
+func ShadowComparisonReport(ctx context.Context, db *sql.DB, since time.Time, flaggedBelow float64) ([]ShadowReport, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection not available")
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT primary_model, candidate_model,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE candidate_error IS NOT NULL),
+			AVG(primary_latency_ms),
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY primary_latency_ms),
+			AVG(primary_grounding_score),
+			COUNT(*) FILTER (WHERE primary_grounding_score < $2),
+			COALESCE(AVG(candidate_latency_ms) FILTER (WHERE candidate_error IS NULL), 0),
+			COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY candidate_latency_ms) FILTER (WHERE candidate_error IS NULL), 0),
+			COALESCE(AVG(candidate_grounding_score), 0),
+			COUNT(*) FILTER (WHERE candidate_grounding_score < $2),
+			COUNT(*) FILTER (WHERE candidate_grounding_score > primary_grounding_score),
+			COUNT(*) FILTER (WHERE candidate_grounding_score < primary_grounding_score),
+			COUNT(*) FILTER (WHERE candidate_grounding_score = primary_grounding_score)
+		FROM shadow_comparisons
+		WHERE created_at >= $1
+		GROUP BY primary_model, candidate_model
+		ORDER BY candidate_model, primary_model
+	`, since, flaggedBelow)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []ShadowReport{}
+	for rows.Next() {
+		var r ShadowReport
+		if err := rows.Scan(&r.Primary.Model, &r.Candidate.Model,
+			&r.Samples, &r.CandidateErrors,
+			&r.Primary.AvgLatencyMs, &r.Primary.P95LatencyMs, &r.Primary.AvgGroundingScore, &r.Primary.FlaggedAnswers,
+			&r.Candidate.AvgLatencyMs, &r.Candidate.P95LatencyMs, &r.Candidate.AvgGroundingScore, &r.Candidate.FlaggedAnswers,
+			&r.CandidateBetter, &r.PrimaryBetter, &r.Ties); err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	return reports, rows.Err()
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestNewShadowEvaluatorDisabledByDefault(t *testing.T) {
+	t.Setenv("SHADOW_MODEL", "")
+	if evaluator := NewShadowEvaluator(nil, "http://127.0.0.1:0", NewGroundingChecker(nil), nil, nil); evaluator != nil {
+		t.Errorf("Expected shadow mode to be disabled without SHADOW_MODEL")
+	}
+
+	var evaluator *ShadowEvaluator
+	if evaluator.Submit(ShadowJob{RequestID: "chat_1"}) {
+		t.Errorf("Expected a disabled evaluator to skip every job")
+	}
+}
+
+func TestShadowEvaluatorSampling(t *testing.T) {
+	t.Setenv("SHADOW_MODEL", "llama3.2:3b")
+	t.Setenv("SHADOW_SAMPLE_PERCENT", "250")
+	t.Setenv("SHADOW_MAX_CONCURRENT", "1")
+
+	evaluator := NewShadowEvaluator(nil, "http://127.0.0.1:0", NewGroundingChecker(nil), nil, nil)
+	if evaluator == nil || evaluator.Model() != "llama3.2:3b" {
+		t.Fatalf("Expected a shadow evaluator for the candidate model, got %+v", evaluator)
+	}
+	if evaluator.percent != 100 {
+		t.Errorf("Expected the sample percentage to be capped at 100, got %.1f", evaluator.percent)
+	}
+
+	// With the only slot taken, jobs are dropped instead of queued
+	evaluator.slots <- struct{}{}
+	if evaluator.Submit(ShadowJob{RequestID: "chat_1", PrimaryGrounding: &GroundingReport{Score: 1}}) {
+		t.Errorf("Expected the job to be dropped while all slots are busy")
+	}
+
+	evaluator.percent = 0
+	<-evaluator.slots
+	if evaluator.Submit(ShadowJob{RequestID: "chat_2", PrimaryGrounding: &GroundingReport{Score: 1}}) {
+		t.Errorf("Expected no job to be sampled at 0%%")
+	}
+}
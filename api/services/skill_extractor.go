@@ -0,0 +1,294 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// skillAliases maps alternative spellings onto canonical (lowercase) skill names
+var skillAliases = map[string]string{
+	"golang":                 "go",
+	"k8s":                    "kubernetes",
+	"postgres":               "postgresql",
+	"amazon web services":    "aws",
+	"google cloud":           "gcp",
+	"google cloud platform":  "gcp",
+	"eks":                    "aws eks",
+	"amazon eks":             "aws eks",
+	"lambda":                 "aws lambda",
+	"microsoft azure":        "azure",
+	"sre":                    "site reliability engineering",
+	"ml":                     "machine learning",
+	"nlp":                    "natural language processing",
+	"iac":                    "infrastructure as code",
+	"continuous integration": "ci/cd",
+	"continuous delivery":    "ci/cd",
+	"continuous deployment":  "ci/cd",
+	"argo cd":                "argocd",
+	"otel":                   "opentelemetry",
+	"open telemetry":         "opentelemetry",
+	"nodejs":                 "node.js",
+	"agile":                  "agile/scrum",
+	"scrum":                  "agile/scrum",
+	"gitlab ci":              "gitlab ci/cd",
+	"elasticsearch":          "elk",
+}
+
+// caseSensitiveSkills are skills whose names are also common English words;
+// they only match when written exactly like this
+var caseSensitiveSkills = map[string]string{
+	"go":     "Go",
+	"rag":    "RAG",
+	"rest":   "REST",
+	"swift":  "Swift",
+	"spark":  "Spark",
+	"vault":  "Vault",
+	"chef":   "Chef",
+	"puppet": "Puppet",
+	"envoy":  "Envoy",
+	"helm":   "Helm",
+	"flux":   "Flux",
+	"tempo":  "Tempo",
+}
+
+// commonTechnologies are well-known skills that may show up in job descriptions
+// even though they are not part of the portfolio, so they can be reported as missing
+var commonTechnologies = []string{
+	"Java", "Kotlin", "Scala", "C++", "C#", "Rust", "Ruby", "PHP", "Swift", "Node.js",
+	"React", "Angular", "Vue", "GraphQL", "gRPC", "REST",
+	"Kafka", "Spark", "Airflow", "Snowflake", "BigQuery", "MySQL", "Cassandra", "DynamoDB",
+	"Istio", "Linkerd", "Envoy", "Consul", "Vault", "Nomad", "OpenShift",
+	"Ansible", "Chef", "Puppet", "Saltstack", "CloudFormation", "Crossplane",
+	"Prometheus", "Grafana", "Datadog", "Splunk", "New Relic", "Jaeger", "Thanos", "PagerDuty",
+	"Linux", "Networking", "SQL", "Microservices", "Distributed Systems",
+	"PyTorch", "LLM", "MLOps", "Kubeflow",
+}
+
+// ExtractedSkill is a skill mentioned in a free-text document such as a job description
+type ExtractedSkill struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+}
+
+// skillVocabulary recognizes skill names, including their aliases, in free text
+type skillVocabulary struct {
+	terms       []*skillTerm
+	byCanonical map[string]*skillTerm
+}
+
+// skillTerm is a single canonical skill with the patterns that match it
+type skillTerm struct {
+	canonical string
+	display   string
+	patterns  []*regexp.Regexp
+}
+
+// canonicalSkill normalizes a skill name so aliases compare equal
+func canonicalSkill(name string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	if canonical, ok := skillAliases[normalized]; ok {
+		return canonical
+	}
+	return normalized
+}
+
+// newSkillVocabulary builds a vocabulary from the portfolio skills and
+// technologies plus the common technologies list
+func newSkillVocabulary(portfolio *PersonalContext) *skillVocabulary {
+	vocabulary := &skillVocabulary{byCanonical: make(map[string]*skillTerm)}
+
+	if portfolio != nil {
+		for _, skill := range portfolio.Skills {
+			vocabulary.add(skill.Name)
+		}
+		for _, exp := range portfolio.Experience {
+			for _, tech := range exp.Technologies {
+				vocabulary.add(tech)
+			}
+		}
+		for _, project := range portfolio.Projects {
+			for _, tech := range project.Technologies {
+				vocabulary.add(tech)
+			}
+		}
+	}
+	for _, tech := range commonTechnologies {
+		vocabulary.add(tech)
+	}
+
+	return vocabulary
+}
+
+// add registers a skill name; the first spelling seen is used for display
+func (v *skillVocabulary) add(name string) {
+	canonical := canonicalSkill(name)
+	if canonical == "" {
+		return
+	}
+	if _, exists := v.byCanonical[canonical]; exists {
+		return
+	}
+
+	term := &skillTerm{canonical: canonical, display: strings.TrimSpace(name)}
+	if exact, ok := caseSensitiveSkills[canonical]; ok {
+		term.display = exact
+		term.patterns = append(term.patterns, skillPattern(exact, true))
+	} else {
+		term.patterns = append(term.patterns, skillPattern(canonical, false))
+	}
+	for alias, target := range skillAliases {
+		if target == canonical {
+			term.patterns = append(term.patterns, skillPattern(alias, false))
+		}
+	}
+
+	v.terms = append(v.terms, term)
+	v.byCanonical[canonical] = term
+}
+
+// skillPattern matches a term on word boundaries, allowing symbols such as
+// "c++" or "ci/cd" inside the term
+func skillPattern(term string, caseSensitive bool) *regexp.Regexp {
+	words := strings.Fields(term)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	expr := `(?:^|[^\pL\pN])(` + strings.Join(words, `[\s-]+`) + `)(?:$|[^\pL\pN+#])`
+	if !caseSensitive {
+		expr = "(?i)" + expr
+	}
+	return regexp.MustCompile(expr)
+}
+
+// mentions reports whether the term appears in text
+func (term *skillTerm) mentions(text string) bool {
+	for _, pattern := range term.patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Section headings that mark the following lines as optional or required
+var (
+	optionalSkillMarkers = []string{"nice to have", "nice-to-have", "preferred", "bonus", "a plus", "desirable", "optional"}
+	requiredSkillMarkers = []string{"requirement", "required", "must have", "must-have", "qualifications", "responsibilities", "what you", "about you", "you have", "you will"}
+)
+
+// extract finds the skills mentioned in text in order of first appearance.
+// Overlapping mentions resolve to the longest term ("AWS Lambda" over "AWS"),
+// and skills only found in "nice to have" sections are marked as not required.
+func (v *skillVocabulary) extract(text string) []ExtractedSkill {
+	type mention struct {
+		term       *skillTerm
+		start, end int
+	}
+	type found struct {
+		skill ExtractedSkill
+		line  int
+		start int
+	}
+
+	var results []*found
+	byCanonical := make(map[string]*found)
+	optionalSection := false
+
+	for lineIndex, line := range strings.Split(text, "\n") {
+		optionalLine := classifySkillLine(line, &optionalSection)
+
+		var mentions []mention
+		for _, term := range v.terms {
+			for _, pattern := range term.patterns {
+				for _, match := range pattern.FindAllStringSubmatchIndex(line, -1) {
+					mentions = append(mentions, mention{term: term, start: match[2], end: match[3]})
+				}
+			}
+		}
+
+		// Longest mentions win, earlier ones break ties
+		sort.SliceStable(mentions, func(i, j int) bool {
+			li, lj := mentions[i].end-mentions[i].start, mentions[j].end-mentions[j].start
+			if li != lj {
+				return li > lj
+			}
+			return mentions[i].start < mentions[j].start
+		})
+
+		var taken []mention
+		for _, m := range mentions {
+			overlaps := false
+			for _, t := range taken {
+				if m.start < t.end && t.start < m.end {
+					overlaps = true
+					break
+				}
+			}
+			if overlaps {
+				continue
+			}
+			taken = append(taken, m)
+
+			if existing, ok := byCanonical[m.term.canonical]; ok {
+				existing.skill.Required = existing.skill.Required || !optionalLine
+				if lineIndex == existing.line && m.start < existing.start {
+					existing.start = m.start
+				}
+				continue
+			}
+			entry := &found{
+				skill: ExtractedSkill{Name: m.term.display, Required: !optionalLine},
+				line:  lineIndex,
+				start: m.start,
+			}
+			byCanonical[m.term.canonical] = entry
+			results = append(results, entry)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].line != results[j].line {
+			return results[i].line < results[j].line
+		}
+		return results[i].start < results[j].start
+	})
+
+	skills := make([]ExtractedSkill, 0, len(results))
+	for _, entry := range results {
+		skills = append(skills, entry.skill)
+	}
+	return skills
+}
+
+// classifySkillLine reports whether skills on a line are optional, updating
+// the current section when the line is a heading
+func classifySkillLine(line string, optionalSection *bool) bool {
+	lower := strings.ToLower(strings.TrimSpace(line))
+	if lower == "" {
+		return *optionalSection
+	}
+
+	isBullet := strings.HasPrefix(lower, "-") || strings.HasPrefix(lower, "*") || strings.HasPrefix(lower, "•")
+	isHeading := !isBullet && len(strings.Fields(lower)) <= 6
+
+	for _, marker := range optionalSkillMarkers {
+		if strings.Contains(lower, marker) {
+			if isHeading {
+				*optionalSection = true
+			}
+			return true
+		}
+	}
+
+	if isHeading {
+		for _, marker := range requiredSkillMarkers {
+			if strings.Contains(lower, marker) {
+				*optionalSection = false
+				break
+			}
+		}
+	}
+
+	return *optionalSection
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Transcript export formats
+const (
+	TranscriptMarkdown = "md"
+	TranscriptJSON     = "json"
+	TranscriptText     = "txt"
+)
+
+// TranscriptFormats lists the export formats
+var TranscriptFormats = []string{TranscriptMarkdown, TranscriptJSON, TranscriptText}
+
+var transcriptContentTypes = map[string]string{
+	TranscriptMarkdown: "text/markdown; charset=utf-8",
+	TranscriptJSON:     "application/json; charset=utf-8",
+	TranscriptText:     "text/plain; charset=utf-8",
+}
+
+// TranscriptContentType returns the MIME type of an export format, or "" when
+// the format is unknown
+func TranscriptContentType(format string) string {
+	return transcriptContentTypes[format]
+}
+
+// RenderTranscript formats a session with the timestamps and cited sources
+// of every message
+func RenderTranscript(session *ChatSession, format string) ([]byte, error) {
+	switch format {
+	case TranscriptJSON:
+		messages := session.Messages
+		if messages == nil {
+			messages = []SessionMessage{}
+		}
+		return json.MarshalIndent(struct {
+			SessionID  string           `json:"session_id"`
+			CreatedAt  time.Time        `json:"created_at"`
+			ExportedAt time.Time        `json:"exported_at"`
+			Messages   []SessionMessage `json:"messages"`
+		}{session.ID, session.CreatedAt, time.Now().UTC(), messages}, "", "  ")
+	case TranscriptMarkdown:
+		return []byte(renderTranscriptText(session, true)), nil
+	case TranscriptText:
+		return []byte(renderTranscriptText(session, false)), nil
+	}
+	return nil, fmt.Errorf("unknown transcript format %q, expected one of %s", format, strings.Join(TranscriptFormats, ", "))
+}
+
+// renderTranscriptText renders a transcript as Markdown or plain text
+func renderTranscriptText(session *ChatSession, markdown bool) string {
+	var b strings.Builder
+
+	title := "Chat with Bruno's assistant"
+	if markdown {
+		fmt.Fprintf(&b, "# %s\n\n", title)
+		fmt.Fprintf(&b, "- Session: `%s`\n- Started: %s\n", session.ID, formatTranscriptTime(session.CreatedAt))
+	} else {
+		fmt.Fprintf(&b, "%s\n%s\n", title, strings.Repeat("=", len(title)))
+		fmt.Fprintf(&b, "Session: %s\nStarted: %s\n", session.ID, formatTranscriptTime(session.CreatedAt))
+	}
+
+	for _, message := range session.Messages {
+		speaker := transcriptSpeaker(message)
+		timestamp := formatTranscriptTime(message.Timestamp)
+		if markdown {
+			fmt.Fprintf(&b, "\n**%s** · %s\n\n%s\n", speaker, timestamp, message.Content)
+			if len(message.Sources) > 0 {
+				fmt.Fprintf(&b, "\n_Sources: %s_\n", strings.Join(message.Sources, ", "))
+			}
+		} else {
+			fmt.Fprintf(&b, "\n[%s] %s:\n%s\n", timestamp, speaker, message.Content)
+			if len(message.Sources) > 0 {
+				fmt.Fprintf(&b, "Sources: %s\n", strings.Join(message.Sources, ", "))
+			}
+		}
+	}
+	return b.String()
+}
+
+// transcriptSpeaker names the author of a message
+func transcriptSpeaker(message SessionMessage) string {
+	switch {
+	case message.Role == "user":
+		return "You"
+	case message.Author == SessionAuthorOwner:
+		return "Bruno"
+	default:
+		return "Assistant"
+	}
+}
+
+func formatTranscriptTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTranscriptSession() *ChatSession {
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	return &ChatSession{
+		ID:        "sess_test",
+		CreatedAt: at,
+		Messages: []SessionMessage{
+			{Role: "user", Content: "What is Tempest?", Timestamp: at},
+			{Role: "assistant", Content: "A game engine.", Sources: []string{"projects#3"}, Timestamp: at.Add(time.Second)},
+			{Role: "assistant", Content: "Happy to tell you more.", Author: SessionAuthorOwner, Timestamp: at.Add(time.Minute)},
+		},
+	}
+}
+
+func TestRenderTranscriptText(t *testing.T) {
+	session := testTranscriptSession()
+
+	markdown, err := RenderTranscript(session, TranscriptMarkdown)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"**You** · 2025-03-01 10:00:00 UTC", "_Sources: projects#3_", "**Bruno**"} {
+		if !strings.Contains(string(markdown), want) {
+			t.Errorf("Expected Markdown transcript to contain %q:\n%s", want, markdown)
+		}
+	}
+
+	text, _ := RenderTranscript(session, TranscriptText)
+	for _, want := range []string{"[2025-03-01 10:00:01 UTC] Assistant:", "Sources: projects#3"} {
+		if !strings.Contains(string(text), want) {
+			t.Errorf("Expected text transcript to contain %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestRenderTranscriptJSON(t *testing.T) {
+	data, err := RenderTranscript(testTranscriptSession(), TranscriptJSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var export struct {
+		SessionID string           `json:"session_id"`
+		Messages  []SessionMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+	if export.SessionID != "sess_test" || len(export.Messages) != 3 || export.Messages[1].Sources[0] != "projects#3" {
+		t.Errorf("Unexpected JSON export: %+v", export)
+	}
+
+	if _, err := RenderTranscript(testTranscriptSession(), "pdf"); err == nil {
+		t.Error("Expected unknown formats to be rejected")
+	}
+}
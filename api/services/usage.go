@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// What the chat does once a daily budget is spent
+const (
+	BudgetModeDegraded = "degraded"
+	BudgetModeFAQOnly  = "faq_only"
+)
+
+// ErrBudgetExceeded is returned by model calls outside the chat pipeline once
+// the daily budget is spent
+var ErrBudgetExceeded = errors.New("daily usage budget exceeded")
+
+// budgetExhaustedMessage answers messages without an FAQ match in FAQ-only mode
+const budgetExhaustedMessage = "The assistant has reached its daily limit and can only answer common questions right now. Please try again tomorrow or use the contact form."
+
+var (
+	usageTokens = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "llm_tokens_total",
+		Help: "Tokens reported by Ollama by role, model and kind (prompt or completion)",
+	}, []string{"role", "model", "kind"})
+	usageGPUSeconds = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "llm_generation_seconds_total",
+		Help: "Prompt evaluation and generation time reported by Ollama by role and model",
+	}, []string{"role", "model"})
+	usageBudgetExceeded = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "llm_daily_budget_exceeded",
+		Help: "1 while a daily usage budget is spent",
+	})
+)
+
+// ModelUsage is the token and compute usage Ollama reports for one request
+type ModelUsage struct {
+	Role               string
+	Model              string
+	PromptTokens       int
+	CompletionTokens   int
+	GenerationDuration time.Duration
+}
+
+// UsageTotals sums the usage of many requests
+type UsageTotals struct {
+	Requests         int64   `json:"requests"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	GPUSeconds       float64 `json:"gpu_seconds"`
+}
+
+// UsageBudget limits the daily usage; zero limits are disabled
+type UsageBudget struct {
+	DailyTokens     int64   `json:"daily_tokens"`
+	DailyGPUSeconds float64 `json:"daily_gpu_seconds"`
+	Mode            string  `json:"mode"`
+	DegradedTokens  int     `json:"degraded_max_tokens"`
+}
+
+// BudgetStatus is today's usage against the budget
+type BudgetStatus struct {
+	Day      string      `json:"day"`
+	Budget   UsageBudget `json:"budget"`
+	Today    UsageTotals `json:"today"`
+	Exceeded bool        `json:"exceeded"`
+}
+
+// UsageTracker aggregates Ollama usage per day, session, model and role in
+// Postgres and enforces the daily budgets
+type UsageTracker struct {
+	db              *sql.DB
+	budget          UsageBudget
+	refreshInterval time.Duration
+
+	mu       sync.Mutex
+	day      string
+	today    UsageTotals
+	loadedAt time.Time
+}
+
+// NewUsageTracker creates a tracker with budgets from the environment
+func NewUsageTracker(db *sql.DB) *UsageTracker {
+	budget := UsageBudget{
+		DailyTokens:    int64(envInt("USAGE_DAILY_TOKEN_BUDGET", 0)),
+		Mode:           strings.ToLower(getEnv("USAGE_BUDGET_MODE", BudgetModeFAQOnly)),
+		DegradedTokens: envInt("USAGE_DEGRADED_MAX_TOKENS", 128),
+	}
+	if seconds, err := strconv.ParseFloat(getEnv("USAGE_DAILY_GPU_SECONDS_BUDGET", "0"), 64); err == nil && seconds > 0 {
+		budget.DailyGPUSeconds = seconds
+	}
+	if budget.Mode != BudgetModeDegraded && budget.Mode != BudgetModeFAQOnly {
+		log.Printf("⚠️ Unknown USAGE_BUDGET_MODE %q, using %s", budget.Mode, BudgetModeFAQOnly)
+		budget.Mode = BudgetModeFAQOnly
+	}
+
+	if budget.DailyTokens > 0 || budget.DailyGPUSeconds > 0 {
+		log.Printf("💰 Daily usage budget: %d tokens, %.0f GPU seconds, then %s", budget.DailyTokens, budget.DailyGPUSeconds, budget.Mode)
+	}
+
+	return &UsageTracker{db: db, budget: budget, refreshInterval: time.Minute}
+}
+
+// Record adds the usage of a request to today's totals and stores it in the
+// background. A nil tracker records nothing.
+func (t *UsageTracker) Record(sessionID string, usage ModelUsage) {
+	usageTokens.WithLabelValues(usage.Role, usage.Model, "prompt").Add(float64(usage.PromptTokens))
+	usageTokens.WithLabelValues(usage.Role, usage.Model, "completion").Add(float64(usage.CompletionTokens))
+	usageGPUSeconds.WithLabelValues(usage.Role, usage.Model).Add(usage.GenerationDuration.Seconds())
+
+	if t == nil {
+		return
+	}
+
+	day := usageDay(time.Now())
+	t.mu.Lock()
+	if t.day != day {
+		t.day, t.today, t.loadedAt = day, UsageTotals{}, time.Time{}
+	}
+	t.today.add(usage)
+	t.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := t.store(ctx, day, sessionID, usage); err != nil {
+			log.Printf("⚠️ Failed to store model usage: %v", err)
+		}
+	}()
+}
+
+// Status returns today's usage against the budget. Totals are reloaded from
+// Postgres once a minute so every replica sees the usage of the others.
+func (t *UsageTracker) Status(ctx context.Context) BudgetStatus {
+	if t == nil {
+		return BudgetStatus{Day: usageDay(time.Now())}
+	}
+
+	day := usageDay(time.Now())
+	t.mu.Lock()
+	if t.day != day {
+		t.day, t.today, t.loadedAt = day, UsageTotals{}, time.Time{}
+	}
+	// One request claims the refresh; the others keep using the local totals
+	// instead of waiting for the query
+	refresh := t.db != nil && time.Since(t.loadedAt) >= t.refreshInterval
+	if refresh {
+		t.loadedAt = time.Now()
+	}
+	t.mu.Unlock()
+
+	if refresh {
+		totals, err := loadUsageTotals(ctx, t.db, day)
+		if err != nil {
+			log.Printf("⚠️ Failed to load today's model usage, using local totals: %v", err)
+		}
+		t.mu.Lock()
+		if err == nil && t.day == day && totals.TotalTokens >= t.today.TotalTokens {
+			t.today = totals
+		}
+		t.mu.Unlock()
+	}
+
+	t.mu.Lock()
+	status := BudgetStatus{Day: day, Budget: t.budget, Today: t.today}
+	t.mu.Unlock()
+
+	status.Exceeded = (t.budget.DailyTokens > 0 && t.today.TotalTokens >= t.budget.DailyTokens) ||
+		(t.budget.DailyGPUSeconds > 0 && t.today.GPUSeconds >= t.budget.DailyGPUSeconds)
+	if status.Exceeded {
+		usageBudgetExceeded.Set(1)
+	} else {
+		usageBudgetExceeded.Set(0)
+	}
+	return status
+}
+
+// degradedOptions caps the answer length once the budget is spent
+func (t *UsageTracker) degradedOptions(options *OllamaOptions) *OllamaOptions {
+	if t.budget.DegradedTokens <= 0 {
+		return options
+	}
+	if options == nil || options.NumPredict <= 0 || options.NumPredict > t.budget.DegradedTokens {
+		return &OllamaOptions{NumPredict: t.budget.DegradedTokens}
+	}
+	return options
+}
+
+// store adds the usage to its aggregate row
+func (t *UsageTracker) store(ctx context.Context, day, sessionID string, usage ModelUsage) error {
+	if t.db == nil {
+		return fmt.Errorf("database connection not available")
+	}
+
+	_, err := t.db.ExecContext(ctx, `
+		INSERT INTO llm_usage (day, session_id, model, role, requests, prompt_tokens, completion_tokens, generation_ms)
+		VALUES ($1, $2, $3, $4, 1, $5, $6, $7)
+		ON CONFLICT (day, session_id, model, role) DO UPDATE SET
+			requests = llm_usage.requests + 1,
+			prompt_tokens = llm_usage.prompt_tokens + EXCLUDED.prompt_tokens,
+			completion_tokens = llm_usage.completion_tokens + EXCLUDED.completion_tokens,
+			generation_ms = llm_usage.generation_ms + EXCLUDED.generation_ms,
+			updated_at = CURRENT_TIMESTAMP
+	`, day, sessionID, usage.Model, usage.Role, usage.PromptTokens, usage.CompletionTokens, usage.GenerationDuration.Milliseconds())
+	return err
+}
+
+// add counts one request
+func (u *UsageTotals) add(usage ModelUsage) {
+	u.Requests++
+	u.PromptTokens += int64(usage.PromptTokens)
+	u.CompletionTokens += int64(usage.CompletionTokens)
+	u.TotalTokens = u.PromptTokens + u.CompletionTokens
+	u.GPUSeconds += usage.GenerationDuration.Seconds()
+}
+
+// usage returns the usage reported in the final response of a request.
+// Compute time is prompt evaluation plus generation, without model loading.
+func (r OllamaResponse) usage(endpoint ModelEndpoint) ModelUsage {
+	duration := time.Duration(r.PromptEvalDuration + r.EvalDuration)
+	if duration == 0 {
+		duration = time.Duration(r.TotalDuration)
+	}
+	return ModelUsage{
+		Role:               endpoint.Role,
+		Model:              endpoint.Model,
+		PromptTokens:       r.PromptEvalCount,
+		CompletionTokens:   r.EvalCount,
+		GenerationDuration: duration,
+	}
+}
+
+// usageDay is the UTC day usage is aggregated under
+func usageDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// usageSessionKey carries the chat session of model requests in their context
+type usageSessionKey struct{}
+
+func withUsageSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, usageSessionKey{}, sessionID)
+}
+
+func usageSession(ctx context.Context) string {
+	sessionID, _ := ctx.Value(usageSessionKey{}).(string)
+	return sessionID
+}
+
+// UsageMeter sums the tokens Ollama reports for the model requests made with
+// a context, so handlers can charge them to the visitor's quota
+type UsageMeter struct {
+	tokens atomic.Int64
+}
+
+// usageMeterKey carries the usage meter of a request in its context
+type usageMeterKey struct{}
+
+// WithUsageMeter returns a context whose model requests are added to the meter
+func WithUsageMeter(ctx context.Context) (context.Context, *UsageMeter) {
+	meter := &UsageMeter{}
+	return context.WithValue(ctx, usageMeterKey{}, meter), meter
+}
+
+// Tokens returns the prompt and completion tokens metered so far
+func (m *UsageMeter) Tokens() int {
+	return int(m.tokens.Load())
+}
+
+// recordRequest records the usage of a model request made with ctx, for the
+// chat session and the usage meter of the context
+func (t *UsageTracker) recordRequest(ctx context.Context, usage ModelUsage) {
+	t.Record(usageSession(ctx), usage)
+	if meter, ok := ctx.Value(usageMeterKey{}).(*UsageMeter); ok {
+		meter.tokens.Add(int64(usage.PromptTokens + usage.CompletionTokens))
+	}
+}
+
+// =============================================================================
+// 📊 USAGE REPORT
+// =============================================================================
+
+// UsageDayTotals is the usage of one day
+type UsageDayTotals struct {
+	Day string `json:"day"`
+	UsageTotals
+}
+
+// UsageModelTotals is the usage of one model in one role
+type UsageModelTotals struct {
+	Model string `json:"model"`
+	Role  string `json:"role"`
+	UsageTotals
+}
+
+// UsageSessionTotals is the usage of one chat session
+type UsageSessionTotals struct {
+	SessionID string `json:"session_id"`
+	UsageTotals
+}
+
+// UsageReport breaks the usage since a day down per day, model and session
+type UsageReport struct {
+	Since    string               `json:"since"`
+	Total    UsageTotals          `json:"total"`
+	Days     []UsageDayTotals     `json:"days"`
+	Models   []UsageModelTotals   `json:"models"`
+	Sessions []UsageSessionTotals `json:"top_sessions"`
+}
+
+// usageTotalsColumns sums an llm_usage selection into UsageTotals
+const usageTotalsColumns = `COALESCE(SUM(requests), 0), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0), COALESCE(SUM(generation_ms), 0)`
+
+// LoadUsageReport aggregates the usage since a day, with the sessions that used the most tokens
+func LoadUsageReport(ctx context.Context, db *sql.DB, since time.Time, sessionLimit int) (*UsageReport, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection not available")
+	}
+
+	day := usageDay(since)
+	report := &UsageReport{
+		Since:    day,
+		Days:     []UsageDayTotals{},
+		Models:   []UsageModelTotals{},
+		Sessions: []UsageSessionTotals{},
+	}
+
+	row := db.QueryRowContext(ctx, `SELECT `+usageTotalsColumns+` FROM llm_usage WHERE day >= $1`, day)
+	if err := scanUsageTotals(row.Scan, &report.Total); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT to_char(day, 'YYYY-MM-DD'), `+usageTotalsColumns+`
+		FROM llm_usage WHERE day >= $1
+		GROUP BY day ORDER BY day DESC
+	`, day)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var totals UsageDayTotals
+		if err := scanUsageTotals(rows.Scan, &totals.UsageTotals, &totals.Day); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		report.Days = append(report.Days, totals)
+	}
+	rows.Close()
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT model, role, `+usageTotalsColumns+`
+		FROM llm_usage WHERE day >= $1
+		GROUP BY model, role ORDER BY SUM(generation_ms) DESC
+	`, day)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var totals UsageModelTotals
+		if err := scanUsageTotals(rows.Scan, &totals.UsageTotals, &totals.Model, &totals.Role); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		report.Models = append(report.Models, totals)
+	}
+	rows.Close()
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT session_id, `+usageTotalsColumns+`
+		FROM llm_usage WHERE day >= $1 AND session_id <> ''
+		GROUP BY session_id ORDER BY SUM(prompt_tokens + completion_tokens) DESC
+		LIMIT $2
+	`, day, sessionLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var totals UsageSessionTotals
+		if err := scanUsageTotals(rows.Scan, &totals.UsageTotals, &totals.SessionID); err != nil {
+			return nil, err
+		}
+		report.Sessions = append(report.Sessions, totals)
+	}
+
+	return report, rows.Err()
+}
+
+// loadUsageTotals sums the usage of one day
+func loadUsageTotals(ctx context.Context, db *sql.DB, day string) (UsageTotals, error) {
+	var totals UsageTotals
+	row := db.QueryRowContext(ctx, `SELECT `+usageTotalsColumns+` FROM llm_usage WHERE day = $1`, day)
+	err := scanUsageTotals(row.Scan, &totals)
+	return totals, err
+}
+
+// scanUsageTotals scans the leading columns into dest, then the usageTotalsColumns into totals
+func scanUsageTotals(scan func(dest ...any) error, totals *UsageTotals, leading ...any) error {
+	var generationMs int64
+	dest := append(leading, &totals.Requests, &totals.PromptTokens, &totals.CompletionTokens, &generationMs)
+	if err := scan(dest...); err != nil {
+		return err
+	}
+	totals.TotalTokens = totals.PromptTokens + totals.CompletionTokens
+	totals.GPUSeconds = float64(generationMs) / 1000
+	return nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestOllamaResponseUsage tests reading usage from the final Ollama response
+func TestOllamaResponseUsage(t *testing.T) {
+	endpoint := ModelEndpoint{Role: ModelRoleAnswer, Model: "gemma3n:e4b"}
+
+	usage := OllamaResponse{
+		PromptEvalCount:    120,
+		EvalCount:          40,
+		TotalDuration:      int64(5 * time.Second),
+		PromptEvalDuration: int64(500 * time.Millisecond),
+		EvalDuration:       int64(2 * time.Second),
+	}.usage(endpoint)
+	if usage.PromptTokens != 120 || usage.CompletionTokens != 40 || usage.Model != "gemma3n:e4b" || usage.Role != ModelRoleAnswer {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+	if usage.GenerationDuration != 2500*time.Millisecond {
+		t.Errorf("Expected compute time without model loading, got %v", usage.GenerationDuration)
+	}
+
+	if usage := (OllamaResponse{TotalDuration: int64(time.Second)}).usage(endpoint); usage.GenerationDuration != time.Second {
+		t.Errorf("Expected the total duration as fallback, got %v", usage.GenerationDuration)
+	}
+}
+
+// TestUsageTrackerBudget tests that the budget is exceeded by the local totals
+func TestUsageTrackerBudget(t *testing.T) {
+	t.Setenv("USAGE_DAILY_TOKEN_BUDGET", "100")
+	t.Setenv("USAGE_BUDGET_MODE", "degraded")
+	tracker := NewUsageTracker(nil)
+
+	tracker.Record("sess_test", ModelUsage{Role: ModelRoleAnswer, Model: "m", PromptTokens: 50, CompletionTokens: 20, GenerationDuration: time.Second})
+	status := tracker.Status(context.Background())
+	if status.Exceeded || status.Today.TotalTokens != 70 || status.Today.Requests != 1 {
+		t.Errorf("Expected 70 tokens within budget, got %+v", status)
+	}
+
+	tracker.Record("sess_test", ModelUsage{Role: ModelRoleAnswer, Model: "m", PromptTokens: 20, CompletionTokens: 10})
+	status = tracker.Status(context.Background())
+	if !status.Exceeded || status.Budget.Mode != BudgetModeDegraded {
+		t.Errorf("Expected the budget to be exceeded in degraded mode, got %+v", status)
+	}
+
+	if options := tracker.degradedOptions(&OllamaOptions{NumPredict: 1024}); options.NumPredict != 128 {
+		t.Errorf("Expected degraded answers to be capped at 128 tokens, got %d", options.NumPredict)
+	}
+	if options := tracker.degradedOptions(&OllamaOptions{NumPredict: 64}); options.NumPredict != 64 {
+		t.Errorf("Expected shorter limits to be kept, got %d", options.NumPredict)
+	}
+
+	tracker.day = "2000-01-01"
+	if status := tracker.Status(context.Background()); status.Exceeded || status.Today.Requests != 0 {
+		t.Errorf("Expected the totals to reset on a new day, got %+v", status)
+	}
+}
+
+// TestUsageTrackerDisabled tests that unconfigured and nil trackers never exceed a budget
+func TestUsageTrackerDisabled(t *testing.T) {
+	tracker := NewUsageTracker(nil)
+	tracker.Record("", ModelUsage{PromptTokens: 1_000_000})
+	if tracker.Status(context.Background()).Exceeded {
+		t.Error("Expected no budget without USAGE_DAILY_TOKEN_BUDGET")
+	}
+
+	var disabled *UsageTracker
+	disabled.Record("", ModelUsage{PromptTokens: 1})
+	if disabled.Status(context.Background()).Exceeded {
+		t.Error("Expected a nil tracker to never exceed a budget")
+	}
+
+	if usageSession(withUsageSession(context.Background(), "sess_test")) != "sess_test" || usageSession(context.Background()) != "" {
+		t.Error("Expected the usage session to round-trip through the context")
+	}
+}
+
+// TestProcessChatOverBudget tests the FAQ-only mode once the budget is spent
+func TestProcessChatOverBudget(t *testing.T) {
+	t.Setenv("USAGE_DAILY_TOKEN_BUDGET", "10")
+	usage := NewUsageTracker(nil)
+	usage.Record("", ModelUsage{PromptTokens: 10})
+
+	llm := &LLMService{
+		model:          "test-model",
+		contextBuilder: &ContextBuilder{},
+		sessions:       NewSessionStore(nil, nil),
+		faqs:           newTestFAQMatcher(FAQEntry{ID: 7, Question: "Where is Bruno based?", Answer: "Berlin."}),
+		usage:          usage,
+	}
+
+	response, err := llm.ProcessChatStream(context.Background(), ChatRequest{Message: "Where is Bruno based?", History: []ChatMessage{}}, nil)
+	if err != nil || response.Source != ResponseSourceFAQ {
+		t.Errorf("Expected FAQ answers past the budget, got %+v (%v)", response, err)
+	}
+
+	response, err = llm.ProcessChatStream(context.Background(), ChatRequest{Message: "Tell me about his Kubernetes work", History: []ChatMessage{}}, nil)
+	if err != nil {
+		t.Fatalf("Expected the budget notice without calling the model, got error: %v", err)
+	}
+	if response.Source != ResponseSourceBudget || response.Response != budgetExhaustedMessage || !response.Degraded {
+		t.Errorf("Unexpected response past the budget: %+v", response)
+	}
+}
+
+// TestBudgetOutsideChat tests that completions and embeddings record usage and stop once the budget is spent
+func TestBudgetOutsideChat(t *testing.T) {
+	t.Setenv("USAGE_DAILY_TOKEN_BUDGET", "100")
+	tracker := NewUsageTracker(nil)
+
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"embeddings": [[0.1, 0.2]], "prompt_eval_count": 60, "total_duration": 3000000, "load_duration": 1000000}`))
+	}))
+	defer ollama.Close()
+
+	client := NewEmbeddingClient(ollama.URL, "nomic-embed-text", tracker)
+	ctx := withUsageSession(context.Background(), "sess_test")
+	embedding, err := client.Embed(ctx, "kubernetes")
+	if err != nil || len(embedding) != 2 {
+		t.Fatalf("Expected an embedding, got %v, %v", embedding, err)
+	}
+	if status := tracker.Status(ctx); status.Today.PromptTokens != 60 || status.Today.GPUSeconds != 0.002 {
+		t.Errorf("Expected the embedding usage to be recorded, got %+v", status.Today)
+	}
+
+	tracker.Record("sess_test", ModelUsage{Role: ModelRoleSummarize, Model: "m", CompletionTokens: 40})
+	if _, err := client.Embed(ctx, "kubernetes"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected embeddings to stop over budget, got %v", err)
+	}
+
+	service := &LLMService{model: "test-model", ollamaURL: ollama.URL, usage: tracker, httpClient: ollama.Client()}
+	if _, err := service.Complete(ctx, "system", "prompt"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected completions to stop over budget, got %v", err)
+	}
+}
+
+// TestUsageMeter tests that a usage meter sums the tokens Ollama reports for its context
+func TestUsageMeter(t *testing.T) {
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message": {"role": "assistant", "content": "Go and Kubernetes."}, "done": true, "prompt_eval_count": 900, "eval_count": 12}`))
+	}))
+	defer ollama.Close()
+
+	service := &LLMService{model: "test-model", ollamaURL: ollama.URL, httpClient: ollama.Client()}
+	ctx, meter := WithUsageMeter(context.Background())
+	for i := 0; i < 2; i++ {
+		if _, err := service.Complete(ctx, "system", "prompt"); err != nil {
+			t.Fatalf("Expected a completion, got %v", err)
+		}
+	}
+	if meter.Tokens() != 1824 {
+		t.Errorf("Expected the reported prompt and completion tokens of both requests, got %d", meter.Tokens())
+	}
+
+	if _, err := service.Complete(context.Background(), "system", "prompt"); err != nil || meter.Tokens() != 1824 {
+		t.Errorf("Expected requests without the meter's context not to be metered, got %d (%v)", meter.Tokens(), err)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 👥 SHADOW MODEL EVALUATION
+// =============================================================================
+
+const (
+	shadowDefaultDays  = 7
+	shadowMaxDays      = 90
+	shadowDefaultLimit = 20
+	shadowMaxLimit     = 100
+)
+
+// getShadowReport compares the candidate models with the primary model over
+// the last `days` days
+func getShadowReport(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(shadowDefaultDays)))
+	if err != nil || days < 1 || days > shadowMaxDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and " + strconv.Itoa(shadowMaxDays)})
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	reports, err := services.ShadowComparisonReport(c.Request.Context(), db, since, llmService.Grounding().MinScore())
+	if err != nil {
+		log.Printf("❌ Shadow report failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build shadow report"})
+		return
+	}
+
+	candidate := ""
+	if shadow := llmService.Shadow(); shadow != nil {
+		candidate = shadow.Model()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"primary_model":   llmService.Model(),
+		"candidate_model": candidate,
+		"since":           since.UTC(),
+		"models":          reports,
+	})
+}
+
+// getShadowComparisons lists the latest answers of both models side by side
+func getShadowComparisons(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(shadowDefaultLimit)))
+	if err != nil || limit < 1 || limit > shadowMaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(shadowMaxLimit)})
+		return
+	}
+
+	comparisons, err := services.ListShadowComparisons(c.Request.Context(), db, strings.TrimSpace(c.Query("model")), limit)
+	if err != nil {
+		log.Printf("❌ Listing shadow comparisons failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shadow comparisons"})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparisons)
+}
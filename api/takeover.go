@@ -0,0 +1,757 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 🙋 LIVE OWNER TAKEOVER
+// =============================================================================
+
+const (
+	// Live session stream event types
+	takeoverEventSessions = "sessions"
+	takeoverEventMessage  = "message"
+	takeoverEventClaimed  = "claimed"
+	takeoverEventReleased = "released"
+
+	takeoverStreamBuffer    = 64
+	takeoverStreamKeepalive = 30 * time.Second
+	takeoverSweepInterval   = 30 * time.Second
+	ownerReplyMaxLength     = 4000
+
+	// Redis keys shared by every replica
+	takeoverClaimsKey    = "chat:takeover:claims" // session ID -> claim expiry (unix ms)
+	takeoverActiveKey    = "chat:takeover:active" // session ID -> last activity (unix ms)
+	takeoverLivePrefix   = "chat:takeover:live:"  // client IP and WebSocket visitor count
+	takeoverPendingKey   = "chat:takeover:pending:"
+	takeoverChannel      = "chat:takeover:events"
+	takeoverRedisTimeout = 2 * time.Second
+)
+
+// takeoverExtendScript restarts the timeout of a claim that has not expired
+var takeoverExtendScript = redis.NewScript(`
+local expires = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not expires or tonumber(expires) <= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+return 1
+`)
+
+// takeoverExpireScript removes the claims that timed out and returns their
+// sessions, so only one replica announces each release
+var takeoverExpireScript = redis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if #expired > 0 then
+	redis.call('ZREM', KEYS[1], unpack(expired))
+end
+return expired
+`)
+
+var chatTakeover *takeoverHub
+
+func initChatTakeover() {
+	if redisClient == nil {
+		log.Printf("⚠️ Live takeover disabled: Redis not available")
+		return
+	}
+
+	claimTimeout, err := strconv.Atoi(getEnv("CHAT_TAKEOVER_TIMEOUT_MINUTES", "10"))
+	if err != nil || claimTimeout <= 0 {
+		claimTimeout = 10
+	}
+	activeWindow, err := strconv.Atoi(getEnv("CHAT_ACTIVE_SESSION_MINUTES", "30"))
+	if err != nil || activeWindow <= 0 {
+		activeWindow = 30
+	}
+
+	chatTakeover = newTakeoverHub(redisClient, time.Duration(claimTimeout)*time.Minute, time.Duration(activeWindow)*time.Minute)
+	go chatTakeover.run(context.Background())
+
+	log.Printf("🙋 Live takeover initialized (claims expire after %d minutes without a reply)", claimTimeout)
+}
+
+// takeoverHub tracks the active chat sessions, streams their messages to the
+// owner and routes the messages of claimed sessions between visitor and owner.
+// Claims and session activity live in Redis and events are fanned out over
+// Redis pub/sub, so a claim holds on every replica.
+type takeoverHub struct {
+	redis        *redis.Client
+	claimTimeout time.Duration
+	activeWindow time.Duration
+
+	// Owner streams and WebSocket visitors connected to this replica
+	mu       sync.Mutex
+	visitors map[string]map[*chatWSClient]struct{}
+	streams  map[chan TakeoverEvent]struct{}
+}
+
+// takeoverBroadcast is an event sent to every replica; the notice goes to the
+// WebSocket visitors of the session
+type takeoverBroadcast struct {
+	Event  TakeoverEvent  `json:"event"`
+	Notice *ChatWSMessage `json:"notice,omitempty"`
+}
+
+func newTakeoverHub(rdb *redis.Client, claimTimeout, activeWindow time.Duration) *takeoverHub {
+	return &takeoverHub{
+		redis:        rdb,
+		claimTimeout: claimTimeout,
+		activeWindow: activeWindow,
+		visitors:     make(map[string]map[*chatWSClient]struct{}),
+		streams:      make(map[chan TakeoverEvent]struct{}),
+	}
+}
+
+// run delivers the events of every replica and releases expired claims until
+// ctx is done
+func (h *takeoverHub) run(ctx context.Context) {
+	h.listen(ctx)
+
+	ticker := time.NewTicker(takeoverSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.sweep(ctx, now)
+		}
+	}
+}
+
+// listen subscribes to the takeover channel and delivers its events to the
+// owner streams and visitors of this replica. It returns once the
+// subscription is confirmed; go-redis resubscribes after reconnects.
+func (h *takeoverHub) listen(ctx context.Context) {
+	pubsub := h.redis.Subscribe(ctx, takeoverChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		log.Printf("⚠️ Live takeover subscription failed, retrying in the background: %v", err)
+	}
+
+	go func() {
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message := <-messages:
+				var broadcast takeoverBroadcast
+				if err := json.Unmarshal([]byte(message.Payload), &broadcast); err != nil {
+					log.Printf("⚠️ Invalid live takeover event: %v", err)
+					continue
+				}
+				h.deliver(broadcast)
+			}
+		}
+	}()
+}
+
+// sweep hands sessions whose claim timed out back to the bot, keeps the
+// sessions with visitors on this replica listed and drops the idle ones
+func (h *takeoverHub) sweep(ctx context.Context, now time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, takeoverRedisTimeout)
+	defer cancel()
+
+	expired, err := takeoverExpireScript.Run(ctx, h.redis, []string{takeoverClaimsKey}, now.UnixMilli()).StringSlice()
+	if err != nil {
+		log.Printf("⚠️ Failed to expire live takeover claims: %v", err)
+	}
+	for _, sessionID := range expired {
+		h.announceRelease(ctx, sessionID, "timeout")
+	}
+
+	pipe := h.redis.Pipeline()
+	h.mu.Lock()
+	for sessionID, visitors := range h.visitors {
+		if len(visitors) > 0 {
+			pipe.ZAdd(ctx, takeoverActiveKey, redis.Z{Score: float64(now.UnixMilli()), Member: sessionID})
+			pipe.Expire(ctx, takeoverLivePrefix+sessionID, h.activeWindow)
+		}
+	}
+	h.mu.Unlock()
+	pipe.ZRemRangeByScore(ctx, takeoverActiveKey, "-inf", strconv.FormatInt(now.Add(-h.activeWindow).UnixMilli(), 10))
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("⚠️ Failed to sweep live sessions: %v", err)
+	}
+}
+
+// touch marks a session active, recording the client IP when it is known
+func (h *takeoverHub) touch(ctx context.Context, sessionID, clientIP string, visitorDelta int64) {
+	liveKey := takeoverLivePrefix + sessionID
+
+	pipe := h.redis.TxPipeline()
+	pipe.ZAdd(ctx, takeoverActiveKey, redis.Z{Score: float64(time.Now().UnixMilli()), Member: sessionID})
+	if clientIP != "" {
+		pipe.HSet(ctx, liveKey, "client_ip", clientIP)
+	}
+	if visitorDelta != 0 {
+		pipe.HIncrBy(ctx, liveKey, "visitors", visitorDelta)
+	}
+	pipe.Expire(ctx, liveKey, h.activeWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("⚠️ Failed to record activity of chat session %s: %v", sessionID, err)
+	}
+}
+
+// observe marks a session active and streams its new messages to the owner
+func (h *takeoverHub) observe(ctx context.Context, sessionID, clientIP string, messages ...services.SessionMessage) {
+	if h == nil || sessionID == "" {
+		return
+	}
+
+	h.touch(ctx, sessionID, clientIP, 0)
+	for i := range messages {
+		h.broadcast(ctx, TakeoverEvent{Type: takeoverEventMessage, SessionID: sessionID, Message: &messages[i]}, nil)
+	}
+}
+
+// attach registers a WebSocket connection of a session so owner replies reach it
+func (h *takeoverHub) attach(ctx context.Context, sessionID string, client *chatWSClient) {
+	if h == nil || !services.ValidSessionID(sessionID) {
+		return
+	}
+
+	h.mu.Lock()
+	visitors, ok := h.visitors[sessionID]
+	if !ok {
+		visitors = make(map[*chatWSClient]struct{})
+		h.visitors[sessionID] = visitors
+	}
+	_, attached := visitors[client]
+	visitors[client] = struct{}{}
+	h.mu.Unlock()
+
+	if !attached {
+		h.touch(ctx, sessionID, client.clientIP, 1)
+	}
+}
+
+// detach removes a WebSocket connection from its session
+func (h *takeoverHub) detach(ctx context.Context, sessionID string, client *chatWSClient) {
+	if h == nil || sessionID == "" {
+		return
+	}
+
+	h.mu.Lock()
+	_, attached := h.visitors[sessionID][client]
+	delete(h.visitors[sessionID], client)
+	if len(h.visitors[sessionID]) == 0 {
+		delete(h.visitors, sessionID)
+	}
+	h.mu.Unlock()
+
+	if attached {
+		h.touch(ctx, sessionID, "", -1)
+	}
+}
+
+// claimed reports whether the owner currently answers a session. The bot
+// answers when Redis cannot tell.
+func (h *takeoverHub) claimed(ctx context.Context, sessionID string) bool {
+	if h == nil || sessionID == "" {
+		return false
+	}
+
+	expires, err := h.redis.ZScore(ctx, takeoverClaimsKey, sessionID).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("⚠️ Failed to check the takeover claim of session %s: %v", sessionID, err)
+		}
+		return false
+	}
+	return int64(expires) > time.Now().UnixMilli()
+}
+
+// claim routes the messages of a session to the owner until it is released
+// or the owner stays silent for the claim timeout
+func (h *takeoverHub) claim(ctx context.Context, sessionID string) (time.Time, error) {
+	expires := time.Now().Add(h.claimTimeout)
+	if err := h.redis.ZAdd(ctx, takeoverClaimsKey, redis.Z{Score: float64(expires.UnixMilli()), Member: sessionID}).Err(); err != nil {
+		return time.Time{}, err
+	}
+	h.touch(ctx, sessionID, "", 0)
+
+	log.Printf("🙋 Chat session %s claimed by the owner", sessionID)
+	h.broadcast(ctx, TakeoverEvent{Type: takeoverEventClaimed, SessionID: sessionID},
+		&ChatWSMessage{Type: chatWSTypeNotice, Content: "Bruno joined the conversation", SessionID: sessionID})
+	return expires, nil
+}
+
+// reply delivers an owner message to the visitors of a claimed session and
+// restarts the claim timeout, returning false when the session is not claimed.
+// Visitors without a WebSocket connection get the message from the pending
+// queue on their next HTTP request.
+func (h *takeoverHub) reply(ctx context.Context, sessionID string, message services.SessionMessage) (bool, error) {
+	now := time.Now()
+	extended, err := takeoverExtendScript.Run(ctx, h.redis, []string{takeoverClaimsKey},
+		sessionID, now.UnixMilli(), now.Add(h.claimTimeout).UnixMilli()).Int()
+	if err != nil || extended == 0 {
+		return false, err
+	}
+	h.touch(ctx, sessionID, "", 0)
+
+	visitors, err := h.redis.HGet(ctx, takeoverLivePrefix+sessionID, "visitors").Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("⚠️ Failed to count the visitors of session %s: %v", sessionID, err)
+	}
+	if visitors <= 0 {
+		if err := h.queue(ctx, sessionID, message); err != nil {
+			log.Printf("⚠️ Failed to queue owner reply for session %s: %v", sessionID, err)
+		}
+	}
+
+	h.broadcast(ctx, TakeoverEvent{Type: takeoverEventMessage, SessionID: sessionID, Message: &message},
+		&ChatWSMessage{Type: chatWSTypeOwner, Content: message.Content, SessionID: sessionID})
+	return true, nil
+}
+
+// release hands a claimed session back to the bot, returning false when it was not claimed
+func (h *takeoverHub) release(ctx context.Context, sessionID, reason string) (bool, error) {
+	removed, err := h.redis.ZRem(ctx, takeoverClaimsKey, sessionID).Result()
+	if err != nil || removed == 0 {
+		return false, err
+	}
+	h.announceRelease(ctx, sessionID, reason)
+	return true, nil
+}
+
+// announceRelease tells the owner and the visitors that the bot answers again
+func (h *takeoverHub) announceRelease(ctx context.Context, sessionID, reason string) {
+	log.Printf("🙋 Chat session %s returned to the bot (%s)", sessionID, reason)
+	h.broadcast(ctx, TakeoverEvent{Type: takeoverEventReleased, SessionID: sessionID, Reason: reason},
+		&ChatWSMessage{Type: chatWSTypeNotice, Content: "The assistant is back", SessionID: sessionID})
+}
+
+// queue keeps an owner reply for a visitor without a WebSocket connection
+func (h *takeoverHub) queue(ctx context.Context, sessionID string, message services.SessionMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	pipe := h.redis.TxPipeline()
+	pipe.RPush(ctx, takeoverPendingKey+sessionID, data)
+	pipe.Expire(ctx, takeoverPendingKey+sessionID, h.activeWindow)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// pending removes and returns the owner replies queued for a session
+func (h *takeoverHub) pending(ctx context.Context, sessionID string) ([]services.SessionMessage, error) {
+	messages := []services.SessionMessage{}
+	if h == nil || sessionID == "" {
+		return messages, nil
+	}
+
+	pipe := h.redis.TxPipeline()
+	queued := pipe.LRange(ctx, takeoverPendingKey+sessionID, 0, -1)
+	pipe.Del(ctx, takeoverPendingKey+sessionID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, data := range queued.Val() {
+		var message services.SessionMessage
+		if err := json.Unmarshal([]byte(data), &message); err == nil {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// broadcast sends an event to the owner streams and visitors of every replica
+func (h *takeoverHub) broadcast(ctx context.Context, event TakeoverEvent, notice *ChatWSMessage) {
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	data, err := json.Marshal(takeoverBroadcast{Event: event, Notice: notice})
+	if err != nil {
+		log.Printf("⚠️ Failed to encode %s event: %v", event.Type, err)
+		return
+	}
+	if err := h.redis.Publish(ctx, takeoverChannel, data).Err(); err != nil {
+		log.Printf("⚠️ Failed to publish %s event: %v", event.Type, err)
+	}
+}
+
+// deliver hands a broadcast to the owner streams and the visitors of its
+// session on this replica; slow streams miss events rather than holding up
+// the chat
+func (h *takeoverHub) deliver(broadcast takeoverBroadcast) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for events := range h.streams {
+		select {
+		case events <- broadcast.Event:
+		default:
+			log.Printf("⚠️ Live session stream is full, dropping %s event", broadcast.Event.Type)
+		}
+	}
+
+	if broadcast.Notice != nil {
+		for client := range h.visitors[broadcast.Event.SessionID] {
+			client.emit(*broadcast.Notice)
+		}
+	}
+}
+
+// subscribe opens a stream of session events for the owner
+func (h *takeoverHub) subscribe() chan TakeoverEvent {
+	events := make(chan TakeoverEvent, takeoverStreamBuffer)
+
+	h.mu.Lock()
+	h.streams[events] = struct{}{}
+	h.mu.Unlock()
+	return events
+}
+
+// unsubscribe closes a stream opened with subscribe
+func (h *takeoverHub) unsubscribe(events chan TakeoverEvent) {
+	h.mu.Lock()
+	delete(h.streams, events)
+	h.mu.Unlock()
+}
+
+// snapshot lists the active sessions, most recent first, with their transcripts
+func (h *takeoverHub) snapshot(ctx context.Context) ([]LiveChatSession, error) {
+	active, err := h.redis.ZRevRangeWithScores(ctx, takeoverActiveKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	claims, err := h.redis.ZRangeWithScores(ctx, takeoverClaimsKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	claimExpires := make(map[string]int64, len(claims))
+	for _, claim := range claims {
+		claimExpires[claim.Member.(string)] = int64(claim.Score)
+	}
+
+	now := time.Now()
+	sessions := make([]LiveChatSession, 0, len(active))
+	for _, entry := range active {
+		id := entry.Member.(string)
+		live := LiveChatSession{
+			ID:           id,
+			LastActivity: time.UnixMilli(int64(entry.Score)).UTC(),
+			Messages:     []services.SessionMessage{},
+		}
+		if fields, err := h.redis.HMGet(ctx, takeoverLivePrefix+id, "client_ip", "visitors").Result(); err == nil {
+			live.ClientIP, _ = fields[0].(string)
+			if visitors, ok := fields[1].(string); ok {
+				live.Visitors, _ = strconv.Atoi(visitors)
+			}
+		}
+		if expires, ok := claimExpires[id]; ok && expires > now.UnixMilli() {
+			expiresAt := time.UnixMilli(expires).UTC()
+			live.Claimed = true
+			live.ClaimExpiresAt = &expiresAt
+		}
+		sessions = append(sessions, live)
+	}
+
+	if llmService != nil {
+		for i := range sessions {
+			if stored, err := llmService.Sessions().Get(ctx, sessions[i].ID); err == nil && stored.Messages != nil {
+				sessions[i].Messages = stored.Messages
+			}
+		}
+	}
+	return sessions, nil
+}
+
+// =============================================================================
+// 💬 VISITOR MESSAGES
+// =============================================================================
+
+// forwardToOwner stores a visitor message of a claimed session and streams it
+// to the owner instead of asking the model. Guardrails still apply.
+func forwardToOwner(ctx context.Context, sessionID, clientIP, content string) *services.GuardrailViolation {
+	if violation := services.CheckGuardrails(content); violation != nil {
+		return violation
+	}
+
+	message := services.SessionMessage{Role: "user", Content: content, Timestamp: time.Now().UTC()}
+	if err := llmService.Sessions().AppendMessages(ctx, sessionID, message); err != nil {
+		log.Printf("⚠️ Failed to store visitor message for claimed session %s: %v", sessionID, err)
+	}
+	chatTakeover.observe(ctx, sessionID, clientIP, message)
+	return nil
+}
+
+// observeChatExchange streams a question answered by the bot to the owner
+func observeChatExchange(ctx context.Context, clientIP string, request services.ChatRequest, response *services.ChatResponse, startTime time.Time) {
+	chatTakeover.observe(ctx, response.SessionID, clientIP,
+		services.SessionMessage{Role: "user", Content: request.Message, Timestamp: startTime.UTC()},
+		services.SessionMessage{Role: "assistant", Content: response.Response, Sources: response.Sources, Timestamp: time.Now().UTC()},
+	)
+}
+
+// takeOwnerReplies returns the owner replies queued for an HTTP visitor who
+// sends the session token (X-Session-Token header); other requests get none
+func takeOwnerReplies(c *gin.Context, sessionID string) []services.SessionMessage {
+	token := c.GetHeader("X-Session-Token")
+	if chatTakeover == nil || token == "" || !services.ValidSessionID(sessionID) {
+		return nil
+	}
+
+	ctx := c.Request.Context()
+	if valid, err := llmService.Sessions().VerifyToken(ctx, sessionID, token); err != nil || !valid {
+		return nil
+	}
+	replies, err := chatTakeover.pending(ctx, sessionID)
+	if err != nil {
+		log.Printf("⚠️ Failed to load owner replies for session %s: %v", sessionID, err)
+		return nil
+	}
+	return replies
+}
+
+// getOwnerReplies lets HTTP visitors poll for the owner replies of a claimed
+// session. Like the transcript export it needs the session token, and unknown
+// sessions and wrong tokens get the same 404.
+func getOwnerReplies(c *gin.Context) {
+	if chatTakeover == nil || llmService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+
+	sessionID := c.Param("id")
+	if !services.ValidSessionID(sessionID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+	token := c.GetHeader("X-Session-Token")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	valid, err := llmService.Sessions().VerifyToken(ctx, sessionID, token)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session store not available"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	replies, err := chatTakeover.pending(ctx, sessionID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"claimed":    chatTakeover.claimed(ctx, sessionID),
+		"messages":   replies,
+	})
+}
+
+// =============================================================================
+// 🛡️ OWNER ENDPOINTS
+// =============================================================================
+
+// getLiveChatSessions lists the active chat sessions with their transcripts
+func getLiveChatSessions(c *gin.Context) {
+	if chatTakeover == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+	sessions, err := chatTakeover.snapshot(c.Request.Context())
+	if err != nil {
+		log.Printf("❌ Failed to list live chat sessions: %v", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// streamLiveChatSessions streams the active sessions and their new messages
+// as server-sent events, starting with a snapshot of the active sessions
+func streamLiveChatSessions(c *gin.Context) {
+	if chatTakeover == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+
+	sessions, err := chatTakeover.snapshot(c.Request.Context())
+	if err != nil {
+		log.Printf("❌ Failed to list live chat sessions: %v", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+
+	events := chatTakeover.subscribe()
+	defer chatTakeover.unsubscribe(events)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	writeEvent := func(event TakeoverEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	if err := writeEvent(TakeoverEvent{
+		Type:      takeoverEventSessions,
+		Sessions:  sessions,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return
+	}
+
+	keepalive := time.NewTicker(takeoverStreamKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event := <-events:
+			if err := writeEvent(event); err != nil {
+				return
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(c.Writer, ": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// claimLiveChatSession routes the visitor messages of a session to the owner
+func claimLiveChatSession(c *gin.Context) {
+	sessionID, ok := liveSessionParam(c)
+	if !ok {
+		return
+	}
+
+	_, err := llmService.Sessions().Get(c.Request.Context(), sessionID)
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Session store not available"})
+		return
+	}
+
+	expires, err := chatTakeover.claim(c.Request.Context(), sessionID)
+	if err != nil {
+		log.Printf("❌ Failed to claim chat session %s: %v", sessionID, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"session_id": sessionID, "claimed": true, "claim_expires_at": expires.UTC()})
+}
+
+// replyLiveChatSession sends an owner reply to the visitor of a claimed session
+func replyLiveChatSession(c *gin.Context) {
+	sessionID, ok := liveSessionParam(c)
+	if !ok {
+		return
+	}
+
+	var request OwnerReplyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	content := strings.TrimSpace(request.Content)
+	if content == "" || len(content) > ownerReplyMaxLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content must be between 1 and " + strconv.Itoa(ownerReplyMaxLength) + " characters"})
+		return
+	}
+
+	message := services.SessionMessage{
+		Role:      "assistant",
+		Content:   content,
+		Author:    services.SessionAuthorOwner,
+		Timestamp: time.Now().UTC(),
+	}
+	replied, err := chatTakeover.reply(c.Request.Context(), sessionID, message)
+	if err != nil {
+		log.Printf("❌ Failed to deliver owner reply for session %s: %v", sessionID, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+	if !replied {
+		c.JSON(http.StatusConflict, gin.H{"error": "Session is not claimed"})
+		return
+	}
+	if err := llmService.Sessions().AppendMessages(c.Request.Context(), sessionID, message); err != nil {
+		log.Printf("⚠️ Failed to store owner reply for session %s: %v", sessionID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session_id": sessionID, "message": message})
+}
+
+// releaseLiveChatSession hands a claimed session back to the bot
+func releaseLiveChatSession(c *gin.Context) {
+	sessionID, ok := liveSessionParam(c)
+	if !ok {
+		return
+	}
+
+	released, err := chatTakeover.release(c.Request.Context(), sessionID, "released")
+	if err != nil {
+		log.Printf("❌ Failed to release chat session %s: %v", sessionID, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return
+	}
+	if !released {
+		c.JSON(http.StatusConflict, gin.H{"error": "Session is not claimed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"session_id": sessionID, "claimed": false})
+}
+
+// liveSessionParam validates the session ID of a takeover request
+func liveSessionParam(c *gin.Context) (string, bool) {
+	if chatTakeover == nil || llmService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live takeover not available"})
+		return "", false
+	}
+
+	sessionID := c.Param("id")
+	if !services.ValidSessionID(sessionID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return "", false
+	}
+	return sessionID, true
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bruno-api/services"
+)
+
+const testSessionID = "sess_0123456789abcdef0123456789abcdef"
+
+func newTestVisitor() *chatWSClient {
+	return &chatWSClient{
+		send:     make(chan ChatWSMessage, 8),
+		done:     make(chan struct{}),
+		clientIP: "203.0.113.7",
+	}
+}
+
+// newTestTakeoverHubs returns replicas sharing one miniredis, each listening
+// for the events of the others
+func newTestTakeoverHubs(t *testing.T, replicas int) ([]*takeoverHub, *redis.Client) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	hubs := make([]*takeoverHub, replicas)
+	for i := range hubs {
+		hubs[i] = newTakeoverHub(rdb, time.Minute, time.Hour)
+		hubs[i].listen(ctx)
+	}
+	return hubs, rdb
+}
+
+// receive waits for the next value of a channel
+func receive[T any](t *testing.T, values <-chan T) T {
+	t.Helper()
+	select {
+	case value := <-values:
+		return value
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a takeover event")
+		var zero T
+		return zero
+	}
+}
+
+func TestTakeoverClaimReplyRelease(t *testing.T) {
+	hubs, _ := newTestTakeoverHubs(t, 1)
+	hub := hubs[0]
+	ctx := context.Background()
+
+	visitor := newTestVisitor()
+	hub.attach(ctx, testSessionID, visitor)
+	events := hub.subscribe()
+	defer hub.unsubscribe(events)
+
+	assert.False(t, hub.claimed(ctx, testSessionID))
+	replied, err := hub.reply(ctx, testSessionID, services.SessionMessage{Content: "Hi"})
+	require.NoError(t, err)
+	assert.False(t, replied, "unclaimed sessions take no owner replies")
+
+	_, err = hub.claim(ctx, testSessionID)
+	require.NoError(t, err)
+	require.True(t, hub.claimed(ctx, testSessionID))
+	assert.Equal(t, chatWSTypeNotice, receive(t, visitor.send).Type)
+	assert.Equal(t, takeoverEventClaimed, receive(t, events).Type)
+
+	replied, err = hub.reply(ctx, testSessionID, services.SessionMessage{Role: "assistant", Content: "Hi, Bruno here"})
+	require.NoError(t, err)
+	require.True(t, replied)
+	reply := receive(t, visitor.send)
+	assert.Equal(t, chatWSTypeOwner, reply.Type)
+	assert.Equal(t, "Hi, Bruno here", reply.Content)
+	assert.Equal(t, "Hi, Bruno here", receive(t, events).Message.Content)
+
+	released, err := hub.release(ctx, testSessionID, "released")
+	require.NoError(t, err)
+	require.True(t, released)
+	assert.False(t, hub.claimed(ctx, testSessionID))
+	assert.Equal(t, chatWSTypeNotice, receive(t, visitor.send).Type)
+	assert.Equal(t, takeoverEventReleased, receive(t, events).Type)
+
+	released, err = hub.release(ctx, testSessionID, "released")
+	require.NoError(t, err)
+	assert.False(t, released, "released sessions cannot be released again")
+}
+
+func TestTakeoverAcrossReplicas(t *testing.T) {
+	hubs, _ := newTestTakeoverHubs(t, 2)
+	ctx := context.Background()
+
+	// The visitor and the owner stream are on one replica, the owner acts on the other
+	visitor := newTestVisitor()
+	hubs[1].attach(ctx, testSessionID, visitor)
+	events := hubs[1].subscribe()
+	defer hubs[1].unsubscribe(events)
+
+	_, err := hubs[0].claim(ctx, testSessionID)
+	require.NoError(t, err)
+	assert.True(t, hubs[1].claimed(ctx, testSessionID), "claims hold on every replica")
+	assert.Equal(t, takeoverEventClaimed, receive(t, events).Type)
+	assert.Equal(t, chatWSTypeNotice, receive(t, visitor.send).Type)
+
+	replied, err := hubs[0].reply(ctx, testSessionID, services.SessionMessage{Role: "assistant", Content: "Hi, Bruno here"})
+	require.NoError(t, err)
+	require.True(t, replied)
+	assert.Equal(t, "Hi, Bruno here", receive(t, visitor.send).Content)
+
+	pending, err := hubs[0].pending(ctx, testSessionID)
+	require.NoError(t, err)
+	assert.Empty(t, pending, "replies to WebSocket visitors are not queued")
+
+	sessions, err := hubs[0].snapshot(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Claimed)
+	assert.Equal(t, 1, sessions[0].Visitors)
+	assert.Equal(t, "203.0.113.7", sessions[0].ClientIP)
+}
+
+func TestTakeoverQueuesRepliesForHTTPVisitors(t *testing.T) {
+	hubs, _ := newTestTakeoverHubs(t, 1)
+	hub := hubs[0]
+	ctx := context.Background()
+
+	hub.observe(ctx, testSessionID, "203.0.113.7", services.SessionMessage{Role: "user", Content: "Hello?"})
+	_, err := hub.claim(ctx, testSessionID)
+	require.NoError(t, err)
+
+	replied, err := hub.reply(ctx, testSessionID, services.SessionMessage{Role: "assistant", Content: "Hi, Bruno here"})
+	require.NoError(t, err)
+	require.True(t, replied)
+
+	pending, err := hub.pending(ctx, testSessionID)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "Hi, Bruno here", pending[0].Content)
+
+	pending, err = hub.pending(ctx, testSessionID)
+	require.NoError(t, err)
+	assert.Empty(t, pending, "queued replies are delivered once")
+}
+
+func TestTakeoverSweep(t *testing.T) {
+	hubs, _ := newTestTakeoverHubs(t, 2)
+	ctx := context.Background()
+	events := hubs[0].subscribe()
+	defer hubs[0].unsubscribe(events)
+
+	_, err := hubs[0].claim(ctx, testSessionID)
+	require.NoError(t, err)
+	assert.Equal(t, takeoverEventClaimed, receive(t, events).Type)
+	hubs[0].observe(ctx, "sess_idle", "203.0.113.8")
+
+	hubs[0].sweep(ctx, time.Now().Add(2*time.Minute))
+	hubs[1].sweep(ctx, time.Now().Add(2*time.Minute))
+	assert.False(t, hubs[1].claimed(ctx, testSessionID), "claims expire without owner replies")
+	released := receive(t, events)
+	assert.Equal(t, takeoverEventReleased, released.Type)
+	assert.Equal(t, "timeout", released.Reason)
+	select {
+	case event := <-events:
+		t.Errorf("Expected one release for the expired claim, got another %s event", event.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	sessions, err := hubs[0].snapshot(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2, "recent sessions stay listed")
+
+	hubs[0].sweep(ctx, time.Now().Add(2*time.Hour))
+	sessions, err = hubs[0].snapshot(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "idle sessions are forgotten")
+}
+
+func TestTakeoverAttachIgnoresInvalidSessions(t *testing.T) {
+	hubs, _ := newTestTakeoverHubs(t, 1)
+	hubs[0].attach(context.Background(), "not-a-session", newTestVisitor())
+	assert.Empty(t, hubs[0].visitors)
+
+	var disabled *takeoverHub
+	assert.False(t, disabled.claimed(context.Background(), testSessionID), "a nil hub never routes to the owner")
+}
+
+func TestGetOwnerRepliesRequiresSessionToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hubs, rdb := newTestTakeoverHubs(t, 1)
+
+	previousHub, previousLLM := chatTakeover, llmService
+	chatTakeover, llmService = hubs[0], services.NewLLMService(nil, rdb)
+	t.Cleanup(func() { chatTakeover, llmService = previousHub, previousLLM })
+
+	ctx := context.Background()
+	session, err := llmService.Sessions().GetOrCreate(ctx, "")
+	require.NoError(t, err)
+	_, err = chatTakeover.claim(ctx, session.ID)
+	require.NoError(t, err)
+	_, err = chatTakeover.reply(ctx, session.ID, services.SessionMessage{Role: "assistant", Content: "Hi, Bruno here"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/api/v1/chat/sessions/:id/replies", getOwnerReplies)
+	poll := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/chat/sessions/"+session.ID+"/replies", nil)
+		if token != "" {
+			req.Header.Set("X-Session-Token", token)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, poll("").Code)
+	assert.Equal(t, http.StatusNotFound, poll("wrong-token").Code)
+
+	w := poll(session.Token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Claimed  bool                      `json:"claimed"`
+		Messages []services.SessionMessage `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.True(t, body.Claimed)
+	require.Len(t, body.Messages, 1)
+	assert.Equal(t, "Hi, Bruno here", body.Messages[0].Content)
+}
@@ -1,5 +1,12 @@
 package main
 
+import (
+	"time"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
 // =============================================================================
 // 📋 DATA STRUCTURES
 // =============================================================================
@@ -68,3 +75,215 @@ type Experience struct {
 	Order        int      `json:"order"`
 	Active       bool     `json:"active"`
 }
+
+// 🔌 ChatWSMessage represents a message exchanged over the chat WebSocket
+type ChatWSMessage struct {
+	Type       string                 `json:"type"`
+	Content    string                 `json:"content,omitempty"`
+	Context    string                 `json:"context,omitempty"`
+	SessionID  string                 `json:"session_id,omitempty"`
+	Token      string                 `json:"token,omitempty"` // session token, required with a session_id the connection is not bound to
+	Active     bool                   `json:"active,omitempty"`
+	Response   *services.ChatResponse `json:"response,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Quota      *services.QuotaStatus  `json:"quota,omitempty"`
+	RetryAfter int                    `json:"retry_after,omitempty"` // seconds, after a quota error
+	Timestamp  string                 `json:"timestamp,omitempty"`
+}
+
+// 🙋 LiveChatSession represents an active chat session shown to the owner
+type LiveChatSession struct {
+	ID             string                    `json:"id"`
+	ClientIP       string                    `json:"client_ip"`
+	LastActivity   time.Time                 `json:"last_activity"`
+	Visitors       int                       `json:"visitors"`
+	Claimed        bool                      `json:"claimed"`
+	ClaimExpiresAt *time.Time                `json:"claim_expires_at,omitempty"`
+	Messages       []services.SessionMessage `json:"messages"`
+}
+
+// 🙋 TakeoverEvent represents an update on the live chat sessions stream
+type TakeoverEvent struct {
+	Type      string                   `json:"type"`
+	SessionID string                   `json:"session_id,omitempty"`
+	Message   *services.SessionMessage `json:"message,omitempty"`
+	Sessions  []LiveChatSession        `json:"sessions,omitempty"`
+	Reason    string                   `json:"reason,omitempty"`
+	Timestamp string                   `json:"timestamp"`
+}
+
+// 🙋 OwnerReplyRequest represents a reply of the owner to a claimed session
+type OwnerReplyRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// 🧩 OpenAIChatCompletionRequest represents an OpenAI-compatible chat completion request
+type OpenAIChatCompletionRequest struct {
+	Model    string              `json:"model"`
+	Messages []OpenAIChatMessage `json:"messages" binding:"required"`
+	Stream   bool                `json:"stream"`
+	User     string              `json:"user,omitempty"`
+}
+
+// 🧩 OpenAIChatMessage represents a message in the OpenAI chat format
+type OpenAIChatMessage struct {
+	Role    string        `json:"role"`
+	Content OpenAIContent `json:"content"`
+}
+
+// 🧩 OpenAIContent holds message content sent either as a string or as text parts
+type OpenAIContent string
+
+// 🧩 OpenAIDelta represents an incremental message in a streamed completion
+type OpenAIDelta struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// 🧩 OpenAIChoice represents a completion choice
+type OpenAIChoice struct {
+	Index        int                `json:"index"`
+	Message      *OpenAIChatMessage `json:"message,omitempty"`
+	Delta        *OpenAIDelta       `json:"delta,omitempty"`
+	FinishReason *string            `json:"finish_reason"`
+}
+
+// 🧩 OpenAIChatCompletionResponse represents a completion or a streamed completion chunk
+type OpenAIChatCompletionResponse struct {
+	ID      string         `json:"id"`
+	Object  string         `json:"object"`
+	Created int64          `json:"created"`
+	Model   string         `json:"model"`
+	Choices []OpenAIChoice `json:"choices"`
+}
+
+// 🧩 OpenAIModel represents a model listed by the OpenAI-compatible API
+type OpenAIModel struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// 🎯 MatchRequest represents a job description submitted for fit analysis
+type MatchRequest struct {
+	JobDescription string `json:"job_description" binding:"required"`
+	Summary        *bool  `json:"summary,omitempty"`
+}
+
+// 📄 ResumeRequest represents a job description submitted for a tailored resume
+type ResumeRequest struct {
+	JobDescription string `json:"job_description" binding:"required"`
+	Rewrite        bool   `json:"rewrite,omitempty"`
+}
+
+// 🧭 ChatIntent represents an intent of the chat routing taxonomy
+type ChatIntent struct {
+	ID          int                 `json:"id"`
+	Name        string              `json:"name" binding:"required"`
+	Description string              `json:"description"`
+	Active      *bool               `json:"active,omitempty"`
+	Keywords    []ChatIntentKeyword `json:"keywords"`
+	Derived     []string            `json:"derived"`
+}
+
+// 🔑 ChatIntentKeyword represents a keyword of an intent and its synonyms
+type ChatIntentKeyword struct {
+	ID       int      `json:"id"`
+	IntentID int      `json:"intent_id"`
+	Keyword  string   `json:"keyword" binding:"required"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// ❓ FAQEntry represents an approved answer returned without calling the LLM
+type FAQEntry struct {
+	ID        int       `json:"id"`
+	Question  string    `json:"question" binding:"required"`
+	Variants  []string  `json:"variants"`
+	Answer    string    `json:"answer" binding:"required"`
+	Active    *bool     `json:"active,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 📚 ContextDocument represents an uploaded document used as chatbot knowledge
+type ContextDocument struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	Filename  string    `json:"filename,omitempty"`
+	Content   string    `json:"content,omitempty"`
+	SizeBytes int       `json:"size_bytes"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 🤝 Lead represents contact details left by a visitor with hiring intent
+type Lead struct {
+	ID         int                       `json:"id"`
+	SessionID  string                    `json:"session_id"`
+	Name       string                    `json:"name"`
+	Email      string                    `json:"email"`
+	Company    string                    `json:"company,omitempty"`
+	Message    string                    `json:"message,omitempty"`
+	Status     string                    `json:"status"`
+	Transcript []services.SessionMessage `json:"transcript,omitempty"`
+	CreatedAt  time.Time                 `json:"created_at"`
+	UpdatedAt  time.Time                 `json:"updated_at"`
+}
+
+// 🤝 LeadRequest represents a lead submitted from the chat
+type LeadRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+	Name      string `json:"name" binding:"required"`
+	Email     string `json:"email" binding:"required"`
+	Company   string `json:"company"`
+	Message   string `json:"message"`
+}
+
+// 🤝 LeadStatusRequest represents a status change of a lead
+type LeadStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// 🔐 AdminUser represents an account allowed to change the portfolio
+type AdminUser struct {
+	ID          int        `json:"id"`
+	Username    string     `json:"username"`
+	Role        string     `json:"role"`
+	Active      bool       `json:"active"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// 🔐 LoginRequest represents admin credentials
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// 🔐 AdminUserRequest represents a new admin account; the role defaults to analyst
+type AdminUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role"`
+}
+
+// 🎭 AdminRoleRequest represents a role assignment
+type AdminRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// 🎭 AuthorizationAuditEntry represents a request denied by role-based access control
+type AuthorizationAuditEntry struct {
+	ID        int       `json:"id"`
+	UserID    *int      `json:"user_id,omitempty"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Resource  string    `json:"resource"`
+	Action    string    `json:"action"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	ClientIP  string    `json:"client_ip,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
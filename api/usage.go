@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	// 🤖 LLM services
+	"bruno-api/services"
+)
+
+// =============================================================================
+// 💰 MODEL USAGE
+// =============================================================================
+
+const (
+	usageDefaultDays     = 7
+	usageMaxDays         = 90
+	usageDefaultSessions = 10
+	usageMaxSessions     = 100
+)
+
+// getUsageReport reports the tokens and GPU time used over the last `days`
+// days per day, model and session, with today's usage against the budget
+func getUsageReport(c *gin.Context) {
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database not available"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(usageDefaultDays)))
+	if err != nil || days < 1 || days > usageMaxDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and " + strconv.Itoa(usageMaxDays)})
+		return
+	}
+	sessions, err := strconv.Atoi(c.DefaultQuery("sessions", strconv.Itoa(usageDefaultSessions)))
+	if err != nil || sessions < 1 || sessions > usageMaxSessions {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sessions must be between 1 and " + strconv.Itoa(usageMaxSessions)})
+		return
+	}
+
+	// Today counts as the first day
+	since := time.Now().UTC().AddDate(0, 0, 1-days)
+	report, err := services.LoadUsageReport(c.Request.Context(), db, since, sessions)
+	if err != nil {
+		log.Printf("❌ Usage report failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build usage report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"budget": llmService.Usage().Status(c.Request.Context()),
+		"usage":  report,
+	})
+}
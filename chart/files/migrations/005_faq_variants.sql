@@ -0,0 +1,4 @@
+-- Question variants for FAQ entries answered without the LLM
+-- Migration: 005_faq_variants.sql
+
+ALTER TABLE faq_entries ADD COLUMN IF NOT EXISTS variants TEXT[] DEFAULT '{}';
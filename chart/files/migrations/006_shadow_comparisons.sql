@@ -0,0 +1,23 @@
+-- Answers of a candidate model recorded next to the primary model's answers
+-- Migration: 006_shadow_comparisons.sql
+
+CREATE TABLE IF NOT EXISTS shadow_comparisons (
+    id SERIAL PRIMARY KEY,
+    request_id VARCHAR(100) NOT NULL,
+    question TEXT NOT NULL,
+    mode VARCHAR(50) NOT NULL DEFAULT 'default',
+    primary_model VARCHAR(100) NOT NULL,
+    primary_answer TEXT NOT NULL,
+    primary_latency_ms INTEGER NOT NULL,
+    primary_grounding_score REAL NOT NULL,
+    primary_ungrounded TEXT[] DEFAULT '{}',
+    candidate_model VARCHAR(100) NOT NULL,
+    candidate_answer TEXT,
+    candidate_latency_ms INTEGER,
+    candidate_grounding_score REAL,
+    candidate_ungrounded TEXT[] DEFAULT '{}',
+    candidate_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shadow_comparisons_model_created ON shadow_comparisons(candidate_model, created_at DESC);
@@ -0,0 +1,35 @@
+-- Contact details left by visitors with hiring intent, with their conversation
+-- Migration: 007_leads.sql
+
+CREATE TABLE IF NOT EXISTS leads (
+    id SERIAL PRIMARY KEY,
+    session_id VARCHAR(64) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(254) NOT NULL,
+    company VARCHAR(255),
+    message TEXT,
+    transcript JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'contacted', 'closed')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_leads_status_created ON leads(status, created_at DESC);
+
+DROP TRIGGER IF EXISTS update_leads_updated_at ON leads;
+CREATE TRIGGER update_leads_updated_at BEFORE UPDATE ON leads FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Hiring intent that triggers the lead capture offer in the chat
+INSERT INTO chat_intents (name, description) VALUES
+('hiring', 'Recruiting and hiring conversations; offers lead capture')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO chat_intent_keywords (intent_id, keyword)
+SELECT i.id, k.keyword
+FROM chat_intents i
+JOIN (VALUES
+    ('hiring', 'hiring'), ('hiring', 'recruiting'), ('hiring', 'recruiter'), ('hiring', 'recruitment'),
+    ('hiring', 'vacancy'), ('hiring', 'job offer'), ('hiring', 'job opening'), ('hiring', 'open role'),
+    ('hiring', 'interview'), ('hiring', 'headhunter'), ('hiring', 'join our team')
+) AS k(intent, keyword) ON k.intent = i.name
+ON CONFLICT (intent_id, keyword) DO NOTHING;
@@ -0,0 +1,17 @@
+-- Token and compute usage reported by Ollama, aggregated per day, session, model and role
+-- Migration: 008_llm_usage.sql
+
+CREATE TABLE IF NOT EXISTS llm_usage (
+    day DATE NOT NULL,
+    session_id VARCHAR(64) NOT NULL DEFAULT '',
+    model VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    requests INTEGER NOT NULL DEFAULT 0,
+    prompt_tokens BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    generation_ms BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (day, session_id, model, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_usage_day_model ON llm_usage(day, model);
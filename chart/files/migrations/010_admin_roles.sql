@@ -0,0 +1,24 @@
+-- Roles for admin accounts and the audit log of requests they were denied
+-- Migration: 010_admin_roles.sql
+
+-- Accounts created before roles existed keep full access as owners; new accounts default to analyst
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'owner';
+ALTER TABLE admin_users ALTER COLUMN role SET DEFAULT 'analyst';
+
+ALTER TABLE admin_users DROP CONSTRAINT IF EXISTS admin_users_role_check;
+ALTER TABLE admin_users ADD CONSTRAINT admin_users_role_check CHECK (role IN ('owner', 'editor', 'analyst'));
+
+CREATE TABLE IF NOT EXISTS authorization_audit_log (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES admin_users(id) ON DELETE SET NULL,
+    username VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    resource VARCHAR(50) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(500) NOT NULL,
+    client_ip VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_authorization_audit_log_created_at ON authorization_audit_log(created_at);
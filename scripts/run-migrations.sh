@@ -45,14 +45,14 @@ if [ -z "$DB_PASSWORD" ]; then
     exit 1
 fi
 
-MIGRATION_FILE="api/migrations/001_complete_schema.sql"
+MIGRATION_DIR="api/migrations"
 
 echo "📋 Database Configuration:"
 echo "  Host: $DB_HOST"
 echo "  Port: $DB_PORT"
 echo "  Database: $DB_NAME"
 echo "  User: $DB_USER"
-echo "  Migration File: $MIGRATION_FILE"
+echo "  Migrations: $MIGRATION_DIR"
 
 # Wait for database to be ready
 echo "⏳ Waiting for database to be ready..."
@@ -62,8 +62,10 @@ until PGPASSWORD=$DB_PASSWORD psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NA
 done
 echo "✅ Database is ready!"
 
-# Run the migration
-echo "🚀 Running migration..."
-PGPASSWORD=$DB_PASSWORD psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME -f $MIGRATION_FILE
+# Run the migrations in order
+for MIGRATION_FILE in $(ls $MIGRATION_DIR/*.sql | sort); do
+    echo "🚀 Running migration $(basename $MIGRATION_FILE)..."
+    PGPASSWORD=$DB_PASSWORD psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME -f $MIGRATION_FILE
+done
 
 echo "✅ Migration completed successfully!"